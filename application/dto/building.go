@@ -12,6 +12,11 @@ type Building struct {
 	Cost              float64
 	Count             int
 	TotalGenerateRate float64
+	IsMining          bool // TotalGenerateRate is in coins instead of money
+	TotalUpkeep       float64
+	Level             int
+	CanLevelUp        bool    // A higher tier exists and the building is unlocked
+	LevelUpCost       float64 // Lump sum to reach the next tier
 }
 
 func (b *Building) String() string {
@@ -19,14 +24,23 @@ func (b *Building) String() string {
 	if b.IsUnlocked {
 		locked = "Next"
 	}
-	return fmt.Sprintf(
-		"%s (%s, Cost: %s, Count: %d, Rate: %s/s)",
+	rate := formatter.FormatCurrency(b.TotalGenerateRate, "$")
+	if b.IsMining {
+		rate = formatter.FormatLargeNumber(b.TotalGenerateRate) + " coins"
+	}
+	text := fmt.Sprintf(
+		"%s (%s, Cost: %s, Count: %d, Rate: %s/s, Upkeep: %s/s)",
 		b.Name,
 		locked,
 		formatter.FormatCurrency(b.Cost, "$"),
 		b.Count,
-		formatter.FormatCurrency(b.TotalGenerateRate, "$"),
+		rate,
+		formatter.FormatCurrency(b.TotalUpkeep, "$"),
 	)
+	if b.CanLevelUp {
+		text += " [U] Level Up: " + formatter.FormatCurrency(b.LevelUpCost, "$")
+	}
+	return text
 }
 
 func (b *Building) GetName() string {
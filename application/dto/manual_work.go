@@ -7,12 +7,18 @@ import (
 )
 
 type ManualWork struct {
-	Name  string
-	Value float64
+	Name       string
+	Value      float64
+	Combo      int
+	Multiplier float64
 }
 
 func (m *ManualWork) String() string {
-	return fmt.Sprintf("%s: %s", m.Name, formatter.FormatCurrency(m.Value, "$"))
+	text := fmt.Sprintf("%s: %s", m.Name, formatter.FormatCurrency(m.Value, "$"))
+	if m.Combo > 1 {
+		text += fmt.Sprintf(" Combo: %d (x%.1f)", m.Combo, m.Multiplier)
+	}
+	return text
 }
 func (m *ManualWork) GetName() string {
 	return m.Name
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/kmdkuk/clicker/presentation/formatter"
+)
+
+type Market struct {
+	Price     float64
+	Coins     float64
+	History   []float64
+	Halvings  int
+	LastEvent string
+}
+
+func (m *Market) String() string {
+	text := fmt.Sprintf(
+		"Coin Price: %s (Holdings: %s coins, Halvings: %d)",
+		formatter.FormatCurrency(m.Price, "$"),
+		formatter.FormatLargeNumber(m.Coins),
+		m.Halvings,
+	)
+	if m.LastEvent != "" {
+		text += " " + m.LastEvent + "!"
+	}
+	return text
+}
+
+type MarketOrder struct {
+	Name   string
+	Amount float64
+	Value  float64
+}
+
+func (o *MarketOrder) String() string {
+	return fmt.Sprintf(
+		"%s (%s coins for %s)",
+		o.Name,
+		formatter.FormatLargeNumber(o.Amount),
+		formatter.FormatCurrency(o.Value, "$"),
+	)
+}
+
+func (o *MarketOrder) GetName() string {
+	return o.Name
+}
@@ -2,7 +2,10 @@ package dto
 
 type Player struct {
 	Money             float64
-	TotalGenerateRate float64
+	TotalGenerateRate float64 // Gross income per second
+	TotalUpkeep       float64 // Operating cost per second
+	Throttle          float64 // Share of capacity the buildings run at
+	IsTampered        bool    // The save was edited outside the game
 }
 
 func (p *Player) GetMoney() float64 {
@@ -11,3 +14,15 @@ func (p *Player) GetMoney() float64 {
 func (p *Player) GetTotalGenerateRate() float64 {
 	return p.TotalGenerateRate
 }
+func (p *Player) GetTotalUpkeep() float64 {
+	return p.TotalUpkeep
+}
+func (p *Player) GetNetRate() float64 {
+	return p.TotalGenerateRate - p.TotalUpkeep
+}
+func (p *Player) GetThrottle() float64 {
+	return p.Throttle
+}
+func (p *Player) GetIsTampered() bool {
+	return p.IsTampered
+}
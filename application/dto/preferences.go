@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type Preferences struct {
+	AutoSaveInterval time.Duration
+	NumberFormat     string
+	Theme            string
+	SoundVolume      int
+	ConfirmPurchases bool
+	DebugOverlay     bool
+	KeyBindings      map[string][]string
+}
@@ -0,0 +1,14 @@
+package dto
+
+type Slot struct {
+	Name string
+	Key  string
+}
+
+func (s *Slot) String() string {
+	return s.Name
+}
+
+func (s *Slot) GetName() string {
+	return s.Name
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"fmt"
+
 	"github.com/kmdkuk/clicker/application/dto"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 )
@@ -19,15 +21,26 @@ func (b *BuildingUseCase) GetBuildings() []dto.Building {
 	buildings := make([]dto.Building, len(b.gameState.GetBuildings()))
 	for i, building := range b.gameState.GetBuildings() {
 		genRate := building.BaseGenerateRate
+		upkeep := building.BaseUpkeep
 		if building.IsUnlocked() {
 			genRate = building.TotalGenerateRate(b.gameState.GetUpgrades())
+			upkeep = building.TotalUpkeep(b.gameState.GetUpgrades())
+		}
+		if building.IsMining {
+			genRate = b.gameState.GetMarket().ConvertToCoins(genRate)
 		}
+		nextTier, hasNextTier := building.NextTier()
 		buildings[i] = dto.Building{
-			Name:              building.Name,
+			Name:              building.DisplayName(),
 			IsUnlocked:        building.IsUnlocked(),
 			Count:             building.Count,
 			Cost:              building.Cost(),
 			TotalGenerateRate: genRate,
+			IsMining:          building.IsMining,
+			TotalUpkeep:       upkeep,
+			Level:             building.Level,
+			CanLevelUp:        hasNextTier && building.IsUnlocked(),
+			LevelUpCost:       nextTier.Cost,
 		}
 	}
 	return buildings
@@ -53,6 +66,7 @@ func (b *BuildingUseCase) GetBuildingsIsUnlockedWithMaskedNextLock() []dto.Build
 		} else {
 			// Mask the name of locked buildings as "???".
 			building.Name = "???"
+			building.CanLevelUp = false
 		}
 		buildingsInMaskedUnlock = append(buildingsInMaskedUnlock, building)
 	}
@@ -92,3 +106,50 @@ func (b *BuildingUseCase) PurchaseBuildingAction(buildingIndex int) (bool, strin
 
 	return true, "Building purchased successfully!"
 }
+
+// PurchaseMaxBuildingAction buys as many of the building as the money allows
+func (b *BuildingUseCase) PurchaseMaxBuildingAction(buildingIndex int) (bool, string) {
+	bought := 0
+	for {
+		success, message := b.PurchaseBuildingAction(buildingIndex)
+		if !success {
+			if bought == 0 {
+				return false, message
+			}
+			break
+		}
+		bought++
+	}
+	if bought == 1 {
+		return true, "Bought 1 building!"
+	}
+	return true, fmt.Sprintf("Bought %d buildings!", bought)
+}
+
+func (b *BuildingUseCase) LevelUpBuildingAction(buildingIndex int) (bool, string) {
+	buildings := b.gameState.GetBuildings()
+	if buildingIndex < 0 || buildingIndex >= len(buildings) {
+		return false, "Invalid building selection!"
+	}
+
+	building := &buildings[buildingIndex]
+	if !building.IsUnlocked() {
+		return false, "Unlock the building before leveling it up!"
+	}
+
+	tier, ok := building.NextTier()
+	if !ok {
+		return false, "Building is already at max level!"
+	}
+
+	if b.gameState.GetMoney() < tier.Cost {
+		return false, "Not enough money to level up!"
+	}
+
+	if err := b.gameState.SetBuildingLevel(buildingIndex, building.Level+1); err != nil {
+		return false, "Failed to level up building!"
+	}
+	b.gameState.UpdateMoney(-tier.Cost)
+
+	return true, "Building leveled up to " + tier.Name + "!"
+}
@@ -18,9 +18,9 @@ var _ = Describe("BuildingUseCase", func() {
 		gameState = &state.DefaultGameState{
 			Money: 1000,
 			Buildings: []model.Building{
-				{Name: "Building1", BaseCost: 100, Count: 2, BaseGenerateRate: 1.0},
+				{Name: "Building1", BaseCost: 100, Count: 2, BaseGenerateRate: 1.0, BaseUpkeep: 0.1},
 				{Name: "Building2", BaseCost: 200, Count: 1, BaseGenerateRate: 1.0},
-				{Name: "Building3", BaseCost: 300, Count: 0, BaseGenerateRate: 1.0},
+				{Name: "Building3", BaseCost: 300, Count: 0, BaseGenerateRate: 1.0, BaseUpkeep: 0.3},
 			},
 			Upgrades: []model.Upgrade{},
 		}
@@ -37,6 +37,18 @@ var _ = Describe("BuildingUseCase", func() {
 			Expect(building.Count).To(Equal(2))
 			Expect(building.Cost).To(BeNumerically("~", 100.0*1.15*1.15, 0.0001))
 			Expect(building.TotalGenerateRate).To(Equal(1.0 * 2))
+			Expect(building.IsMining).To(BeFalse())
+			Expect(building.TotalUpkeep).To(BeNumerically("~", 0.1*2, 0.0001))
+			// Locked buildings show the upkeep of a single unit
+			Expect(buildings[2].TotalUpkeep).To(Equal(0.3))
+		})
+
+		It("should return the coin rate for mining buildings", func() {
+			gameState.Buildings[0].IsMining = true
+			gameState.Market.Halvings = 1
+			building := useCase.GetBuildings()[0]
+			Expect(building.IsMining).To(BeTrue())
+			Expect(building.TotalGenerateRate).To(BeNumerically("~", 1.0*2/model.CoinBasePrice*0.5, 0.0001))
 		})
 	})
 
@@ -70,6 +82,79 @@ var _ = Describe("BuildingUseCase", func() {
 		})
 	})
 
+	Describe("PurchaseMaxBuildingAction", func() {
+		It("should buy as many buildings as the money allows", func() {
+			success, message := useCase.PurchaseMaxBuildingAction(0)
+			Expect(success).To(BeTrue())
+			Expect(message).To(Equal("Bought 5 buildings!"))
+			Expect(gameState.Buildings[0].Count).To(Equal(7))
+			Expect(gameState.Money).To(BeNumerically("<", gameState.Buildings[0].Cost()))
+		})
+
+		It("should fail if not even one building is affordable", func() {
+			gameState.Money = 10
+			success, message := useCase.PurchaseMaxBuildingAction(0)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("Not enough money to purchase!"))
+			Expect(gameState.Buildings[0].Count).To(Equal(2))
+		})
+	})
+
+	Describe("LevelUpBuildingAction", func() {
+		BeforeEach(func() {
+			for i := range gameState.Buildings {
+				gameState.Buildings[i].Tiers = []model.BuildingTier{
+					{Name: gameState.Buildings[i].Name, GenerateRateMultiplier: 1},
+					{Name: gameState.Buildings[i].Name + " mk2", GenerateRateMultiplier: 3, Cost: 500},
+				}
+			}
+		})
+
+		It("should level up a building and pay the lump sum", func() {
+			success, message := useCase.LevelUpBuildingAction(0)
+			Expect(success).To(BeTrue())
+			Expect(message).To(Equal("Building leveled up to Building1 mk2!"))
+			Expect(gameState.Buildings[0].Level).To(Equal(1))
+			Expect(gameState.Money).To(Equal(500.0))
+			Expect(useCase.GetBuildings()[0].Name).To(Equal("Building1 mk2"))
+			Expect(useCase.GetBuildings()[0].TotalGenerateRate).To(Equal(1.0 * 3 * 2))
+		})
+
+		It("should offer the level up only for unlocked buildings with a next tier", func() {
+			buildings := useCase.GetBuildings()
+			Expect(buildings[0].CanLevelUp).To(BeTrue())
+			Expect(buildings[0].LevelUpCost).To(Equal(500.0))
+			Expect(buildings[2].CanLevelUp).To(BeFalse())
+		})
+
+		It("should fail at max level", func() {
+			gameState.Buildings[0].Level = 1
+			success, message := useCase.LevelUpBuildingAction(0)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("Building is already at max level!"))
+		})
+
+		It("should fail for a locked building", func() {
+			success, message := useCase.LevelUpBuildingAction(2)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("Unlock the building before leveling it up!"))
+		})
+
+		It("should fail if not enough money", func() {
+			gameState.Money = 100
+			success, message := useCase.LevelUpBuildingAction(0)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("Not enough money to level up!"))
+			Expect(gameState.Buildings[0].Level).To(Equal(0))
+		})
+
+		It("should fail for an invalid building", func() {
+			success, message := useCase.LevelUpBuildingAction(3)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("Invalid building selection!"))
+		})
+	})
+
 	Describe("GetBuildingsIsUnlockedWithMaskedNextLock", func() {
 		Context("with some buildings unlocked and some locked", func() {
 			BeforeEach(func() {
@@ -0,0 +1,14 @@
+package usecase
+
+import "time"
+
+// Clock provides the current time so that time dependent use cases can be tested
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -2,29 +2,46 @@ package usecase
 
 import (
 	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 )
 
 func NewManualWorkUseCase(gameState state.GameState) *ManualWorkUseCase {
+	return NewManualWorkUseCaseWithClock(gameState, SystemClock{})
+}
+
+func NewManualWorkUseCaseWithClock(gameState state.GameState, clock Clock) *ManualWorkUseCase {
 	return &ManualWorkUseCase{
 		gameState: gameState,
+		clock:     clock,
 	}
 }
 
 type ManualWorkUseCase struct {
 	gameState state.GameState
+	clock     Clock
+	combo     model.Combo
 }
 
 // GetManualWork implements presentation.ManualWorkUseCase.
 func (m *ManualWorkUseCase) GetManualWork() *dto.ManualWork {
+	now := m.clock.Now()
 	value := m.gameState.GetManualWork().GetValue(m.gameState.GetUpgrades())
 	return &dto.ManualWork{
-		Name:  m.gameState.GetManualWork().Name,
-		Value: value,
+		Name:       m.gameState.GetManualWork().Name,
+		Value:      value,
+		Combo:      m.combo.CurrentStreak(now),
+		Multiplier: m.combo.Multiplier(now),
 	}
 }
 
 // ManualWorkAction implements presentation.ManualWorkUseCase.
+// Actions above the rate limit are ignored.
 func (m *ManualWorkUseCase) ManualWorkAction() {
-	m.gameState.UpdateMoney(m.gameState.GetManualWork().Work(m.gameState.GetUpgrades()))
+	now := m.clock.Now()
+	if !m.combo.Register(now) {
+		return
+	}
+	value := m.gameState.GetManualWork().Work(m.gameState.GetUpgrades())
+	m.gameState.UpdateMoney(value * m.combo.Multiplier(now))
 }
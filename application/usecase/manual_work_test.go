@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"time"
+
 	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 
@@ -8,9 +10,18 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("BuildingUseCase", func() {
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+var _ = Describe("ManualWorkUseCase", func() {
 	var (
 		gameState *state.DefaultGameState
+		clock     *fakeClock
 		useCase   *ManualWorkUseCase
 	)
 
@@ -33,7 +44,8 @@ var _ = Describe("BuildingUseCase", func() {
 				},
 			},
 		}
-		useCase = NewManualWorkUseCase(gameState)
+		clock = &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+		useCase = NewManualWorkUseCaseWithClock(gameState, clock)
 	})
 
 	Describe("GetManualWork", func() {
@@ -41,6 +53,19 @@ var _ = Describe("BuildingUseCase", func() {
 			manualWork := useCase.GetManualWork()
 			Expect(manualWork.Name).To(Equal("Manual Work"))
 			Expect(manualWork.Value).To(BeNumerically("~", 1*1.1, 0.0001))
+			Expect(manualWork.Combo).To(Equal(0))
+			Expect(manualWork.Multiplier).To(Equal(1.0))
+		})
+
+		It("should return the current combo", func() {
+			useCase.ManualWorkAction()
+			useCase.ManualWorkAction()
+			manualWork := useCase.GetManualWork()
+			Expect(manualWork.Combo).To(Equal(2))
+			Expect(manualWork.Multiplier).To(BeNumerically("~", 1.1, 0.0001))
+
+			clock.now = clock.now.Add(time.Minute)
+			Expect(useCase.GetManualWork().Combo).To(Equal(0))
 		})
 	})
 
@@ -50,5 +75,23 @@ var _ = Describe("BuildingUseCase", func() {
 			Expect(gameState.Money).To(BeNumerically("~", 1000+1*1.1, 0.0001))
 			Expect(gameState.ManualWork.Count).To(Equal(1))
 		})
+
+		It("should apply the combo multiplier", func() {
+			useCase.ManualWorkAction()
+			clock.now = clock.now.Add(100 * time.Millisecond)
+			useCase.ManualWorkAction()
+			Expect(gameState.Money).To(BeNumerically("~", 1000+1.1+1.1*1.1, 0.0001))
+		})
+
+		It("should ignore actions above the rate limit", func() {
+			for i := 0; i < model.MaxActionsPerSecond+5; i++ {
+				useCase.ManualWorkAction()
+			}
+			Expect(gameState.ManualWork.Count).To(Equal(model.MaxActionsPerSecond))
+
+			clock.now = clock.now.Add(time.Second)
+			useCase.ManualWorkAction()
+			Expect(gameState.ManualWork.Count).To(Equal(model.MaxActionsPerSecond + 1))
+		})
 	})
 })
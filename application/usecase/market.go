@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/infrastructure/state"
+)
+
+// marketOrders lists the sell orders offered on the market page as a share of the holdings
+var marketOrders = []struct {
+	name  string
+	ratio float64
+}{
+	{name: "Sell 10%", ratio: 0.1},
+	{name: "Sell Half", ratio: 0.5},
+	{name: "Sell All", ratio: 1.0},
+}
+
+func NewMarketUseCase(gameState state.GameState) *MarketUseCase {
+	return &MarketUseCase{
+		gameState: gameState,
+	}
+}
+
+type MarketUseCase struct {
+	gameState state.GameState
+}
+
+func (m *MarketUseCase) GetMarket() *dto.Market {
+	market := m.gameState.GetMarket()
+	return &dto.Market{
+		Price:     market.Price,
+		Coins:     m.gameState.GetCoins(),
+		History:   append([]float64(nil), market.History...),
+		Halvings:  market.Halvings,
+		LastEvent: market.LastEvent,
+	}
+}
+
+func (m *MarketUseCase) GetMarketOrders() []dto.MarketOrder {
+	price := m.gameState.GetMarket().Price
+	coins := m.gameState.GetCoins()
+	orders := make([]dto.MarketOrder, len(marketOrders))
+	for i, order := range marketOrders {
+		amount := coins * order.ratio
+		orders[i] = dto.MarketOrder{
+			Name:   order.name,
+			Amount: amount,
+			Value:  amount * price,
+		}
+	}
+	return orders
+}
+
+func (m *MarketUseCase) SellAction(cursor int) (bool, string) {
+	orders := m.GetMarketOrders()
+	if cursor < 0 || cursor >= len(orders) {
+		return false, "Invalid order selection!"
+	}
+
+	order := orders[cursor]
+	if order.Amount <= 0 {
+		return false, "No coins to sell!"
+	}
+
+	m.gameState.UpdateCoins(-order.Amount)
+	m.gameState.UpdateMoney(order.Value)
+
+	return true, "Coins sold successfully!"
+}
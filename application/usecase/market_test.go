@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/state"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MarketUseCase", func() {
+	var (
+		gameState *state.DefaultGameState
+		useCase   *MarketUseCase
+	)
+
+	BeforeEach(func() {
+		gameState = &state.DefaultGameState{
+			Money:  100,
+			Coins:  10,
+			Market: model.NewMarket(1),
+		}
+		gameState.Market.Price = 2.0
+		useCase = NewMarketUseCase(gameState)
+	})
+
+	Describe("GetMarket", func() {
+		It("should return the price and holdings", func() {
+			market := useCase.GetMarket()
+			Expect(market.Price).To(Equal(2.0))
+			Expect(market.Coins).To(Equal(10.0))
+			Expect(market.History).To(Equal(gameState.Market.History))
+		})
+	})
+
+	Describe("GetMarketOrders", func() {
+		It("should value the orders at the current price", func() {
+			orders := useCase.GetMarketOrders()
+			Expect(orders).To(HaveLen(3))
+			Expect(orders[0].Amount).To(BeNumerically("~", 1.0, 0.0001))
+			Expect(orders[0].Value).To(BeNumerically("~", 2.0, 0.0001))
+			Expect(orders[2].Amount).To(Equal(10.0))
+			Expect(orders[2].Value).To(Equal(20.0))
+		})
+	})
+
+	Describe("SellAction", func() {
+		It("should sell coins for money", func() {
+			success, message := useCase.SellAction(1)
+			Expect(success).To(BeTrue())
+			Expect(message).To(Equal("Coins sold successfully!"))
+			Expect(gameState.Coins).To(Equal(5.0))
+			Expect(gameState.Money).To(Equal(110.0))
+		})
+
+		It("should sell all coins", func() {
+			success, _ := useCase.SellAction(2)
+			Expect(success).To(BeTrue())
+			Expect(gameState.Coins).To(Equal(0.0))
+			Expect(gameState.Money).To(Equal(120.0))
+		})
+
+		It("should fail without coins", func() {
+			gameState.Coins = 0
+			success, message := useCase.SellAction(2)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("No coins to sell!"))
+		})
+
+		It("should fail for an invalid order", func() {
+			success, message := useCase.SellAction(3)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal("Invalid order selection!"))
+		})
+	})
+})
@@ -19,5 +19,8 @@ func (p *PlayerUseCase) GetPlayer() *dto.Player {
 	return &dto.Player{
 		Money:             p.gameState.GetMoney(),
 		TotalGenerateRate: p.gameState.GetTotalGenerateRate(),
+		TotalUpkeep:       p.gameState.GetTotalUpkeep(),
+		Throttle:          p.gameState.GetThrottle(),
+		IsTampered:        p.gameState.IsTampered(),
 	}
 }
@@ -18,7 +18,7 @@ var _ = Describe("PlayerUseCase", func() {
 		gameState = &state.DefaultGameState{
 			Money: 1000,
 			Buildings: []model.Building{
-				{Name: "Building1", BaseCost: 100, Count: 2, BaseGenerateRate: 1.0},
+				{Name: "Building1", BaseCost: 100, Count: 2, BaseGenerateRate: 1.0, BaseUpkeep: 0.1},
 				{Name: "Building2", BaseCost: 200, Count: 1, BaseGenerateRate: 1.0},
 				{Name: "Building3", BaseCost: 300, Count: 0, BaseGenerateRate: 1.0},
 			},
@@ -42,6 +42,14 @@ var _ = Describe("PlayerUseCase", func() {
 			player := useCase.GetPlayer()
 			Expect(player.Money).To(Equal(gameState.Money))
 			Expect(player.TotalGenerateRate).To(BeNumerically("~", gameState.GetTotalGenerateRate(), 0.0001))
+			Expect(player.TotalUpkeep).To(BeNumerically("~", 0.2, 0.0001))
+			Expect(player.GetNetRate()).To(BeNumerically("~", gameState.GetTotalGenerateRate()-0.2, 0.0001))
+			Expect(player.IsTampered).To(BeFalse())
+		})
+
+		It("should report an edited save", func() {
+			gameState.SetTampered(true)
+			Expect(useCase.GetPlayer().IsTampered).To(BeTrue())
 		})
 	})
 })
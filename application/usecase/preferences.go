@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+)
+
+// NewPreferencesUseCase manages the preferences loaded at startup.
+// The config is updated with every change, so that the game picks it up.
+func NewPreferencesUseCase(preferencesStorage storage.PreferencesStorage, config *config.Config, preferences model.Preferences) *PreferencesUseCase {
+	return NewPreferencesUseCaseWithOverrides(preferencesStorage, config, preferences, preferences)
+}
+
+// NewPreferencesUseCaseWithOverrides manages the stored preferences while the session uses
+// the overridden ones, e.g. by command line flags. Only the settings the player changes are stored,
+// so the overrides apply to this session only.
+func NewPreferencesUseCaseWithOverrides(preferencesStorage storage.PreferencesStorage, config *config.Config, stored, session model.Preferences) *PreferencesUseCase {
+	return &PreferencesUseCase{
+		storage:     preferencesStorage,
+		config:      config,
+		preferences: session,
+		stored:      stored,
+	}
+}
+
+type PreferencesUseCase struct {
+	storage     storage.PreferencesStorage
+	config      *config.Config
+	preferences model.Preferences // In use for this session
+	stored      model.Preferences // As stored, without the overrides of this session
+}
+
+func (p *PreferencesUseCase) GetPreferences() *dto.Preferences {
+	return &dto.Preferences{
+		AutoSaveInterval: p.preferences.AutoSaveInterval,
+		NumberFormat:     string(p.preferences.NumberFormat),
+		Theme:            p.preferences.Theme,
+		SoundVolume:      p.preferences.SoundVolume,
+		ConfirmPurchases: p.preferences.ConfirmPurchases,
+		DebugOverlay:     p.preferences.DebugOverlay,
+		KeyBindings:      cloneKeyBindings(p.preferences.KeyBindings),
+	}
+}
+
+// UpdatePreferencesAction applies the preferences to the game and stores them.
+// They stay applied for this session even if they cannot be stored.
+func (p *PreferencesUseCase) UpdatePreferencesAction(preferences dto.Preferences) (bool, string) {
+	updated := model.Preferences{
+		AutoSaveInterval: preferences.AutoSaveInterval,
+		NumberFormat:     model.NumberFormat(preferences.NumberFormat),
+		Theme:            preferences.Theme,
+		SoundVolume:      preferences.SoundVolume,
+		ConfirmPurchases: preferences.ConfirmPurchases,
+		DebugOverlay:     preferences.DebugOverlay,
+		KeyBindings:      cloneKeyBindings(preferences.KeyBindings),
+	}
+	check := updated
+	if check.Normalize() > 0 {
+		return false, "Invalid settings!"
+	}
+
+	// A change that cannot be stored now is stored with the next one
+	p.stored = storeChanges(p.stored, p.preferences, updated)
+	p.preferences = updated
+	ApplyPreferences(p.config, updated)
+	if err := p.storage.SavePreferences(p.stored); err != nil {
+		slog.Warn("Failed to save preferences", "error", err)
+		return false, "Failed to save settings! They apply until the game is closed."
+	}
+	return true, ""
+}
+
+// storeChanges returns the stored preferences with the settings that changed from before to after
+func storeChanges(stored, before, after model.Preferences) model.Preferences {
+	if after.AutoSaveInterval != before.AutoSaveInterval {
+		stored.AutoSaveInterval = after.AutoSaveInterval
+	}
+	if after.NumberFormat != before.NumberFormat {
+		stored.NumberFormat = after.NumberFormat
+	}
+	if after.Theme != before.Theme {
+		stored.Theme = after.Theme
+	}
+	if after.SoundVolume != before.SoundVolume {
+		stored.SoundVolume = after.SoundVolume
+	}
+	if after.ConfirmPurchases != before.ConfirmPurchases {
+		stored.ConfirmPurchases = after.ConfirmPurchases
+	}
+	if after.DebugOverlay != before.DebugOverlay {
+		stored.DebugOverlay = after.DebugOverlay
+	}
+	if !maps.EqualFunc(after.KeyBindings, before.KeyBindings, slices.Equal) {
+		stored.KeyBindings = cloneKeyBindings(after.KeyBindings)
+	}
+	return stored
+}
+
+// cloneKeyBindings copies the key bindings, so that the caller cannot change the stored ones
+func cloneKeyBindings(keyBindings map[string][]string) map[string][]string {
+	if keyBindings == nil {
+		return nil
+	}
+	cloned := make(map[string][]string, len(keyBindings))
+	for action, keys := range keyBindings {
+		cloned[action] = slices.Clone(keys)
+	}
+	return cloned
+}
+
+// ApplyPreferences copies the preferences that the config holds into the config
+func ApplyPreferences(c *config.Config, preferences model.Preferences) {
+	c.AutoSaveInterval = preferences.AutoSaveInterval
+	c.Theme = preferences.Theme
+	c.EnableDebug = preferences.DebugOverlay
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreferencesUseCase", func() {
+	var (
+		store   *driver.MemoryStore
+		faults  *driver.FaultDriver
+		cfg     *config.Config
+		useCase *PreferencesUseCase
+	)
+
+	BeforeEach(func() {
+		store = driver.NewMemoryStore()
+		faults = driver.NewFaultDriver(store.NewStorageDriver(config.PreferencesKey))
+		cfg = config.NewConfig()
+		useCase = NewPreferencesUseCase(storage.NewPreferencesStorage(faults), cfg, model.DefaultPreferences())
+	})
+
+	It("should return the preferences it was created with", func() {
+		p := useCase.GetPreferences()
+		Expect(p.AutoSaveInterval).To(Equal(config.DefaultAutoSaveInterval))
+		Expect(p.NumberFormat).To(Equal(string(model.NumberFormatShort)))
+	})
+
+	Describe("UpdatePreferencesAction", func() {
+		It("should apply the preferences to the config and store them", func() {
+			p := *useCase.GetPreferences()
+			p.AutoSaveInterval = time.Minute
+			p.Theme = "Light"
+			p.DebugOverlay = true
+			p.ConfirmPurchases = true
+
+			ok, message := useCase.UpdatePreferencesAction(p)
+			Expect(ok).To(BeTrue())
+			Expect(message).To(BeEmpty())
+			Expect(cfg.AutoSaveInterval).To(Equal(time.Minute))
+			Expect(cfg.Theme).To(Equal("Light"))
+			Expect(cfg.EnableDebug).To(BeTrue())
+			Expect(*useCase.GetPreferences()).To(Equal(p))
+
+			stored, err := storage.NewPreferencesStorage(store.NewStorageDriver(config.PreferencesKey)).LoadPreferences()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.ConfirmPurchases).To(BeTrue())
+			Expect(stored.AutoSaveInterval).To(Equal(time.Minute))
+		})
+
+		It("should reject invalid preferences", func() {
+			p := *useCase.GetPreferences()
+			p.SoundVolume = 101
+
+			ok, message := useCase.UpdatePreferencesAction(p)
+			Expect(ok).To(BeFalse())
+			Expect(message).To(Equal("Invalid settings!"))
+			Expect(useCase.GetPreferences().SoundVolume).To(Equal(model.MaxSoundVolume))
+			Expect(faults.Saves).To(Equal(0))
+		})
+
+		It("should keep the preferences for the session if they cannot be stored", func() {
+			faults.SaveError = errors.New("disk full")
+			p := *useCase.GetPreferences()
+			p.NumberFormat = string(model.NumberFormatFull)
+			p.AutoSaveInterval = 0
+
+			ok, message := useCase.UpdatePreferencesAction(p)
+			Expect(ok).To(BeFalse())
+			Expect(message).To(ContainSubstring("Failed to save settings!"))
+			Expect(useCase.GetPreferences().NumberFormat).To(Equal(string(model.NumberFormatFull)))
+			Expect(cfg.AutoSaveInterval).To(BeZero())
+		})
+	})
+
+	Context("with overrides for the session", func() {
+		BeforeEach(func() {
+			stored := model.DefaultPreferences()
+			stored.Theme = "Light"
+			session := stored
+			session.AutoSaveInterval = time.Second
+			session.Theme = "Dark"
+			session.DebugOverlay = true
+			useCase = NewPreferencesUseCaseWithOverrides(storage.NewPreferencesStorage(faults), cfg, stored, session)
+		})
+
+		It("should return the preferences of the session", func() {
+			Expect(useCase.GetPreferences().Theme).To(Equal("Dark"))
+			Expect(useCase.GetPreferences().AutoSaveInterval).To(Equal(time.Second))
+		})
+
+		It("should store only the settings the player changed", func() {
+			p := *useCase.GetPreferences()
+			p.NumberFormat = string(model.NumberFormatFull)
+			p.DebugOverlay = false
+
+			ok, _ := useCase.UpdatePreferencesAction(p)
+			Expect(ok).To(BeTrue())
+			Expect(cfg.Theme).To(Equal("Dark"))
+			Expect(cfg.AutoSaveInterval).To(Equal(time.Second))
+
+			stored, err := storage.NewPreferencesStorage(store.NewStorageDriver(config.PreferencesKey)).LoadPreferences()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.NumberFormat).To(Equal(model.NumberFormatFull))
+			Expect(stored.DebugOverlay).To(BeFalse())
+			Expect(stored.Theme).To(Equal("Light"))
+			Expect(stored.AutoSaveInterval).To(Equal(config.DefaultAutoSaveInterval))
+		})
+	})
+
+	It("should not change the preferences returned before", func() {
+		before := useCase.GetPreferences()
+		ok, _ := useCase.UpdatePreferencesAction(dto.Preferences{NumberFormat: string(model.NumberFormatFull)})
+		Expect(ok).To(BeTrue())
+		Expect(before.NumberFormat).To(Equal(string(model.NumberFormatShort)))
+	})
+
+	It("should keep the key bindings apart from the ones passed in and returned", func() {
+		keyBindings := map[string][]string{"save": {"F5"}}
+		ok, _ := useCase.UpdatePreferencesAction(dto.Preferences{NumberFormat: string(model.NumberFormatShort), KeyBindings: keyBindings})
+		Expect(ok).To(BeTrue())
+		keyBindings["save"][0] = "F6"
+		returned := useCase.GetPreferences()
+		returned.KeyBindings["save"] = nil
+		Expect(useCase.GetPreferences().KeyBindings).To(Equal(map[string][]string{"save": {"F5"}}))
+	})
+})
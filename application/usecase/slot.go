@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+)
+
+func NewSlotUseCase(slots storage.SlotManager) *SlotUseCase {
+	return &SlotUseCase{
+		slots: slots,
+	}
+}
+
+type SlotUseCase struct {
+	slots storage.SlotManager
+}
+
+func (s *SlotUseCase) GetSlots() []dto.Slot {
+	names, err := s.slots.ListSlots()
+	if err != nil {
+		return []dto.Slot{}
+	}
+	slots := make([]dto.Slot, len(names))
+	for i, name := range names {
+		slots[i] = dto.Slot{
+			Name: name,
+			Key:  s.slots.GetKeyName(name),
+		}
+	}
+	return slots
+}
+
+func (s *SlotUseCase) CreateSlotAction(name string) (bool, string) {
+	if err := s.slots.CreateSlot(name); err != nil {
+		return false, slotErrorMessage(err, "Failed to create slot!")
+	}
+	return true, "Slot created!"
+}
+
+// CopySlotAction copies the slot to a new slot named after it
+func (s *SlotUseCase) CopySlotAction(index int) (bool, string) {
+	slot, ok := s.getSlot(index)
+	if !ok {
+		return false, "Invalid slot selection!"
+	}
+	name := s.unusedName(slot.Name + "-copy")
+	if err := s.slots.CopySlot(slot.Name, name); err != nil {
+		return false, slotErrorMessage(err, "Failed to copy slot!")
+	}
+	return true, fmt.Sprintf("Slot copied to %s!", name)
+}
+
+func (s *SlotUseCase) RenameSlotAction(index int, name string) (bool, string) {
+	slot, ok := s.getSlot(index)
+	if !ok {
+		return false, "Invalid slot selection!"
+	}
+	if err := s.slots.RenameSlot(slot.Name, name); err != nil {
+		return false, slotErrorMessage(err, "Failed to rename slot!")
+	}
+	return true, "Slot renamed!"
+}
+
+func (s *SlotUseCase) DeleteSlotAction(index int) (bool, string) {
+	slot, ok := s.getSlot(index)
+	if !ok {
+		return false, "Invalid slot selection!"
+	}
+	if err := s.slots.DeleteSlot(slot.Name); err != nil {
+		return false, slotErrorMessage(err, "Failed to delete slot!")
+	}
+	return true, "Slot deleted!"
+}
+
+func (s *SlotUseCase) getSlot(index int) (dto.Slot, bool) {
+	slots := s.GetSlots()
+	if index < 0 || index >= len(slots) {
+		return dto.Slot{}, false
+	}
+	return slots[index], true
+}
+
+// unusedName returns the name, suffixed with a number if a slot with that name exists
+func (s *SlotUseCase) unusedName(name string) string {
+	used := map[string]bool{}
+	for _, slot := range s.GetSlots() {
+		used[slot.Name] = true
+	}
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	return candidate
+}
+
+func slotErrorMessage(err error, fallback string) string {
+	switch {
+	case errors.Is(err, storage.ErrInvalidSlotName):
+		return "Invalid slot name! Use letters, digits, '-' and '_'."
+	case errors.Is(err, storage.ErrSlotExists):
+		return "Slot already exists!"
+	case errors.Is(err, storage.ErrSlotNotFound):
+		return "Slot not found!"
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SlotUseCase", func() {
+	var useCase *SlotUseCase
+
+	slotNames := func() []string {
+		names := []string{}
+		for _, slot := range useCase.GetSlots() {
+			names = append(names, slot.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		useCase = NewSlotUseCase(storage.NewSlotManager(driver.NewKeyManager(dir), dir))
+		ok, _ := useCase.CreateSlotAction(config.DefaultSlot)
+		Expect(ok).To(BeTrue())
+	})
+
+	Describe("CreateSlotAction", func() {
+		It("should create a slot", func() {
+			ok, message := useCase.CreateSlotAction("balance")
+			Expect(ok).To(BeTrue())
+			Expect(message).To(Equal("Slot created!"))
+			Expect(slotNames()).To(Equal([]string{config.DefaultSlot, "balance"}))
+		})
+
+		It("should reject an invalid name", func() {
+			ok, message := useCase.CreateSlotAction("bad name")
+			Expect(ok).To(BeFalse())
+			Expect(message).To(ContainSubstring("Invalid slot name!"))
+		})
+
+		It("should reject an existing name", func() {
+			ok, message := useCase.CreateSlotAction(config.DefaultSlot)
+			Expect(ok).To(BeFalse())
+			Expect(message).To(Equal("Slot already exists!"))
+		})
+	})
+
+	Describe("CopySlotAction", func() {
+		It("should copy a slot to an unused name", func() {
+			ok, message := useCase.CopySlotAction(0)
+			Expect(ok).To(BeTrue())
+			Expect(message).To(Equal("Slot copied to default-copy!"))
+
+			ok, message = useCase.CopySlotAction(0)
+			Expect(ok).To(BeTrue())
+			Expect(message).To(Equal("Slot copied to default-copy-2!"))
+			Expect(slotNames()).To(Equal([]string{config.DefaultSlot, "default-copy", "default-copy-2"}))
+		})
+
+		It("should reject an invalid selection", func() {
+			ok, message := useCase.CopySlotAction(5)
+			Expect(ok).To(BeFalse())
+			Expect(message).To(Equal("Invalid slot selection!"))
+		})
+	})
+
+	Describe("RenameSlotAction", func() {
+		It("should rename a slot", func() {
+			ok, message := useCase.RenameSlotAction(0, "main")
+			Expect(ok).To(BeTrue())
+			Expect(message).To(Equal("Slot renamed!"))
+			Expect(slotNames()).To(Equal([]string{"main"}))
+		})
+	})
+
+	Describe("DeleteSlotAction", func() {
+		It("should delete a slot", func() {
+			ok, message := useCase.DeleteSlotAction(0)
+			Expect(ok).To(BeTrue())
+			Expect(message).To(Equal("Slot deleted!"))
+			Expect(slotNames()).To(BeEmpty())
+		})
+
+		It("should reject an invalid selection", func() {
+			ok, _ := useCase.DeleteSlotAction(-1)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
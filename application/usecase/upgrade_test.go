@@ -6,6 +6,7 @@ import (
 
 	"github.com/kmdkuk/clicker/application/usecase"
 	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/state"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -74,8 +75,37 @@ func (m *MockGameState) GetBuildings() []model.Building {
 func (m *MockGameState) SetBuildingCount(index int, count int) error {
 	return nil
 }
+func (m *MockGameState) SetBuildingLevel(index int, level int) error {
+	return nil
+}
 func (m *MockGameState) UpdateBuildings(_ time.Time) {
 }
+func (m *MockGameState) GetCoins() float64 {
+	return 0.0
+}
+func (m *MockGameState) UpdateCoins(amount float64) {
+}
+func (m *MockGameState) GetMarket() *model.Market {
+	return &model.Market{}
+}
+func (m *MockGameState) SetMarket(market model.Market) {
+}
+func (m *MockGameState) GetTotalUpkeep() float64 {
+	return 0.0
+}
+func (m *MockGameState) GetThrottle() float64 {
+	return 1.0
+}
+
+func (m *MockGameState) IsTampered() bool {
+	return false
+}
+
+func (m *MockGameState) SetTampered(tampered bool) {}
+
+func (m *MockGameState) Clone() state.GameState {
+	return m
+}
 
 var _ = Describe("UpgradeUseCase", func() {
 	var (
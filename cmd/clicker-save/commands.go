@@ -0,0 +1,427 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/game/level"
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+)
+
+var errInvalidSave = errors.New("save is invalid")
+
+const usage = `Usage: clicker-save <command> [flags] [file]
+
+Commands:
+  dump [file]              Print the save with building and upgrade names
+  validate [file]          Check the save like the game does when loading it
+  repair [file]            Recover and fix the save and write it back
+  diff <file> <file>       Compare two saves
+  set [file] key=value...  Edit fields for testing
+
+Keys for set:
+  money, coins, manual_work, tampered, buildings.<id>, building_levels.<id>, upgrade.<id>
+  The edited save is flagged as tampered unless tampered=false is given.
+
+Flags:
+`
+
+// command is a subcommand with the files it works on already resolved
+type command func(out io.Writer, files []string, args []string, output string) error
+
+var commands = map[string]command{
+	"dump":     dump,
+	"validate": validate,
+	"repair":   repair,
+	"diff":     diff,
+	"set":      set,
+}
+
+func run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("clicker-save", flag.ContinueOnError)
+	flags.SetOutput(out)
+	slot := flags.String("slot", config.DefaultSlot, "Save slot to use when no file is given")
+	saveDir := flags.String("save-dir", "", "Directory the game stores saves in (default: $"+config.SaveDirEnv+" or the user config directory)")
+	output := flags.StringP("output", "o", "", "File to write to for repair and set (default: overwrite the input)")
+	flags.Usage = func() {
+		fmt.Fprint(out, usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing command")
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("unknown command: %s", flags.Arg(0))
+	}
+
+	// Arguments with "=" are fields for set, the rest are files
+	files, fields := []string{}, []string{}
+	for _, arg := range flags.Args()[1:] {
+		if strings.Contains(arg, "=") {
+			fields = append(fields, arg)
+		} else {
+			files = append(files, arg)
+		}
+	}
+	if len(files) == 0 {
+		file, err := defaultFile(*saveDir, *slot)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+	}
+	return cmd(out, files, fields, *output)
+}
+
+// defaultFile returns the save of the slot where the game stores it
+func defaultFile(saveDir, slot string) (string, error) {
+	if !config.IsValidSlotName(slot) {
+		return "", fmt.Errorf("invalid slot name: %q", slot)
+	}
+	dir, err := driver.ResolveSaveDir(saveDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, config.SaveKeyForSlot(slot)), nil
+}
+
+func readSave(file string) (storage.Save, storage.Integrity, error) {
+	data, err := driver.NewStorageDriver(file).LoadData()
+	if err != nil {
+		return storage.Save{}, storage.IntegrityCorrupted, err
+	}
+	return storage.ReadSave(data)
+}
+
+func writeSave(file string, save storage.Save) error {
+	data, err := storage.EncodeSave(save)
+	if err != nil {
+		return err
+	}
+	return driver.NewStorageDriver(file).SaveData(data)
+}
+
+func dump(out io.Writer, files []string, _ []string, _ string) error {
+	for _, file := range files {
+		save, integrity, err := readSave(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		describe(out, file, save, integrity)
+	}
+	return nil
+}
+
+// describe prints the save with the names the game shows instead of bare indexes
+func describe(out io.Writer, file string, save storage.Save, integrity storage.Integrity) {
+	fmt.Fprintf(out, "File:        %s\n", file)
+	fmt.Fprintf(out, "Integrity:   %s\n", integrity)
+	fmt.Fprintf(out, "Version:     %d\n", save.Version)
+	fmt.Fprintf(out, "Money:       %s\n", strconv.FormatFloat(save.Money, 'f', -1, 64))
+	fmt.Fprintf(out, "Coins:       %s\n", strconv.FormatFloat(save.Coins, 'f', -1, 64))
+	fmt.Fprintf(out, "Manual work: %d\n", save.ManualWork)
+	fmt.Fprintf(out, "Tampered:    %t\n", save.Tampered)
+	if save.Market != nil {
+		fmt.Fprintf(out, "Market:      price %s, %d ticks, %d halvings\n", strconv.FormatFloat(save.Market.Price, 'f', -1, 64), save.Market.Ticks, save.Market.Halvings)
+	}
+
+	fmt.Fprintln(out, "Buildings:")
+	for _, key := range buildingKeys(save) {
+		fmt.Fprintf(out, "  %-24s %-30s count %d, level %d\n", key, buildingName(save, key), save.Buildings[key], save.BuildingLevels[key])
+	}
+
+	fmt.Fprintln(out, "Purchased upgrades:")
+	names := upgradeNames()
+	for _, u := range save.Upgradings {
+		if !u.IsPurchased {
+			continue
+		}
+		name, ok := names[u.ID]
+		if !ok {
+			name = "(unknown upgrade)"
+		}
+		fmt.Fprintf(out, "  %-20s %s\n", u.ID, name)
+	}
+}
+
+// buildingKeys returns the buildings of the game in order, followed by the unknown ones in the saves
+func buildingKeys(saves ...storage.Save) []string {
+	var keys []string
+	known := map[string]bool{}
+	for _, b := range level.NewBuildings() {
+		keys = append(keys, b.Key)
+		known[b.Key] = true
+	}
+	var unknown []string
+	for _, save := range saves {
+		for _, values := range []map[string]int{save.Buildings, save.BuildingLevels} {
+			for key := range values {
+				if !known[key] {
+					unknown = append(unknown, key)
+					known[key] = true
+				}
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return append(keys, unknown...)
+}
+
+func isKnownBuilding(key string) bool {
+	for _, b := range level.NewBuildings() {
+		if b.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// buildingName returns the tier name of the building in the save
+func buildingName(save storage.Save, key string) string {
+	for _, b := range level.NewBuildings() {
+		if b.Key == key {
+			b.Level = save.BuildingLevels[key]
+			return b.DisplayName()
+		}
+	}
+	return "(unknown building)"
+}
+
+func upgradeNames() map[string]string {
+	names := map[string]string{}
+	for _, u := range level.NewUpgrades() {
+		names[u.ID] = u.Name
+	}
+	return names
+}
+
+func validate(out io.Writer, files []string, _ []string, _ string) error {
+	invalid := false
+	for _, file := range files {
+		save, integrity, err := readSave(file)
+		if err == nil {
+			err = save.Validation()
+		}
+		switch {
+		case err != nil:
+			invalid = true
+			fmt.Fprintf(out, "%s: invalid (%s): %v\n", file, integrity, err)
+		case integrity == storage.IntegrityEdited:
+			fmt.Fprintf(out, "%s: valid, but edited outside the game\n", file)
+		case len(save.UnknownContent()) > 0:
+			fmt.Fprintf(out, "%s: valid, but the game does not know %s\n", file, strings.Join(save.UnknownContent(), ", "))
+		default:
+			fmt.Fprintf(out, "%s: valid (%s)\n", file, integrity)
+		}
+	}
+	if invalid {
+		return errInvalidSave
+	}
+	return nil
+}
+
+// repair recovers and fixes the save like the game does when loading it.
+// The original is kept as <file>.repair.bak when it is overwritten.
+func repair(out io.Writer, files []string, _ []string, output string) error {
+	if len(files) != 1 {
+		return errors.New("repair takes one file")
+	}
+	file := files[0]
+	data, err := driver.NewStorageDriver(file).LoadData()
+	if err != nil {
+		return err
+	}
+	save, err := storage.RepairSave(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w (restore a backup instead)", file, err)
+	}
+	if output == "" {
+		output = file
+		if err := driver.NewStorageDriver(file + ".repair.bak").SaveData(data); err != nil {
+			return fmt.Errorf("failed to keep the original: %w", err)
+		}
+		fmt.Fprintf(out, "Kept the original as %s.repair.bak\n", file)
+	}
+	if err := writeSave(output, save); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote the repaired save to %s\n", output)
+	return nil
+}
+
+func diff(out io.Writer, files []string, _ []string, _ string) error {
+	if len(files) != 2 {
+		return errors.New("diff takes two files")
+	}
+	a, _, err := readSave(files[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", files[0], err)
+	}
+	b, _, err := readSave(files[1])
+	if err != nil {
+		return fmt.Errorf("%s: %w", files[1], err)
+	}
+	lines := diffSaves(a, b)
+	if len(lines) == 0 {
+		fmt.Fprintln(out, "The saves are the same")
+		return nil
+	}
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+// diffSaves returns one line for each field that differs, as "field: a -> b"
+func diffSaves(a, b storage.Save) []string {
+	lines := []string{}
+	add := func(field string, from, to any) {
+		if fmt.Sprint(from) != fmt.Sprint(to) {
+			lines = append(lines, fmt.Sprintf("%s: %v -> %v", field, from, to))
+		}
+	}
+	add("money", a.Money, b.Money)
+	add("coins", a.Coins, b.Coins)
+	add("manual_work", a.ManualWork, b.ManualWork)
+	add("tampered", a.Tampered, b.Tampered)
+	if a.Market != nil && b.Market != nil {
+		add("market.price", a.Market.Price, b.Market.Price)
+	} else if (a.Market == nil) != (b.Market == nil) {
+		lines = append(lines, "market: only in one save")
+	}
+
+	for _, key := range buildingKeys(a, b) {
+		name := buildingName(a, key)
+		add(fmt.Sprintf("buildings.%s (%s)", key, name), a.Buildings[key], b.Buildings[key])
+		add(fmt.Sprintf("building_levels.%s (%s)", key, name), a.BuildingLevels[key], b.BuildingLevels[key])
+	}
+
+	purchased := func(save storage.Save) map[string]bool {
+		m := map[string]bool{}
+		for _, u := range save.Upgradings {
+			m[u.ID] = u.IsPurchased
+		}
+		return m
+	}
+	purchasedA, purchasedB := purchased(a), purchased(b)
+	names := upgradeNames()
+	for _, u := range level.NewUpgrades() {
+		add(fmt.Sprintf("upgrade.%s (%s)", u.ID, names[u.ID]), purchasedA[u.ID], purchasedB[u.ID])
+	}
+	return lines
+}
+
+// set edits fields of the save. The result is signed like a save written by the game, but is
+// flagged as tampered like a save edited by hand unless tampered=false is set explicitly.
+func set(out io.Writer, files []string, fields []string, output string) error {
+	if len(files) != 1 {
+		return errors.New("set takes one file")
+	}
+	if len(fields) == 0 {
+		return errors.New("set needs at least one key=value")
+	}
+	save, _, err := readSave(files[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", files[0], err)
+	}
+	// An edit made outside the game must not pass for a clean save
+	save.Tampered = true
+	for _, field := range fields {
+		key, value, _ := strings.Cut(field, "=")
+		if err := setField(&save, key, value); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	if err := save.Validation(); err != nil {
+		return fmt.Errorf("the edited save would not load: %w", err)
+	}
+	if output == "" {
+		output = files[0]
+	}
+	if err := writeSave(output, save); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s\n", output)
+	return nil
+}
+
+func setField(save *storage.Save, key, value string) error {
+	name, id, _ := strings.Cut(key, ".")
+	switch name {
+	case "money":
+		return parseFloat(value, &save.Money)
+	case "coins":
+		return parseFloat(value, &save.Coins)
+	case "manual_work":
+		return parseInt(value, &save.ManualWork)
+	case "tampered":
+		v, err := strconv.ParseBool(value)
+		save.Tampered = v
+		return err
+	case "buildings":
+		return setBuilding(&save.Buildings, id, value)
+	case "building_levels":
+		return setBuilding(&save.BuildingLevels, id, value)
+	case "upgrade":
+		if _, ok := upgradeNames()[id]; !ok {
+			return fmt.Errorf("unknown upgrade: %q", id)
+		}
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		save.SetUpgradePurchased(id, v)
+		return nil
+	default:
+		return fmt.Errorf("unknown key: %q", key)
+	}
+}
+
+// setBuilding sets the value of the building with the given ID
+func setBuilding(values *map[string]int, id, value string) error {
+	if !isKnownBuilding(id) {
+		return fmt.Errorf("unknown building: %q", id)
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if *values == nil {
+		*values = map[string]int{}
+	}
+	(*values)[id] = v
+	return nil
+}
+
+func parseFloat(value string, to *float64) error {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*to = v
+	return nil
+}
+
+func parseInt(value string, to *int) error {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*to = v
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("clicker-save", func() {
+	var (
+		dir  string
+		file string
+		out  *bytes.Buffer
+	)
+
+	runCLI := func(args ...string) error {
+		out.Reset()
+		return run(args, out)
+	}
+	writeFile := func(name string, save storage.Save) string {
+		path := filepath.Join(dir, name)
+		Expect(writeSave(path, save)).To(Succeed())
+		return path
+	}
+	load := func(path string) storage.Save {
+		save, _, err := readSave(path)
+		Expect(err).NotTo(HaveOccurred())
+		return save
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		out = &bytes.Buffer{}
+		save := storage.Save{Version: storage.CurrentSaveVersion, Money: 12.5, Buildings: map[string]int{"cpu_miner": 3, "gpu_rig": 1}, BuildingLevels: map[string]int{"cpu_miner": 1}}
+		save.SetUpgradePurchased("cpu_miner_0", true)
+		file = writeFile("game_state.json", save)
+	})
+
+	It("should dump the save with names", func() {
+		Expect(runCLI("dump", file)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("Money:       12.5"))
+		Expect(out.String()).To(MatchRegexp(`cpu_miner\s+CPU Miner mk2\s+count 3, level 1`))
+		Expect(out.String()).To(MatchRegexp(`cpu_miner_0\s+CPU Miner Upgrade 1`))
+	})
+
+	It("should use the save of the slot in the save directory", func() {
+		Expect(runCLI("dump", "--save-dir", dir)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(file))
+
+		Expect(runCLI("dump", "--save-dir", dir, "--slot", "missing")).NotTo(Succeed())
+	})
+
+	Describe("validate", func() {
+		It("should accept a valid save", func() {
+			Expect(runCLI("validate", file)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("valid (valid)"))
+		})
+
+		It("should reject an invalid save", func() {
+			broken := writeFile("broken.json", storage.Save{Version: storage.CurrentSaveVersion, Money: -1})
+			Expect(runCLI("validate", broken)).To(MatchError(errInvalidSave))
+			Expect(out.String()).To(ContainSubstring("invalid money value"))
+		})
+
+		It("should point out content the game does not know", func() {
+			save := load(file)
+			save.Buildings["removed_miner"] = 2
+			unknown := writeFile("unknown.json", save)
+			Expect(runCLI("validate", unknown)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("does not know building removed_miner"))
+		})
+	})
+
+	Describe("repair", func() {
+		It("should fix the save and keep the original", func() {
+			broken := writeFile("broken.json", storage.Save{Version: storage.CurrentSaveVersion, Money: -1, ManualWork: 4})
+
+			Expect(runCLI("repair", broken)).To(Succeed())
+			repaired := load(broken)
+			Expect(repaired.Validation()).To(Succeed())
+			Expect(repaired.ManualWork).To(Equal(4))
+			Expect(load(broken + ".repair.bak").Money).To(Equal(-1.0))
+		})
+
+		It("should write to another file", func() {
+			output := filepath.Join(dir, "out.json")
+			Expect(runCLI("repair", file, "-o", output)).To(Succeed())
+			Expect(load(output).Money).To(Equal(12.5))
+			Expect(file + ".repair.bak").NotTo(BeAnExistingFile())
+		})
+
+		It("should refuse data that cannot be recovered", func() {
+			Expect(os.WriteFile(file, []byte(`{"money":`), 0644)).To(Succeed())
+			Expect(runCLI("repair", file)).To(MatchError(ContainSubstring("restore a backup")))
+		})
+	})
+
+	It("should diff two saves", func() {
+		other := load(file)
+		other.Money = 20
+		other.Buildings["gpu_rig"] = 2
+		other.SetUpgradePurchased("cpu_miner_0", false)
+		otherFile := writeFile("other.json", other)
+
+		Expect(runCLI("diff", file, otherFile)).To(Succeed())
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(ConsistOf(
+			"money: 12.5 -> 20",
+			"buildings.gpu_rig (GPU Rig): 1 -> 2",
+			"upgrade.cpu_miner_0 (CPU Miner Upgrade 1): true -> false",
+		))
+
+		Expect(runCLI("diff", file, file)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("The saves are the same"))
+	})
+
+	Describe("set", func() {
+		It("should edit the fields", func() {
+			Expect(runCLI("set", file, "money=1000", "buildings.staking_pool=2", "upgrade.cpu_miner_1=true", "tampered=true")).To(Succeed())
+
+			save := load(file)
+			Expect(save.Money).To(Equal(1000.0))
+			Expect(save.Buildings).To(Equal(map[string]int{"cpu_miner": 3, "gpu_rig": 1, "staking_pool": 2}))
+			Expect(save.Tampered).To(BeTrue())
+			Expect(save.Upgradings).To(ContainElement(HaveField("ID", "cpu_miner_1")))
+		})
+
+		It("should flag the edited save as tampered", func() {
+			Expect(load(file).Tampered).To(BeFalse())
+			Expect(runCLI("set", file, "money=1e99")).To(Succeed())
+			Expect(load(file).Tampered).To(BeTrue())
+
+			// Only an explicit tampered=false clears the flag
+			Expect(runCLI("set", file, "money=10", "tampered=false")).To(Succeed())
+			Expect(load(file).Tampered).To(BeFalse())
+		})
+
+		It("should reject unknown keys and saves that would not load", func() {
+			Expect(runCLI("set", file, "gems=1")).To(MatchError(ContainSubstring("unknown key")))
+			Expect(runCLI("set", file, "buildings.4=2")).To(MatchError(ContainSubstring("unknown building")))
+			Expect(runCLI("set", file, "money=-1")).To(MatchError(ContainSubstring("would not load")))
+			Expect(load(file).Money).To(Equal(12.5))
+		})
+	})
+
+	It("should fail on an unknown command", func() {
+		Expect(runCLI("explode")).To(MatchError(ContainSubstring("unknown command")))
+	})
+})
@@ -0,0 +1,22 @@
+// clicker-save inspects and repairs save files.
+//
+//	clicker-save dump [file]             Print the save with building and upgrade names
+//	clicker-save validate [file]         Check the save like the game does when loading it
+//	clicker-save repair [file]           Recover and fix the save and write it back
+//	clicker-save diff <file> <file>      Compare two saves
+//	clicker-save set [file] key=value... Edit fields for testing
+//
+// The file defaults to the save of --slot in the save directory of the game.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
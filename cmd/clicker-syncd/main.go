@@ -0,0 +1,32 @@
+// clicker-syncd is a reference sync server that stores saves on disk.
+// Run it and start the game with --sync-url to play the same save on several machines and browsers.
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/kmdkuk/clicker/config"
+)
+
+func main() {
+	var addr, dir, token string
+	flag.StringVar(&addr, "addr", ":8080", "Address to listen on")
+	flag.StringVar(&dir, "dir", "saves", "Directory to store saves in")
+	flag.StringVar(&token, "token", "", "Bearer token clients must send (default: $"+config.SyncTokenEnv+", empty allows anyone)")
+	flag.Parse()
+	if token == "" {
+		token = os.Getenv(config.SyncTokenEnv)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Serving saves in %s on %s", dir, addr)
+	if err := http.ListenAndServe(addr, newServer(dir, token)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+)
+
+// maxSaveSize limits the request body so that a client cannot fill the disk with one request
+const maxSaveSize = 1 << 20
+
+// server stores the saves in dir, with the time each save was written next to it in <name>.saved-at
+type server struct {
+	dir   string
+	token string
+	mu    sync.Mutex // Makes the compare and write of PUT atomic
+}
+
+func newServer(dir, token string) *server {
+	return &server{
+		dir:   dir,
+		token: token,
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The browser build calls the server from another origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, "+driver.SavedAtHeader)
+	w.Header().Set("Access-Control-Expose-Headers", driver.SavedAtHeader)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, driver.SyncSavesPath)
+	// Only save keys are accepted, which also keeps the path inside dir
+	if _, ok := config.SlotFromSaveKey(name); !ok || !strings.HasPrefix(r.URL.Path, driver.SyncSavesPath) {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.get(w, name)
+	case http.MethodPut:
+		s.put(w, r, name)
+	case http.MethodDelete:
+		s.delete(w, name)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+func (s *server) get(w http.ResponseWriter, name string) {
+	s.mu.Lock()
+	data, savedAt, err := s.load(name)
+	s.mu.Unlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		http.NotFound(w, nil)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load %s: %v", name, err)
+		http.Error(w, "failed to load save", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(driver.SavedAtHeader, savedAt.UTC().Format(time.RFC3339Nano))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// put stores the save unless the stored one was written later (last writer wins)
+func (s *server) put(w http.ResponseWriter, r *http.Request, name string) {
+	savedAt, err := time.Parse(time.RFC3339Nano, r.Header.Get(driver.SavedAtHeader))
+	if err != nil {
+		http.Error(w, "invalid "+driver.SavedAtHeader+" header", http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSaveSize))
+	if err != nil || len(data) == 0 {
+		http.Error(w, "invalid save", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, stored, err := s.load(name); err == nil && stored.After(savedAt) {
+		http.Error(w, "a newer save exists", http.StatusConflict)
+		return
+	}
+	if err := s.store(name, data, savedAt); err != nil {
+		log.Printf("Failed to store %s: %v", name, err)
+		http.Error(w, "failed to store save", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(driver.SavedAtHeader, savedAt.UTC().Format(time.RFC3339Nano))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete removes the save, e.g. when its slot was deleted or renamed in the game
+func (s *server) delete(w http.ResponseWriter, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range []string{s.path(name), s.path(name) + ".saved-at"} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Failed to delete %s: %v", name, err)
+			http.Error(w, "failed to delete save", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) load(name string) ([]byte, time.Time, error) {
+	data, err := driver.NewStorageDriver(s.path(name)).LoadData()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	meta, err := driver.NewStorageDriver(s.path(name) + ".saved-at").LoadData()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	savedAt, err := time.Parse(time.RFC3339Nano, string(meta))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, savedAt, nil
+}
+
+// store writes the save before its time, so a crash in between leaves the save with an older time
+// that any later PUT can overwrite, rather than refusing later saves for a save that was never written
+func (s *server) store(name string, data []byte, savedAt time.Time) error {
+	if err := driver.NewStorageDriver(s.path(name)).SaveData(data); err != nil {
+		return err
+	}
+	return driver.NewStorageDriver(s.path(name) + ".saved-at").SaveData([]byte(savedAt.UTC().Format(time.RFC3339Nano)))
+}
+
+func (s *server) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		ts *httptest.Server
+		// Two machines playing the same slot, each with its own local save
+		desktop, browser driver.StorageDriver
+		desktopDir       string
+	)
+
+	newClient := func(dir, endpoint string) driver.StorageDriver {
+		return driver.NewSyncDriver(driver.NewStorageDriver(filepath.Join(dir, "game_state.json")), endpoint, "secret")
+	}
+	// save and load wait for the pushes, which are made in the background
+	save := func(d driver.StorageDriver, data string) {
+		Expect(d.SaveData([]byte(data))).To(Succeed())
+		driver.Flush(d)
+	}
+	load := func(d driver.StorageDriver) string {
+		data, err := d.LoadData()
+		Expect(err).NotTo(HaveOccurred())
+		driver.Flush(d)
+		return string(data)
+	}
+	put := func(path, savedAt, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodPut, ts.URL+path, strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set(driver.SavedAtHeader, savedAt)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		return resp
+	}
+
+	BeforeEach(func() {
+		ts = httptest.NewServer(newServer(GinkgoT().TempDir(), "secret"))
+		DeferCleanup(ts.Close)
+		desktopDir = GinkgoT().TempDir()
+		desktop = newClient(desktopDir, ts.URL)
+		browser = newClient(GinkgoT().TempDir(), ts.URL)
+	})
+
+	It("should share a save between machines", func() {
+		save(desktop, `{"money":10}`)
+		Expect(load(browser)).To(Equal(`{"money":10}`))
+
+		// The pulled save is kept locally for playing offline
+		Expect(load(driver.NewStorageDriver(browser.GetKeyName()))).To(Equal(`{"money":10}`))
+	})
+
+	It("should keep the save written last", func() {
+		save(desktop, `{"money":10}`)
+		save(browser, `{"money":20}`)
+
+		Expect(load(desktop)).To(Equal(`{"money":20}`))
+		Expect(load(browser)).To(Equal(`{"money":20}`))
+	})
+
+	It("should fall back to the local save and push it once the server is back", func() {
+		save(desktop, `{"money":10}`)
+
+		offline := newClient(desktopDir, "http://127.0.0.1:1")
+		save(offline, `{"money":30}`)
+		Expect(load(offline)).To(Equal(`{"money":30}`))
+
+		// The local save is newer than the remote one, so loading online pushes it
+		Expect(load(desktop)).To(Equal(`{"money":30}`))
+		Expect(load(browser)).To(Equal(`{"money":30}`))
+	})
+
+	It("should refuse a save older than the stored one", func() {
+		now := time.Now().UTC()
+		Expect(put("/saves/game_state.json", now.Format(time.RFC3339Nano), "secret").StatusCode).To(Equal(http.StatusNoContent))
+		Expect(put("/saves/game_state.json", now.Add(-time.Minute).Format(time.RFC3339Nano), "secret").StatusCode).To(Equal(http.StatusConflict))
+		Expect(put("/saves/game_state.json", now.Add(time.Minute).Format(time.RFC3339Nano), "secret").StatusCode).To(Equal(http.StatusNoContent))
+	})
+
+	It("should delete a save", func() {
+		save(desktop, `{"money":10}`)
+		Expect(driver.DeleteRemoteSave(desktop.GetKeyName(), ts.URL, "secret")).To(Succeed())
+
+		// A new game in a slot of the same name does not pull the deleted save
+		fresh := newClient(GinkgoT().TempDir(), ts.URL)
+		_, err := fresh.LoadData()
+		Expect(err).To(MatchError(fs.ErrNotExist))
+
+		// Deleting a missing save is not an error
+		Expect(driver.DeleteRemoteSave(desktop.GetKeyName(), ts.URL, "secret")).To(Succeed())
+	})
+
+	It("should reject requests without the token", func() {
+		Expect(put("/saves/game_state.json", time.Now().Format(time.RFC3339Nano), "wrong").StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should only accept save keys", func() {
+		now := time.Now().Format(time.RFC3339Nano)
+		Expect(put("/saves/..%2Fescape.json", now, "secret").StatusCode).To(Equal(http.StatusNotFound))
+		Expect(put("/saves/notes.txt", now, "secret").StatusCode).To(Equal(http.StatusNotFound))
+		Expect(put("/other/game_state.json", now, "secret").StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
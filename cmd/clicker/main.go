@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
-	"time"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	flag "github.com/spf13/pflag"
 
 	"github.com/kmdkuk/clicker/application/usecase"
 	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/game"
+	"github.com/kmdkuk/clicker/infrastructure/logging"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 	"github.com/kmdkuk/clicker/infrastructure/storage"
 	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
@@ -20,12 +27,149 @@ import (
 
 func main() {
 	cfg := config.NewConfig()
+	var slot string
 	flag.BoolVarP(&cfg.EnableDebug, "debug", "d", false, "Enable debug mode")
+	flag.StringVar(&slot, "slot", "", "Save slot to play (the slot picker is shown if empty)")
+	flag.IntVar(&cfg.BackupRetention, "backups", cfg.BackupRetention, "Number of rotating save backups to keep (0 disables backups)")
+	flag.DurationVar(&cfg.AutoSaveInterval, "autosave", cfg.AutoSaveInterval, "Interval between auto-saves in whole seconds (0 saves only on exit)")
+	flag.StringVar(&cfg.SaveDir, "save-dir", "", "Directory to store saves in (default: $"+config.SaveDirEnv+" or the user config directory)")
+	flag.StringVar(&cfg.SyncURL, "sync-url", pageParam("sync"), "Sync server to share saves with other machines (e.g. http://localhost:8080)")
+	flag.StringVar(&cfg.SyncToken, "sync-token", "", "Token for the sync server (default: $"+config.SyncTokenEnv+")")
+	flag.BoolVar(&cfg.Journal, "journal", false, "Append changes to a journal and save after every action")
+	flag.IntVar(&cfg.JournalCompact, "journal-compact", cfg.JournalCompact, "Number of journal records before they are compacted into the save")
+	flag.StringVar(&cfg.LogFile, "log-file", "", "File to append the log to (default: stderr)")
+	flag.StringVar(&cfg.Theme, "theme", "", "Built-in theme (dark, light, high-contrast) or path to a theme file")
 	flag.Parse()
+	if !config.IsValidAutoSaveInterval(cfg.AutoSaveInterval) {
+		log.Fatalf("invalid auto-save interval: %s (use 0 or whole seconds, e.g. 10s)", cfg.AutoSaveInterval)
+	}
+
+	saveDir, err := driver.ResolveSaveDir(cfg.SaveDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.SaveDir = saveDir
+	// 設定はセーブとは別にこのマシンに保存する。ログの設定にも使うので最初に読む
+	preferencesStorage := storage.NewPreferencesStorage(driver.NewStorageDriver(filepath.Join(cfg.SaveDir, config.PreferencesKey)))
+	stored, preferencesErr := preferencesStorage.LoadPreferences()
+	preferences := overridePreferences(stored, cfg)
+	usecase.ApplyPreferences(cfg, preferences)
+
+	logOverlay, closeLog, err := logging.Setup(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeLog()
+	if preferencesErr != nil {
+		slog.Warn("Using the default settings", "error", preferencesErr)
+	}
+	preferencesUseCase := usecase.NewPreferencesUseCaseWithOverrides(preferencesStorage, cfg, stored, preferences)
+	bindings, err := input.NewBindings(preferences.KeyBindings)
+	if err != nil {
+		slog.Warn("Skipped invalid key bindings", "error", err)
+	}
+	if cfg.SyncToken == "" {
+		cfg.SyncToken = os.Getenv(config.SyncTokenEnv)
+	}
+	if cfg.SyncToken == "" {
+		cfg.SyncToken = pageParam("token")
+	}
+	if slot != "" && !config.IsValidSlotName(slot) {
+		log.Fatalf("invalid slot name: %q", slot)
+	}
+
+	// 以前のバージョンはカレントディレクトリに保存していたので、初回起動時に移動する
+	moved, err := storage.MigrateLegacySaves("", cfg.SaveDir)
+	if err != nil {
+		slog.Warn("Failed to move saves from the working directory", "error", err)
+	}
+	for _, slot := range moved {
+		slog.Info("Moved save slot", "slot", slot, "dir", cfg.SaveDir)
+	}
+
+	// Ctrl+C や SIGTERM でも最後にセーブしてから終了する
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	slots := storage.NewSlotManager(driver.NewKeyManager(cfg.SaveDir), cfg.SaveDir)
+	if cfg.SyncURL != "" {
+		slots = storage.NewSlotManagerWithSync(driver.NewKeyManager(cfg.SaveDir), cfg.SaveDir, cfg.SyncURL, cfg.SyncToken)
+	}
+	inputHandler := input.NewHandlerWithBindings(bindings)
+	var current *game.Game
+	start := func(slot string) (*game.Game, error) {
+		cfg.SaveKey = slots.GetKeyName(slot)
+		g, err := newGame(ctx, cfg, inputHandler, preferencesUseCase, bindings)
+		if g != nil {
+			g.SetLogOverlay(logOverlay)
+		}
+		current = g
+		return g, err
+	}
+
+	ebiten.SetWindowSize(cfg.ScreenWidth, cfg.ScreenHeight)
+	ebiten.SetWindowTitle("Clicker")
+	// The layout follows the window size, so the window can be resized freely
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	// Closing the window is reported to the game so that it can save first
+	ebiten.SetWindowClosingHandled(true)
+
+	var root ebiten.Game
+	if slot != "" {
+		g, err := start(slot)
+		if err != nil {
+			log.Fatal(err)
+		}
+		root = g
+	} else {
+		picker, err := presentation.NewSlotPicker(cfg, usecase.NewSlotUseCase(slots))
+		if err != nil {
+			log.Fatal(err)
+		}
+		root = game.NewLauncher(ctx, cfg, picker, inputHandler, start)
+	}
+	err = ebiten.RunGame(root)
+	// Stop the auto-save and wait for its final save before exiting
+	cancel()
+	if current != nil {
+		current.WaitAutoSave()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// overridePreferences returns the stored preferences with the flags given on the command line,
+// which take precedence for this session but are not stored
+func overridePreferences(preferences model.Preferences, cfg *config.Config) model.Preferences {
+	if flag.CommandLine.Changed("autosave") {
+		preferences.AutoSaveInterval = cfg.AutoSaveInterval
+	}
+	if flag.CommandLine.Changed("theme") {
+		preferences.Theme = cfg.Theme
+	}
+	if flag.CommandLine.Changed("debug") {
+		preferences.DebugOverlay = cfg.EnableDebug
+	}
+	return preferences
+}
+
+// newGame loads the save stored under cfg.SaveKey and builds the game for it
+func newGame(ctx context.Context, cfg *config.Config, inputHandler input.Handler, preferencesUseCase *usecase.PreferencesUseCase, bindings *input.Bindings) (*game.Game, error) {
 	gameState := state.NewGameState()
-	storage := storage.NewDefaultStorage(driver.NewStorageDriver(config.DefaultSaveKey))
-	if state, err := storage.LoadGameState(); err == nil {
+	storageDriver := driver.NewStorageDriver(cfg.SaveKey)
+	if cfg.SyncURL != "" {
+		storageDriver = driver.NewSyncDriver(storageDriver, cfg.SyncURL, cfg.SyncToken)
+	}
+	gameStorage := storage.NewDefaultStorageWithRetention(storageDriver, cfg.BackupRetention)
+	if cfg.Journal {
+		gameStorage = storage.NewJournalStorage(storageDriver, cfg.BackupRetention, cfg.JournalCompact)
+	}
+	if state, err := gameStorage.LoadGameState(); err == nil {
 		gameState = state
+	} else if errors.Is(err, storage.ErrNewerSaveVersion) {
+		// 新しいバージョンのセーブを上書きしないように起動を中止する
+		return nil, err
 	}
 	renderer, err := presentation.NewRenderer(
 		cfg,
@@ -33,26 +177,22 @@ func main() {
 		usecase.NewManualWorkUseCase(gameState),
 		usecase.NewBuildingUseCase(gameState),
 		usecase.NewUpgradeUseCase(gameState),
+		usecase.NewMarketUseCase(gameState),
+		preferencesUseCase,
+		bindings,
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	inputHandler := input.NewHandler()
 	g := game.NewGame(
 		cfg,
 		gameState,
-		storage,
+		gameStorage,
+		driver.NewTransferDriver(cfg.SaveKey),
 		renderer,
 		inputHandler,
 	)
-	ebiten.SetWindowSize(cfg.ScreenWidth, cfg.ScreenHeight)
-	ebiten.SetWindowTitle("Clicker")
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	g.StartAutoSave(ctx, 30*time.Second)
-	if err := ebiten.RunGame(g); err != nil {
-		log.Fatal(err)
-	}
+	g.ShowLoadReport()
+	g.StartAutoSave(ctx)
+	return g, nil
 }
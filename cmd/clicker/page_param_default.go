@@ -0,0 +1,9 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package main
+
+// pageParam returns an empty string since the desktop build is configured with flags only
+func pageParam(name string) string {
+	return ""
+}
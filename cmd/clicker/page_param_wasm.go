@@ -0,0 +1,20 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import "syscall/js"
+
+// pageParam returns the query parameter of the page URL, since the browser build has no command line.
+// e.g. index.html?sync=http://localhost:8080&token=secret
+func pageParam(name string) string {
+	search := js.Global().Get("location").Get("search")
+	if search.IsUndefined() {
+		return ""
+	}
+	value := js.Global().Get("URLSearchParams").New(search).Call("get", name)
+	if value.IsNull() {
+		return ""
+	}
+	return value.String()
+}
@@ -1,23 +1,104 @@
 package config
 
+import (
+	"strings"
+	"time"
+)
+
 type Config struct {
-	EnableDebug  bool   // Enable or disable debug mode
-	SaveKey      string // Key for saving game state
-	ScreenWidth  int    // Width of the game screen
-	ScreenHeight int    // Height of the game screen
+	EnableDebug      bool          // Enable or disable debug mode
+	SaveKey          string        // Key for saving game state
+	ScreenWidth      int           // Initial width of the window, the layout follows its size
+	ScreenHeight     int           // Initial height of the window
+	BackupRetention  int           // Number of rotating backups kept next to the save
+	SaveDir          string        // Directory the saves are stored in
+	AutoSaveInterval time.Duration // Interval between auto-saves
+	SyncURL          string        // Sync server to mirror saves to, disabled if empty
+	SyncToken        string        // Bearer token for the sync server
+	LogFile          string        // File to write the log to instead of stderr
+	Journal          bool          // Append changes to a journal and save after every action
+	JournalCompact   int           // Number of journal records written before they are compacted into a snapshot
+	Theme            string        // Built-in theme name or path to a theme file
 }
 
 // NewConfig creates a new configuration with default values
 func NewConfig() *Config {
 	return &Config{
-		EnableDebug:  false, // Debug mode is disabled by default
-		SaveKey:      DefaultSaveKey,
-		ScreenWidth:  800,
-		ScreenHeight: 600,
+		EnableDebug:      false, // Debug mode is disabled by default
+		SaveKey:          DefaultSaveKey,
+		ScreenWidth:      800,
+		ScreenHeight:     600,
+		BackupRetention:  DefaultBackupRetention,
+		AutoSaveInterval: DefaultAutoSaveInterval,
+		JournalCompact:   DefaultJournalCompact,
 	}
 }
 
 const (
 	DefaultSaveKey string = "game_state.json"
 	CostMultiplier        = 1.15
+	// DefaultBackupRetention is the number of the last good saves kept as backups
+	DefaultBackupRetention = 5
+	// DefaultAutoSaveInterval is the interval between auto-saves
+	DefaultAutoSaveInterval = 30 * time.Second
+	// DefaultJournalCompact is the number of journal records written before a new snapshot
+	DefaultJournalCompact = 100
+	// AppName is the name of the directory created under the user config directory
+	AppName = "clicker"
+	// SaveDirEnv overrides the save directory when --save-dir is not given
+	SaveDirEnv = "CLICKER_SAVE_DIR"
+	// SyncTokenEnv sets the sync server token when --sync-token is not given
+	SyncTokenEnv = "CLICKER_SYNC_TOKEN"
+	// PreferencesKey is the key the preferences are stored under in the save directory, apart from the save slots
+	PreferencesKey = "preferences.json"
+)
+
+const (
+	DefaultSlot    = "default" // Slot stored in DefaultSaveKey for compatibility with saves before slots
+	slotKeyPrefix  = "game_state."
+	slotKeySuffix  = ".json"
+	MaxSlotNameLen = 32
 )
+
+// SaveKeyForSlot returns the key a save slot is stored under
+func SaveKeyForSlot(slot string) string {
+	if slot == "" || slot == DefaultSlot {
+		return DefaultSaveKey
+	}
+	return slotKeyPrefix + slot + slotKeySuffix
+}
+
+// SlotFromSaveKey returns the slot stored under the key, or false if the key is not a save slot
+func SlotFromSaveKey(key string) (string, bool) {
+	if key == DefaultSaveKey {
+		return DefaultSlot, true
+	}
+	if !strings.HasPrefix(key, slotKeyPrefix) || !strings.HasSuffix(key, slotKeySuffix) {
+		return "", false
+	}
+	slot := strings.TrimSuffix(strings.TrimPrefix(key, slotKeyPrefix), slotKeySuffix)
+	if !IsValidSlotName(slot) || slot == DefaultSlot {
+		return "", false
+	}
+	return slot, true
+}
+
+// IsValidAutoSaveInterval reports whether the interval can be used for the auto-save.
+// The preferences store whole seconds, so shorter or fractional intervals are not allowed. 0 saves only on exit.
+func IsValidAutoSaveInterval(interval time.Duration) bool {
+	return interval >= 0 && interval%time.Second == 0
+}
+
+// IsValidSlotName reports whether the name can be used for a save slot.
+// Only letters, digits, '-' and '_' are allowed so that the name is safe as a file name.
+func IsValidSlotName(name string) bool {
+	if name == "" || len(name) > MaxSlotNameLen {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -7,11 +7,59 @@ import (
 )
 
 type Building struct {
-	ID               int     // Unique identifier for the building
-	Name             string  `json:"name"`
-	BaseCost         float64 `json:"base_cost"`
-	BaseGenerateRate float64 `json:"base_generate_rate"`
-	Count            int     `json:"count"`
+	ID               int            // Unique identifier for the building
+	Key              string         `json:"key"` // Stable identifier stored in saves, e.g. "cpu_miner"
+	Name             string         `json:"name"`
+	BaseCost         float64        `json:"base_cost"`
+	BaseGenerateRate float64        `json:"base_generate_rate"`
+	Count            int            `json:"count"`
+	IsMining         bool           `json:"is_mining"`   // Mining buildings produce coins instead of money
+	BaseUpkeep       float64        `json:"base_upkeep"` // Operating cost per unit and second
+	Level            int            `json:"level"`       // Index of the current tier in Tiers
+	Tiers            []BuildingTier `json:"-"`           // Tiers the building can be leveled up to, starting with the base tier
+}
+
+// BuildingTier describes one level of a building type, e.g. "CPU Miner mk2"
+type BuildingTier struct {
+	Name                   string  `json:"name"`
+	GenerateRateMultiplier float64 `json:"generate_rate_multiplier"`
+	Cost                   float64 `json:"cost"` // Lump sum to level up to this tier
+}
+
+// DisplayName returns the name of the current tier
+func (b *Building) DisplayName() string {
+	if tier, ok := b.tier(b.Level); ok {
+		return tier.Name
+	}
+	return b.Name
+}
+
+// GenerateRate returns the rate of a single unit at the current tier
+func (b *Building) GenerateRate() float64 {
+	if tier, ok := b.tier(b.Level); ok {
+		return b.BaseGenerateRate * tier.GenerateRateMultiplier
+	}
+	return b.BaseGenerateRate
+}
+
+// NextTier returns the tier the building can be leveled up to
+func (b *Building) NextTier() (BuildingTier, bool) {
+	return b.tier(b.Level + 1)
+}
+
+// MaxLevel returns the highest level the building can reach
+func (b *Building) MaxLevel() int {
+	if len(b.Tiers) == 0 {
+		return 0
+	}
+	return len(b.Tiers) - 1
+}
+
+func (b *Building) tier(level int) (BuildingTier, bool) {
+	if level < 0 || level >= len(b.Tiers) {
+		return BuildingTier{}, false
+	}
+	return b.Tiers[level], true
 }
 
 // Cost method: Calculates the cost based on the current number of purchases
@@ -30,16 +78,28 @@ func (b *Building) IsUnlocked() bool {
 // TotalGenerateRate method for calculating rounded values
 func (b *Building) TotalGenerateRate(upgrades []Upgrade) float64 {
 	// Calculation logic
-	rate := b.BaseGenerateRate * float64(b.Count)
+	rate := b.GenerateRate() * float64(b.Count)
 	// Apply necessary upgrades
 	for _, upgrade := range upgrades {
-		if !upgrade.IsTargetManualWork && b.ID == upgrade.TargetBuilding && upgrade.IsPurchased {
+		if !upgrade.IsTargetManualWork && !upgrade.IsTargetUpkeep && b.ID == upgrade.TargetBuilding && upgrade.IsPurchased {
 			rate = upgrade.Effect(rate)
 		}
 	}
 	return rate
 }
 
+// TotalUpkeep returns the operating cost per second of all units, reduced by efficiency upgrades
+func (b *Building) TotalUpkeep(upgrades []Upgrade) float64 {
+	upkeep := b.BaseUpkeep * float64(b.Count)
+
+	for _, upgrade := range upgrades {
+		if upgrade.IsTargetUpkeep && b.ID == upgrade.TargetBuilding && upgrade.IsPurchased {
+			upkeep = upgrade.Effect(upkeep)
+		}
+	}
+	return upkeep
+}
+
 func (b *Building) GenerateIncome(elapsed float64, upgrades []Upgrade) float64 {
 	if b.IsUnlocked() {
 		return b.TotalGenerateRate(upgrades) * elapsed // 丸めを削除
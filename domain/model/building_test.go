@@ -84,6 +84,7 @@ func newBuilding() *Building {
 		BaseCost:         10.0,
 		BaseGenerateRate: 0.5,
 		Count:            0,
+		BaseUpkeep:       0.1,
 	}
 }
 
@@ -167,4 +168,84 @@ var _ = Describe("Building", func() {
 			Expect(building.TotalGenerateRate(upgrades)).To(BeNumerically("~", 0.5*1.1*2, 0.00001))
 		})
 	})
+
+	Describe("TotalUpkeep", func() {
+		It("should return 0 when the building is locked", func() {
+			Expect(building.TotalUpkeep(nil)).To(Equal(0.0))
+		})
+
+		It("should scale with the number of units", func() {
+			building.Count = 3
+			Expect(building.TotalUpkeep(nil)).To(BeNumerically("~", 0.3, 0.00001))
+		})
+
+		It("should apply only purchased efficiency upgrades of the building", func() {
+			building.Count = 2
+			upgrades := []Upgrade{
+				{
+					Name:           "Efficiency",
+					IsTargetUpkeep: true,
+					TargetBuilding: 0,
+					IsPurchased:    true,
+					Effect: func(upkeep float64) float64 {
+						return upkeep * 0.5
+					},
+				},
+				{
+					Name:           "Other Efficiency",
+					IsTargetUpkeep: true,
+					TargetBuilding: 1,
+					IsPurchased:    true,
+					Effect: func(upkeep float64) float64 {
+						return upkeep * 0.5
+					},
+				},
+				{
+					Name:           "Rate Upgrade",
+					TargetBuilding: 0,
+					IsPurchased:    true,
+					Effect: func(rate float64) float64 {
+						return rate * 2.0
+					},
+				},
+			}
+			Expect(building.TotalUpkeep(upgrades)).To(BeNumerically("~", 0.1, 0.00001))
+			Expect(building.TotalGenerateRate(upgrades)).To(BeNumerically("~", 0.5*2*2, 0.00001))
+		})
+	})
+
+	Describe("Tiers", func() {
+		BeforeEach(func() {
+			building.Tiers = []BuildingTier{
+				{Name: "Test Building", GenerateRateMultiplier: 1},
+				{Name: "Test Building mk2", GenerateRateMultiplier: 3, Cost: 100},
+			}
+		})
+
+		It("should use the base tier at level 0", func() {
+			Expect(building.DisplayName()).To(Equal("Test Building"))
+			Expect(building.GenerateRate()).To(Equal(0.5))
+			tier, ok := building.NextTier()
+			Expect(ok).To(BeTrue())
+			Expect(tier.Cost).To(Equal(100.0))
+		})
+
+		It("should change name and rate with the level", func() {
+			building.Level = 1
+			building.Count = 2
+			Expect(building.DisplayName()).To(Equal("Test Building mk2"))
+			Expect(building.GenerateRate()).To(Equal(1.5))
+			Expect(building.TotalGenerateRate(nil)).To(Equal(3.0))
+			_, ok := building.NextTier()
+			Expect(ok).To(BeFalse())
+			Expect(building.MaxLevel()).To(Equal(1))
+		})
+
+		It("should fall back to the base values without tiers", func() {
+			building.Tiers = nil
+			Expect(building.DisplayName()).To(Equal("Test Building"))
+			Expect(building.GenerateRate()).To(Equal(0.5))
+			Expect(building.MaxLevel()).To(Equal(0))
+		})
+	})
 })
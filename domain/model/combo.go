@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+const (
+	ComboWindow         = time.Second            // Max pause between two actions that keeps the streak
+	ComboDecayInterval  = 500 * time.Millisecond // Time to lose one streak step after the window elapsed
+	ComboBonusPerStreak = 0.1                    // Multiplier gained per streak step
+	MaxComboMultiplier  = 3.0
+	MaxActionsPerSecond = 15 // Cap on accepted manual actions to keep auto-clickers in check
+	actionRateLimitSpan = time.Second
+)
+
+// Combo tracks a streak of manual actions and limits how many are accepted per second.
+// The time is always passed in so that callers can inject their clock.
+type Combo struct {
+	Streak     int
+	LastAction time.Time
+	recent     []time.Time // Accepted actions within the rate limit span
+}
+
+// Register records an action and returns false if it was rejected by the rate limit
+func (c *Combo) Register(now time.Time) bool {
+	if !c.allow(now) {
+		return false
+	}
+	c.Streak = c.CurrentStreak(now) + 1
+	c.LastAction = now
+	return true
+}
+
+// CurrentStreak returns the streak at now, decayed by one step for every
+// ComboDecayInterval that passed after the ComboWindow
+func (c *Combo) CurrentStreak(now time.Time) int {
+	idle := now.Sub(c.LastAction)
+	if idle <= ComboWindow {
+		return c.Streak
+	}
+	streak := c.Streak - int((idle-ComboWindow)/ComboDecayInterval) - 1
+	if streak < 0 {
+		return 0
+	}
+	return streak
+}
+
+// Multiplier returns the bonus applied to manual work for the streak at now
+func (c *Combo) Multiplier(now time.Time) float64 {
+	streak := c.CurrentStreak(now)
+	if streak <= 1 {
+		return 1
+	}
+	multiplier := 1 + ComboBonusPerStreak*float64(streak-1)
+	if multiplier > MaxComboMultiplier {
+		return MaxComboMultiplier
+	}
+	return multiplier
+}
+
+func (c *Combo) allow(now time.Time) bool {
+	kept := c.recent[:0]
+	for _, t := range c.recent {
+		if now.Sub(t) < actionRateLimitSpan {
+			kept = append(kept, t)
+		}
+	}
+	c.recent = kept
+	if len(c.recent) >= MaxActionsPerSecond {
+		return false
+	}
+	c.recent = append(c.recent, now)
+	return true
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Combo", func() {
+	var (
+		combo *Combo
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		combo = &Combo{}
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	Describe("Register", func() {
+		It("should build a streak for actions within the window", func() {
+			for i := 0; i < 3; i++ {
+				Expect(combo.Register(now)).To(BeTrue())
+				now = now.Add(200 * time.Millisecond)
+			}
+			Expect(combo.Streak).To(Equal(3))
+		})
+
+		It("should restart the streak after a long pause", func() {
+			combo.Register(now)
+			combo.Register(now.Add(100 * time.Millisecond))
+			Expect(combo.Register(now.Add(10 * time.Second))).To(BeTrue())
+			Expect(combo.Streak).To(Equal(1))
+		})
+
+		It("should reject actions above the rate limit", func() {
+			for i := 0; i < MaxActionsPerSecond; i++ {
+				Expect(combo.Register(now.Add(time.Duration(i) * time.Millisecond))).To(BeTrue())
+			}
+			Expect(combo.Register(now.Add(100 * time.Millisecond))).To(BeFalse())
+			Expect(combo.Streak).To(Equal(MaxActionsPerSecond))
+		})
+
+		It("should accept actions again after the rate limit span", func() {
+			for i := 0; i < MaxActionsPerSecond; i++ {
+				combo.Register(now)
+			}
+			Expect(combo.Register(now.Add(time.Second))).To(BeTrue())
+		})
+	})
+
+	Describe("CurrentStreak", func() {
+		BeforeEach(func() {
+			for i := 0; i < 5; i++ {
+				combo.Register(now)
+			}
+		})
+
+		It("should keep the streak within the window", func() {
+			Expect(combo.CurrentStreak(now.Add(ComboWindow))).To(Equal(5))
+		})
+
+		It("should decay step by step after the window", func() {
+			Expect(combo.CurrentStreak(now.Add(ComboWindow + time.Millisecond))).To(Equal(4))
+			Expect(combo.CurrentStreak(now.Add(ComboWindow + ComboDecayInterval + time.Millisecond))).To(Equal(3))
+		})
+
+		It("should not decay below zero", func() {
+			Expect(combo.CurrentStreak(now.Add(time.Hour))).To(Equal(0))
+		})
+	})
+
+	Describe("Multiplier", func() {
+		It("should not boost a single action", func() {
+			combo.Register(now)
+			Expect(combo.Multiplier(now)).To(Equal(1.0))
+		})
+
+		It("should grow with the streak", func() {
+			combo.Register(now)
+			combo.Register(now)
+			combo.Register(now)
+			Expect(combo.Multiplier(now)).To(BeNumerically("~", 1.2, 0.00001))
+		})
+
+		It("should be capped", func() {
+			combo.Streak = 1000
+			combo.LastAction = now
+			Expect(combo.Multiplier(now)).To(Equal(MaxComboMultiplier))
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"math"
+)
+
+const (
+	CoinBasePrice        = 1.0  // Fair price of one coin before any halving
+	MarketTickSeconds    = 1.0  // Seconds of game time per market step
+	MarketHistorySize    = 120  // Number of prices kept for the chart
+	MarketHalvingTicks   = 3600 // Steps between two halvings
+	marketMeanReversion  = 0.01 // Pull of the price towards the fair price per step
+	marketVolatility     = 0.03 // Standard deviation of the log price per step
+	marketCrashChance    = 0.002
+	marketCrashFactor    = 0.5
+	marketRallyChance    = 0.002
+	marketRallyFactor    = 1.5
+	marketMinPrice       = 0.0001
+	marketSplitMixGamma  = 0x9e3779b97f4a7c15
+	marketSplitMixMulti1 = 0xbf58476d1ce4e5b9
+	marketSplitMixMulti2 = 0x94d049bb133111eb
+)
+
+// Market events reported by the last step
+const (
+	MarketEventNone    = ""
+	MarketEventHalving = "Halving"
+	MarketEventCrash   = "Crash"
+	MarketEventRally   = "Rally"
+)
+
+// Market simulates the price of the coin produced by mining buildings.
+// The price follows a seeded random walk so that a saved market continues
+// exactly where it stopped.
+type Market struct {
+	Price        float64   `json:"price"`
+	History      []float64 `json:"history"`
+	Halvings     int       `json:"halvings"`
+	Ticks        int       `json:"ticks"`
+	TickProgress float64   `json:"tick_progress"`
+	RandState    uint64    `json:"rand_state"`
+	LastEvent    string    `json:"last_event"`
+}
+
+func NewMarket(seed int64) Market {
+	return Market{
+		Price:     CoinBasePrice,
+		History:   []float64{CoinBasePrice},
+		RandState: uint64(seed),
+	}
+}
+
+// FairPrice is the price the random walk reverts to. It doubles on every
+// halving so that the money value of mining stays roughly constant.
+func (m *Market) FairPrice() float64 {
+	return CoinBasePrice * math.Pow(2, float64(m.Halvings))
+}
+
+// RewardMultiplier is the share of the original block reward still paid to miners
+func (m *Market) RewardMultiplier() float64 {
+	return math.Pow(0.5, float64(m.Halvings))
+}
+
+// ConvertToCoins converts the money a mining building would have produced into mined coins
+func (m *Market) ConvertToCoins(amount float64) float64 {
+	return amount / CoinBasePrice * m.RewardMultiplier()
+}
+
+// Advance moves the market forward by the elapsed seconds and returns the number of steps taken
+func (m *Market) Advance(elapsed float64) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	if m.Price <= 0 {
+		m.Price = m.FairPrice()
+	}
+	m.TickProgress += elapsed
+	steps := 0
+	for m.TickProgress >= MarketTickSeconds {
+		m.TickProgress -= MarketTickSeconds
+		m.step()
+		steps++
+	}
+	return steps
+}
+
+func (m *Market) step() {
+	m.Ticks++
+	m.LastEvent = MarketEventNone
+
+	logPrice := math.Log(m.Price)
+	logPrice += marketMeanReversion*(math.Log(m.FairPrice())-logPrice) + marketVolatility*m.normal()
+	m.Price = math.Exp(logPrice)
+
+	if m.Ticks%MarketHalvingTicks == 0 {
+		m.Halvings++
+		m.LastEvent = MarketEventHalving
+	}
+	switch r := m.float(); {
+	case r < marketCrashChance:
+		m.Price *= marketCrashFactor
+		m.LastEvent = MarketEventCrash
+	case r < marketCrashChance+marketRallyChance:
+		m.Price *= marketRallyFactor
+		m.LastEvent = MarketEventRally
+	}
+	if m.Price < marketMinPrice {
+		m.Price = marketMinPrice
+	}
+
+	m.History = append(m.History, m.Price)
+	if len(m.History) > MarketHistorySize {
+		m.History = m.History[len(m.History)-MarketHistorySize:]
+	}
+}
+
+// next returns the next value of a splitmix64 generator
+func (m *Market) next() uint64 {
+	m.RandState += marketSplitMixGamma
+	z := m.RandState
+	z = (z ^ (z >> 30)) * marketSplitMixMulti1
+	z = (z ^ (z >> 27)) * marketSplitMixMulti2
+	return z ^ (z >> 31)
+}
+
+// float returns a uniformly distributed value in [0, 1)
+func (m *Market) float() float64 {
+	return float64(m.next()>>11) / (1 << 53)
+}
+
+// normal returns a standard normally distributed value (Box-Muller)
+func (m *Market) normal() float64 {
+	u1 := m.float()
+	for u1 == 0 {
+		u1 = m.float()
+	}
+	u2 := m.float()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Market", func() {
+	var market Market
+
+	BeforeEach(func() {
+		market = NewMarket(42)
+	})
+
+	Describe("NewMarket", func() {
+		It("should start at the base price", func() {
+			Expect(market.Price).To(Equal(CoinBasePrice))
+			Expect(market.History).To(Equal([]float64{CoinBasePrice}))
+		})
+	})
+
+	Describe("Advance", func() {
+		It("should take one step per elapsed second", func() {
+			Expect(market.Advance(2.5)).To(Equal(2))
+			Expect(market.Ticks).To(Equal(2))
+			Expect(market.TickProgress).To(BeNumerically("~", 0.5, 0.00001))
+			Expect(market.Advance(0.5)).To(Equal(1))
+		})
+
+		It("should not move on non-positive elapsed time", func() {
+			Expect(market.Advance(0)).To(Equal(0))
+			Expect(market.Advance(-1)).To(Equal(0))
+			Expect(market.Price).To(Equal(CoinBasePrice))
+		})
+
+		It("should be deterministic for the same seed", func() {
+			other := NewMarket(42)
+			market.Advance(100)
+			other.Advance(100)
+			Expect(market.Price).To(Equal(other.Price))
+			Expect(market.History).To(Equal(other.History))
+		})
+
+		It("should differ for another seed", func() {
+			other := NewMarket(43)
+			market.Advance(100)
+			other.Advance(100)
+			Expect(market.Price).NotTo(Equal(other.Price))
+		})
+
+		It("should keep the price positive and the history bounded", func() {
+			market.Advance(MarketHistorySize * 5)
+			Expect(market.Price).To(BeNumerically(">", 0))
+			Expect(market.History).To(HaveLen(MarketHistorySize))
+		})
+
+		It("should halve the reward after the halving interval", func() {
+			market.Advance(MarketHalvingTicks)
+			Expect(market.Halvings).To(Equal(1))
+			Expect(market.RewardMultiplier()).To(Equal(0.5))
+			Expect(market.FairPrice()).To(Equal(CoinBasePrice * 2))
+		})
+
+		It("should recover a market without price", func() {
+			market = Market{}
+			market.Advance(1)
+			Expect(market.Price).To(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("ConvertToCoins", func() {
+		It("should convert money at the base price", func() {
+			Expect(market.ConvertToCoins(CoinBasePrice * 3)).To(BeNumerically("~", 3, 0.00001))
+		})
+
+		It("should apply the halvings", func() {
+			market.Halvings = 2
+			Expect(market.ConvertToCoins(CoinBasePrice * 4)).To(BeNumerically("~", 1, 0.00001))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/kmdkuk/clicker/config"
+)
+
+// NumberFormat is how large numbers such as money are shown
+type NumberFormat string
+
+const (
+	NumberFormatShort      NumberFormat = "short"      // 1.23M
+	NumberFormatScientific NumberFormat = "scientific" // 1.23e6
+	NumberFormatFull       NumberFormat = "full"       // 1,234,567
+)
+
+const (
+	MaxSoundVolume  = 100
+	SoundVolumeStep = 10
+)
+
+// NumberFormats are the number formats the player can choose from
+var NumberFormats = []NumberFormat{NumberFormatShort, NumberFormatScientific, NumberFormatFull}
+
+// AutoSaveIntervals are the auto-save intervals the player can choose from. 0 saves only on exit.
+var AutoSaveIntervals = []time.Duration{0, 10 * time.Second, 30 * time.Second, time.Minute, 5 * time.Minute}
+
+// Preferences are the settings of the player. They are stored apart from the saves,
+// so they are shared by all save slots and kept when a game is reset.
+type Preferences struct {
+	AutoSaveInterval time.Duration
+	NumberFormat     NumberFormat
+	Theme            string // Built-in theme name or path to a theme file, empty for the default theme
+	SoundVolume      int    // 0 to MaxSoundVolume
+	ConfirmPurchases bool   // Ask before buying buildings and upgrades
+	DebugOverlay     bool   // Show the debug information and the recent log lines
+	// Key names bound to each action by name, nil for the default keys.
+	// The names are resolved by the input handler, so that the model does not depend on the keyboard.
+	KeyBindings map[string][]string
+}
+
+func DefaultPreferences() Preferences {
+	return Preferences{
+		AutoSaveInterval: config.DefaultAutoSaveInterval,
+		NumberFormat:     NumberFormatShort,
+		SoundVolume:      MaxSoundVolume,
+	}
+}
+
+// Normalize replaces the values that are out of range, e.g. from an edited preferences file, with the defaults.
+// It returns the number of values that were replaced.
+func (p *Preferences) Normalize() int {
+	defaults := DefaultPreferences()
+	replaced := 0
+	if !config.IsValidAutoSaveInterval(p.AutoSaveInterval) {
+		p.AutoSaveInterval = defaults.AutoSaveInterval
+		replaced++
+	}
+	if !slices.Contains(NumberFormats, p.NumberFormat) {
+		p.NumberFormat = defaults.NumberFormat
+		replaced++
+	}
+	if p.SoundVolume < 0 || p.SoundVolume > MaxSoundVolume {
+		p.SoundVolume = defaults.SoundVolume
+		replaced++
+	}
+	return replaced
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/kmdkuk/clicker/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Preferences", func() {
+	It("should default to the auto-save interval of the config", func() {
+		p := DefaultPreferences()
+		Expect(p.AutoSaveInterval).To(Equal(config.DefaultAutoSaveInterval))
+		Expect(p.NumberFormat).To(Equal(NumberFormatShort))
+		Expect(p.SoundVolume).To(Equal(MaxSoundVolume))
+		Expect(p.ConfirmPurchases).To(BeFalse())
+	})
+
+	Describe("Normalize", func() {
+		It("should keep valid preferences", func() {
+			p := Preferences{AutoSaveInterval: time.Minute, NumberFormat: NumberFormatFull, SoundVolume: 0, DebugOverlay: true}
+			Expect(p.Normalize()).To(Equal(0))
+			Expect(p.NumberFormat).To(Equal(NumberFormatFull))
+			Expect(p.SoundVolume).To(Equal(0))
+		})
+
+		It("should replace an auto-save interval that is not whole seconds", func() {
+			p := Preferences{AutoSaveInterval: 500 * time.Millisecond, NumberFormat: NumberFormatShort}
+			Expect(p.Normalize()).To(Equal(1))
+			Expect(p.AutoSaveInterval).To(Equal(config.DefaultAutoSaveInterval))
+		})
+
+		It("should replace values out of range with the defaults", func() {
+			p := Preferences{AutoSaveInterval: -time.Second, NumberFormat: "roman", SoundVolume: 150, ConfirmPurchases: true}
+			Expect(p.Normalize()).To(Equal(3))
+			Expect(p.AutoSaveInterval).To(Equal(config.DefaultAutoSaveInterval))
+			Expect(p.NumberFormat).To(Equal(NumberFormatShort))
+			Expect(p.SoundVolume).To(Equal(MaxSoundVolume))
+			Expect(p.ConfirmPurchases).To(BeTrue())
+		})
+	})
+})
@@ -7,6 +7,7 @@ type Upgrade struct {
 	Effect             func(float64) float64      `json:"-"` // Exclude from JSON encoding
 	IsPurchased        bool                       `json:"is_purchased"`
 	IsTargetManualWork bool                       `json:"is_target_manual_work"`
+	IsTargetUpkeep     bool                       `json:"is_target_upkeep"` // Effect applies to the upkeep of TargetBuilding
 	TargetBuilding     int                        `json:"target_building"`
 	IsReleased         func(GameStateReader) bool `json:"-"` // Exclude from JSON encoding
 }
@@ -2,12 +2,16 @@ package game
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/logging"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 	"github.com/kmdkuk/clicker/infrastructure/storage"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
 	"github.com/kmdkuk/clicker/presentation"
 	"github.com/kmdkuk/clicker/presentation/input"
 
@@ -18,61 +22,287 @@ type Game struct {
 	config       *config.Config  // Game configuration
 	gameState    state.GameState // Game state
 	storage      storage.Storage
+	transfer     driver.TransferDriver // Moves exported save strings in and out of the game
 	inputHandler input.Handler         // Handler to manage input processing
 	renderer     presentation.Renderer // Update Renderer to use the presentation package
+	saveMu       sync.Mutex            // Serializes saves from the game loop and the auto-save
+	stopped      chan struct{}         // Closed after the final save when the auto-save is stopped
+	loopEnded    chan struct{}         // Closed by WaitAutoSave once the game loop no longer runs
+	snapshotDue  atomic.Bool           // Set by the auto-save to ask the game loop for a snapshot
+	snapshots    chan snapshot         // Snapshots taken on the game loop for the auto-save to write
+	generation   int                   // Counts the times the game state was replaced, e.g. by an import
+	logOverlay   *logging.Overlay      // Recent log lines shown in debug mode
+	deviceScale  func() float64        // Device scale factor of the monitor, replaced in tests
+	// Auto-save interval in use, and the channel that passes a changed interval to the auto-save
+	autoSaveInterval time.Duration
+	intervalChanged  chan time.Duration
 }
 
-func NewGame(c *config.Config, gameState state.GameState, storage storage.Storage, renderer presentation.Renderer, inputHandler input.Handler) *Game {
+func NewGame(c *config.Config, gameState state.GameState, storage storage.Storage, transfer driver.TransferDriver, renderer presentation.Renderer, inputHandler input.Handler) *Game {
 	return &Game{
 		config:       c,
 		gameState:    gameState,
 		storage:      storage,
+		transfer:     transfer,
 		inputHandler: inputHandler,
 		renderer:     renderer,
+		stopped:      make(chan struct{}),
+		loopEnded:    make(chan struct{}),
+		snapshots:    make(chan snapshot, 1),
+		deviceScale:  deviceScaleFactor,
+		// 最新の間隔だけ伝われば良いのでバッファは1つ
+		intervalChanged: make(chan time.Duration, 1),
 	}
 }
 
-func (g *Game) StartAutoSave(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
+// snapshot is a copy of the game state taken on the game loop, so that the auto-save
+// writes it without reading the state while the game changes it
+type snapshot struct {
+	gameState  state.GameState
+	generation int // Generation of the game state the copy was taken from
+}
+
+// SetLogOverlay shows the recent log lines of the overlay as the debug message
+func (g *Game) SetLogOverlay(overlay *logging.Overlay) {
+	g.logOverlay = overlay
+}
+
+// StartAutoSave saves the game every config.AutoSaveInterval until the context is cancelled.
+// The game is saved once more when the context is cancelled and Update then ends the game.
+// The auto-save asks Update for a snapshot of the game state and writes it on its own goroutine.
+// A non-positive interval disables the periodic saves but keeps the final save.
+// A change of config.AutoSaveInterval, e.g. from the settings, is picked up by Update.
+func (g *Game) StartAutoSave(ctx context.Context) {
+	g.autoSaveInterval = g.config.AutoSaveInterval
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	reset := func(interval time.Duration) {
+		if ticker != nil {
+			ticker.Stop()
+			ticker, tick = nil, nil
+		}
+		if interval > 0 {
+			ticker = time.NewTicker(interval)
+			tick = ticker.C
+		}
+	}
+	reset(g.autoSaveInterval)
 	go func() {
-		defer ticker.Stop()
+		defer close(g.stopped)
+		defer reset(0)
 		for {
 			select {
 			case <-ctx.Done():
-				log.Printf("Auto-save stopped")
+				if err := g.saveSnapshot(g.finalSnapshot(), g.storage.SaveFinalGameState); err != nil {
+					slog.Error("Final save failed", "error", err)
+				}
+				slog.Info("Auto-save stopped")
 				return
-			case <-ticker.C:
+			case interval := <-g.intervalChanged:
+				reset(interval)
+				slog.Info("Auto-save interval changed", "interval", interval)
+			case <-tick:
+				g.snapshotDue.Store(true)
+			case s := <-g.snapshots:
 				// Auto-save the game state
-				err := g.storage.SaveGameState(g.gameState)
-				if err != nil {
-					log.Printf("Auto-save failed: %v", err)
+				if err := g.saveSnapshot(s, g.storage.SaveGameState); err != nil {
+					slog.Warn("Auto-save failed", "error", err)
 				}
 			}
 		}
 	}()
 }
 
+// WaitAutoSave blocks until the auto-save started by StartAutoSave has made its final save.
+// It is called once after the game loop has ended, so the final save no longer waits for Update.
+func (g *Game) WaitAutoSave() {
+	close(g.loopEnded)
+	<-g.stopped
+}
+
+// finalSnapshot asks Update for a snapshot, or takes it itself once the game loop has ended
+func (g *Game) finalSnapshot() snapshot {
+	// A snapshot that was not written yet is older than the one asked for
+	select {
+	case <-g.snapshots:
+	default:
+	}
+	g.snapshotDue.Store(true)
+	select {
+	case s := <-g.snapshots:
+		return s
+	case <-g.loopEnded:
+		return g.takeSnapshot()
+	}
+}
+
+// takeSnapshot copies the game state. It is called on the game loop.
+func (g *Game) takeSnapshot() snapshot {
+	return snapshot{gameState: g.gameState.Clone(), generation: g.generation}
+}
+
+// sendSnapshot passes a snapshot to the auto-save if it asked for one
+func (g *Game) sendSnapshot() {
+	if !g.snapshotDue.CompareAndSwap(true, false) {
+		return
+	}
+	// Replace a snapshot the auto-save has not picked up yet
+	select {
+	case <-g.snapshots:
+	default:
+	}
+	g.snapshots <- g.takeSnapshot()
+}
+
+// saveSnapshot writes the snapshot with save unless the game state was replaced after it was taken
+func (g *Game) saveSnapshot(s snapshot, save func(state.GameState) error) error {
+	g.saveMu.Lock()
+	defer g.saveMu.Unlock()
+	if s.generation != g.generation {
+		return nil
+	}
+	return save(s.gameState)
+}
+
+// Save writes the game state to the storage. It is called on the game loop.
+func (g *Game) Save() error {
+	g.saveMu.Lock()
+	defer g.saveMu.Unlock()
+	return g.storage.SaveGameState(g.gameState)
+}
+
 func (g *Game) Update() error {
+	select {
+	case <-g.stopped:
+		// The final save is already done
+		return ebiten.Termination
+	default:
+	}
+
 	g.inputHandler.Update() // Update input handler
 	defer g.inputHandler.ResetClickState()
 
+	if g.inputHandler.IsCloseRequested() {
+		// Save before the window is closed so that no progress since the last auto-save is lost
+		if err := g.Save(); err != nil {
+			slog.Error("Save on close failed", "error", err)
+		}
+		return ebiten.Termination
+	}
+
 	g.gameState.UpdateBuildings(time.Now())
 
 	// Update game state
+	keyType := g.inputHandler.GetPressedKey()
+	if !g.renderer.IsPopupActive() {
+		switch keyType {
+		case input.KeyTypeSave:
+			g.renderer.ShowToast(g.saveNow())
+		case input.KeyTypeExport:
+			g.renderer.ShowPopup(g.exportSave())
+		case input.KeyTypeImport:
+			g.confirmImport()
+		}
+	}
 	x, y := g.inputHandler.GetMouseCursor()
-	g.renderer.HandleInput(g.inputHandler.GetPressedKey(), g.inputHandler.IsClicked(), g.inputHandler.IsMouseMoved(), x, y)
+	isClicked := g.inputHandler.IsClicked()
+	g.renderer.HandleInput(keyType, isClicked, g.inputHandler.IsMouseMoved(), x, y)
+	g.renderer.HandleDrag(g.inputHandler.GetDrag())
+	// Switched after the input, so that a tap is handled on the rows it was aimed at
+	g.renderer.SetTouchMode(g.inputHandler.IsTouchUsed())
+	if g.renderer.TakeGameStateChange() && g.config.Journal {
+		// The journal makes saves cheap enough to keep every action.
+		// The auto-save writes them off the game loop, and its final save compacts them into the synced save.
+		g.snapshotDue.Store(true)
+	}
 
 	g.renderer.Update()
+	g.updateAutoSaveInterval()
+	g.sendSnapshot()
+	if g.logOverlay != nil {
+		g.renderer.DebugMessage(g.logOverlay.String())
+	} else if g.config.EnableDebug {
+		// The log is only collected for the overlay if debug mode was on at startup
+		g.renderer.DebugMessage("Restart the game to show the recent log lines here")
+	}
 
 	return nil
 }
 
+// updateAutoSaveInterval passes a changed config.AutoSaveInterval to the auto-save
+func (g *Game) updateAutoSaveInterval() {
+	if g.config.AutoSaveInterval == g.autoSaveInterval {
+		return
+	}
+	g.autoSaveInterval = g.config.AutoSaveInterval
+	// Replace an interval the auto-save has not picked up yet
+	select {
+	case <-g.intervalChanged:
+	default:
+	}
+	g.intervalChanged <- g.autoSaveInterval
+}
+
+// saveNow saves the game on request and returns the message to show
+func (g *Game) saveNow() string {
+	if err := g.Save(); err != nil {
+		slog.Warn("Save failed", "error", err)
+		return "Failed to save game!"
+	}
+	return "Game saved!"
+}
+
+// exportSave hands the save string to the transfer driver and returns the message to show
+func (g *Game) exportSave() string {
+	text, err := g.storage.ExportGameState(g.gameState)
+	if err == nil {
+		err = g.transfer.Export(text)
+	}
+	if err != nil {
+		slog.Warn("Export failed", "error", err)
+		return "Failed to export save!"
+	}
+	return "Save exported to " + g.transfer.Describe() + "!"
+}
+
+// confirmImport asks before the imported save replaces the running game
+func (g *Game) confirmImport() {
+	question := "Import the save from " + g.transfer.Describe() + "?\nThe current game will be replaced."
+	g.renderer.ShowDialog(question, []string{"Import", "Cancel"}, func(choice int) {
+		if choice == 0 {
+			g.renderer.ShowPopup(g.importSave())
+		}
+	})
+}
+
+// importSave replaces the game state with the save string from the transfer driver
+func (g *Game) importSave() string {
+	text, err := g.transfer.Import()
+	if err != nil {
+		slog.Warn("Import failed", "error", err)
+		return "No save to import from " + g.transfer.Describe() + "!"
+	}
+	g.saveMu.Lock()
+	defer g.saveMu.Unlock()
+	if err := g.storage.ImportGameState(g.gameState, text); err != nil {
+		slog.Warn("Import failed", "error", err)
+		return "Invalid save string!"
+	}
+	// Snapshots from before the import must not overwrite it
+	g.generation++
+	return "Save imported successfully!"
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
 	g.renderer.Draw(screen) // Delegate drawing to renderer
 }
 
+// Layout follows the size of the window, so the renderer reflows the UI when the window is resized
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return g.config.ScreenWidth, g.config.ScreenHeight
+	scale := g.deviceScale()
+	width, height := screenSize(outsideWidth, outsideHeight, scale)
+	g.renderer.Resize(width, height, scale)
+	return width, height
 }
 
 // GetTotalGenerateRate calculates the total money generation rate from all unlocked buildings
@@ -5,9 +5,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/kmdkuk/clicker/application/usecase"
 	"github.com/kmdkuk/clicker/config"
 	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/logging"
 	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+	"github.com/kmdkuk/clicker/presentation"
 	"github.com/kmdkuk/clicker/presentation/input"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -53,6 +58,11 @@ func (m *mockGameState) SetBuildingCount(buildingIndex int, count int) error {
 	panic("unimplemented")
 }
 
+// SetBuildingLevel implements state.GameState.
+func (m *mockGameState) SetBuildingLevel(buildingIndex int, level int) error {
+	panic("unimplemented")
+}
+
 // SetManualWorkCount implements state.GameState.
 func (m *mockGameState) SetManualWorkCount(count int) error {
 	panic("unimplemented")
@@ -78,6 +88,51 @@ func (m *mockGameState) UpdateMoney(amount float64) {
 	panic("unimplemented")
 }
 
+// GetCoins implements state.GameState.
+func (m *mockGameState) GetCoins() float64 {
+	panic("unimplemented")
+}
+
+// UpdateCoins implements state.GameState.
+func (m *mockGameState) UpdateCoins(amount float64) {
+	panic("unimplemented")
+}
+
+// GetMarket implements state.GameState.
+func (m *mockGameState) GetMarket() *model.Market {
+	panic("unimplemented")
+}
+
+// SetMarket implements state.GameState.
+func (m *mockGameState) SetMarket(market model.Market) {
+	panic("unimplemented")
+}
+
+// GetTotalUpkeep implements state.GameState.
+func (m *mockGameState) GetTotalUpkeep() float64 {
+	panic("unimplemented")
+}
+
+// GetThrottle implements state.GameState.
+func (m *mockGameState) GetThrottle() float64 {
+	panic("unimplemented")
+}
+
+// IsTampered implements state.GameState.
+func (m *mockGameState) IsTampered() bool {
+	panic("unimplemented")
+}
+
+// SetTampered implements state.GameState.
+func (m *mockGameState) SetTampered(tampered bool) {
+	panic("unimplemented")
+}
+
+// Clone implements state.GameState.
+func (m *mockGameState) Clone() state.GameState {
+	return m
+}
+
 func (m *mockGameState) UpdateBuildings(time time.Time) {
 	// Mock implementation for UpdateBuildings
 }
@@ -96,6 +151,13 @@ type mockStorage struct {
 	savedGameState state.GameState
 	loadErr        error
 	saveErr        error
+	importErr      error
+	importedText   string
+	saveCount      int
+	finalSaves     int
+	report         storage.LoadReport
+	restoredBackup int
+	restoreErr     error
 }
 
 func (m *mockStorage) LoadGameState() (state.GameState, error) {
@@ -109,6 +171,7 @@ func (m *mockStorage) LoadGameState() (state.GameState, error) {
 }
 
 func (m *mockStorage) SaveGameState(gs state.GameState) error {
+	m.saveCount++
 	if m.saveErr != nil {
 		return m.saveErr
 	}
@@ -116,8 +179,70 @@ func (m *mockStorage) SaveGameState(gs state.GameState) error {
 	return nil
 }
 
+func (m *mockStorage) SaveFinalGameState(gs state.GameState) error {
+	m.finalSaves++
+	return m.SaveGameState(gs)
+}
+
+func (m *mockStorage) ExportGameState(gs state.GameState) (string, error) {
+	return "clicker:exported", nil
+}
+
+func (m *mockStorage) ImportGameState(gs state.GameState, text string) error {
+	m.importedText = text
+	return m.importErr
+}
+
+func (m *mockStorage) LastLoadReport() storage.LoadReport {
+	return m.report
+}
+
+func (m *mockStorage) RestoreBackupInto(gs state.GameState, n int) error {
+	m.restoredBackup = n
+	return m.restoreErr
+}
+
+type mockTransfer struct {
+	exportedText string
+	importText   string
+	importErr    error
+}
+
+func (m *mockTransfer) Export(text string) error {
+	m.exportedText = text
+	return nil
+}
+
+func (m *mockTransfer) Import() (string, error) {
+	return m.importText, m.importErr
+}
+
+func (m *mockTransfer) Describe() string {
+	return "mock"
+}
+
 type mockInputHandler struct {
-	pressedKey input.KeyType
+	pressedKey     input.KeyType
+	inputChars     []rune
+	closeRequested bool
+	textMode       bool
+	dragDY         float64
+	touching       bool
+	touchUsed      bool
+	clicked        bool
+	mouseX, mouseY int
+}
+
+func (m *mockInputHandler) GetDrag() (float64, bool) {
+	return m.dragDY, m.touching
+}
+
+func (m *mockInputHandler) IsTouchUsed() bool {
+	return m.touchUsed
+}
+
+func (m *mockInputHandler) SetTextMode(enabled bool) {
+	m.textMode = enabled
 }
 
 func (m *mockInputHandler) Update() {
@@ -125,7 +250,7 @@ func (m *mockInputHandler) Update() {
 }
 
 func (m *mockInputHandler) IsClicked() bool {
-	return false
+	return m.clicked
 }
 func (m *mockInputHandler) IsMouseMoved() bool {
 	return false
@@ -134,7 +259,14 @@ func (m *mockInputHandler) ResetClickState() {
 	// Do nothing in the mock
 }
 func (m *mockInputHandler) GetMouseCursor() (int, int) {
-	return 0, 0
+	return m.mouseX, m.mouseY
+}
+func (m *mockInputHandler) GetInputChars() []rune {
+	return m.inputChars
+}
+
+func (m *mockInputHandler) IsCloseRequested() bool {
+	return m.closeRequested
 }
 
 func (m *mockInputHandler) GetPressedKey() input.KeyType {
@@ -147,8 +279,21 @@ func (m *mockInputHandler) SetPressedKey(key input.KeyType) {
 
 type mockRenderer struct {
 	popupActive      bool
+	popupMessage     string
+	toastMessage     string
+	dialogMessage    string
+	dialogOptions    []string
+	onChoose         func(choice int)
 	lastHandledInput input.KeyType
 	drawCalled       bool
+	debugMessage     string
+	width            int
+	height           int
+	scale            float64
+	dragDY           float64
+	touching         bool
+	touchMode        bool
+	gameStateChanged bool
 }
 
 // GetCursor implements ui.Renderer.
@@ -171,6 +316,12 @@ func (m *mockRenderer) IsPopupActive() bool {
 	return m.popupActive
 }
 
+func (m *mockRenderer) TakeGameStateChange() bool {
+	changed := m.gameStateChanged
+	m.gameStateChanged = false
+	return changed
+}
+
 func (m *mockRenderer) SetPopupActive(active bool) {
 	m.popupActive = active
 }
@@ -189,8 +340,21 @@ func (m *mockRenderer) HandleInput(keyType input.KeyType, isClicked, isMouseMove
 	m.lastHandledInput = keyType
 }
 
+func (m *mockRenderer) HandleDrag(dy float64, touching bool) {
+	m.dragDY = dy
+	m.touching = touching
+}
+
+func (m *mockRenderer) SetTouchMode(enabled bool) {
+	m.touchMode = enabled
+}
+
 func (m *mockRenderer) Update() {}
 
+func (m *mockRenderer) Resize(width, height int, scale float64) {
+	m.width, m.height, m.scale = width, height, scale
+}
+
 func (m *mockRenderer) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return 640, 480
 }
@@ -198,10 +362,21 @@ func (m *mockRenderer) Layout(outsideWidth, outsideHeight int) (int, int) {
 // ポップアップ関連のメソッド
 func (m *mockRenderer) ShowPopup(message string) {
 	m.popupActive = true
+	m.popupMessage = message
+}
+
+func (m *mockRenderer) ShowToast(message string) {
+	m.toastMessage = message
+}
+
+func (m *mockRenderer) ShowDialog(message string, options []string, onChoose func(choice int)) {
+	m.dialogMessage = message
+	m.dialogOptions = options
+	m.onChoose = onChoose
 }
 
 func (m *mockRenderer) GetPopupMessage() string {
-	return ""
+	return m.popupMessage
 }
 
 // カーソルとページ管理のメソッド
@@ -215,11 +390,11 @@ func (m *mockRenderer) GetCurrentPage() int {
 
 // Debug related methods
 func (m *mockRenderer) DebugMessage(message string) {
-	// Process to set debug message
+	m.debugMessage = message
 }
 
 func (m *mockRenderer) GetDebugMessage() string {
-	return ""
+	return m.debugMessage
 }
 
 func (m *mockRenderer) DebugPrint(screen *ebiten.Image) {
@@ -233,6 +408,7 @@ var _ = Describe("Game", func() {
 		testConfig    *config.Config
 		testGameState state.GameState
 		testStorage   *mockStorage
+		testTransfer  *mockTransfer
 		testHandler   *mockInputHandler
 		testRenderer  *mockRenderer
 		mockScreen    *ebiten.Image
@@ -250,12 +426,13 @@ var _ = Describe("Game", func() {
 		// Setup mocks
 		testGameState = &mockGameState{}
 		testStorage = &mockStorage{}
+		testTransfer = &mockTransfer{}
 		testHandler = &mockInputHandler{}
 		testRenderer = &mockRenderer{}
 		mockScreen = ebiten.NewImage(testConfig.ScreenWidth, testConfig.ScreenHeight)
 
 		// Create game with dependencies
-		testGame = NewGame(testConfig, testGameState, testStorage, testRenderer, testHandler)
+		testGame = NewGame(testConfig, testGameState, testStorage, testTransfer, testRenderer, testHandler)
 
 		// Override game dependencies with our mocks for testing
 		// Note: This would require exposing fields or adding a method for testing
@@ -270,7 +447,7 @@ var _ = Describe("Game", func() {
 			// In a real test, we'd need to inject this mock somehow
 			// For now, we're testing that NewGame doesn't panic
 			Expect(func() {
-				_ = NewGame(testConfig, testGameState, storage, testRenderer, testHandler)
+				_ = NewGame(testConfig, testGameState, storage, testTransfer, testRenderer, testHandler)
 			}).NotTo(Panic())
 		})
 
@@ -280,7 +457,7 @@ var _ = Describe("Game", func() {
 
 			// Again, in a real test, we'd need to inject this mock
 			Expect(func() {
-				_ = NewGame(testConfig, gameState, storage, testRenderer, testHandler)
+				_ = NewGame(testConfig, gameState, storage, testTransfer, testRenderer, testHandler)
 			}).NotTo(Panic())
 		})
 	})
@@ -290,17 +467,131 @@ var _ = Describe("Game", func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 			defer cancel()
 
-			// For a real test, we'd need to:
-			// 1. Inject our mock storage
-			// 2. Call StartAutoSave with a very short interval
-			// 3. Wait to see if SaveGameState gets called
-
-			// However, since we can't directly inject mocks in this example:
+			testConfig.AutoSaveInterval = 50 * time.Millisecond
 			Expect(func() {
-				testGame.StartAutoSave(ctx, 50*time.Millisecond)
+				testGame.StartAutoSave(ctx)
 				time.Sleep(120 * time.Millisecond) // Wait for auto-save to trigger
 			}).NotTo(Panic())
 		})
+
+		It("should save once more and end the game when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			testConfig.AutoSaveInterval = time.Hour
+			testGame.StartAutoSave(ctx)
+			Expect(testGame.Update()).To(Succeed())
+
+			cancel()
+			Eventually(testGame.Update).Should(MatchError(ebiten.Termination))
+			Expect(testStorage.saveCount).To(Equal(1))
+		})
+
+		It("should write a snapshot taken by Update instead of the live game state", func() {
+			gameState := state.NewGameState()
+			gameState.UpdateMoney(10)
+			testGame = NewGame(testConfig, gameState, testStorage, testTransfer, testRenderer, testHandler)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			testConfig.AutoSaveInterval = 10 * time.Millisecond
+			testGame.StartAutoSave(ctx)
+
+			Eventually(func() state.GameState {
+				Expect(testGame.Update()).To(Succeed())
+				testGame.saveMu.Lock()
+				defer testGame.saveMu.Unlock()
+				return testStorage.savedGameState
+			}).ShouldNot(BeNil())
+			testGame.saveMu.Lock()
+			defer testGame.saveMu.Unlock()
+			Expect(testStorage.savedGameState).NotTo(BeIdenticalTo(gameState))
+			Expect(testStorage.savedGameState.GetMoney()).To(Equal(10.0))
+		})
+
+		It("should make the final save without Update once the game loop has ended", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			testConfig.AutoSaveInterval = time.Hour
+			testGame.StartAutoSave(ctx)
+
+			cancel()
+			testGame.WaitAutoSave()
+			Expect(testStorage.saveCount).To(Equal(1))
+			Expect(testStorage.finalSaves).To(Equal(1))
+		})
+
+		It("should not write a snapshot taken before an import", func() {
+			testTransfer.importText = "clicker:imported"
+			testGame.snapshotDue.Store(true)
+			testHandler.SetPressedKey(input.KeyTypeImport)
+			Expect(testGame.Update()).To(Succeed())
+			testRenderer.onChoose(0)
+
+			Expect(testGame.saveSnapshot(<-testGame.snapshots, testStorage.SaveGameState)).To(Succeed())
+			Expect(testStorage.saveCount).To(Equal(0))
+		})
+
+		It("should follow a changed interval", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			testConfig.AutoSaveInterval = time.Hour
+			testGame.StartAutoSave(ctx)
+
+			testConfig.AutoSaveInterval = 10 * time.Millisecond
+			Eventually(func() int {
+				// Update hands the snapshots to the auto-save
+				Expect(testGame.Update()).To(Succeed())
+				testGame.saveMu.Lock()
+				defer testGame.saveMu.Unlock()
+				return testStorage.saveCount
+			}).Should(BeNumerically(">=", 1))
+		})
+	})
+
+	Describe("Saving", func() {
+		It("should save when the window is closed", func() {
+			testHandler.closeRequested = true
+			Expect(testGame.Update()).To(MatchError(ebiten.Termination))
+			Expect(testStorage.saveCount).To(Equal(1))
+		})
+
+		It("should save on the save key and show a toast", func() {
+			testHandler.SetPressedKey(input.KeyTypeSave)
+			Expect(testGame.Update()).To(Succeed())
+
+			Expect(testStorage.saveCount).To(Equal(1))
+			Expect(testRenderer.toastMessage).To(Equal("Game saved!"))
+			Expect(testRenderer.IsPopupActive()).To(BeFalse())
+		})
+
+		It("should report a failed save", func() {
+			testStorage.saveErr = errors.New("disk full")
+			testHandler.SetPressedKey(input.KeyTypeSave)
+			Expect(testGame.Update()).To(Succeed())
+
+			Expect(testRenderer.toastMessage).To(Equal("Failed to save game!"))
+		})
+
+		It("should hand every action that changed the game to the auto-save with the journal", func() {
+			testConfig.Journal = true
+			testRenderer.gameStateChanged = true
+			Expect(testGame.Update()).To(Succeed())
+			Expect(testGame.snapshots).To(HaveLen(1))
+			Expect(testStorage.saveCount).To(BeZero())
+
+			Expect(testGame.saveSnapshot(<-testGame.snapshots, testStorage.SaveGameState)).To(Succeed())
+			Expect(testStorage.saveCount).To(Equal(1))
+		})
+
+		It("should not save after an action that changed nothing", func() {
+			testConfig.Journal = true
+			testHandler.SetPressedKey(input.KeyTypeDecision)
+			Expect(testGame.Update()).To(Succeed())
+			Expect(testGame.snapshots).To(BeEmpty())
+		})
+
+		It("should not save after actions without the journal", func() {
+			testRenderer.gameStateChanged = true
+			Expect(testGame.Update()).To(Succeed())
+			Expect(testGame.snapshots).To(BeEmpty())
+		})
 	})
 
 	Describe("Update", func() {
@@ -328,6 +619,183 @@ var _ = Describe("Game", func() {
 				Expect(err).To(BeNil())
 			}).NotTo(Panic())
 		})
+
+		It("should pass the drag and the touch mode to the renderer", func() {
+			testHandler.dragDY = -12
+			testHandler.touching = true
+			testHandler.touchUsed = true
+
+			Expect(testGame.Update()).To(Succeed())
+			Expect(testRenderer.dragDY).To(Equal(-12.0))
+			Expect(testRenderer.touching).To(BeTrue())
+			Expect(testRenderer.touchMode).To(BeTrue())
+		})
+
+		It("should show the recent log lines as the debug message", func() {
+			overlay := logging.NewOverlay(logging.OverlayLines)
+			_, _ = overlay.Write([]byte("level=WARN msg=\"Auto-save failed\"\n"))
+			testGame.SetLogOverlay(overlay)
+
+			Expect(testGame.Update()).To(Succeed())
+			Expect(testRenderer.GetDebugMessage()).To(Equal("level=WARN msg=\"Auto-save failed\""))
+		})
+
+		It("should ask for a restart if debug mode was turned on after startup", func() {
+			Expect(testGame.Update()).To(Succeed())
+			Expect(testRenderer.GetDebugMessage()).To(Equal("Restart the game to show the recent log lines here"))
+		})
+	})
+
+	Describe("Load report", func() {
+		It("should not show a dialog after a clean load", func() {
+			testGame.ShowLoadReport()
+			Expect(testRenderer.dialogMessage).To(BeEmpty())
+		})
+
+		It("should explain the recovery and restore the chosen backup", func() {
+			testStorage.report = storage.LoadReport{
+				Outcome:        storage.LoadRecovered,
+				Problem:        errors.New("unexpected end of JSON input"),
+				RestoredBackup: 1,
+				BrokenSaveKey:  "save.json.corrupt.bak",
+				Backups:        []storage.BackupSummary{{Number: 3, Money: 10}},
+			}
+			testGame.ShowLoadReport()
+
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("restored from backup 1"))
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("unexpected end of JSON input"))
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("save.json.corrupt.bak"))
+			Expect(testRenderer.dialogOptions).To(HaveLen(2))
+			Expect(testRenderer.dialogOptions[0]).To(HavePrefix("Restore backup 3"))
+			Expect(testRenderer.dialogOptions[1]).To(Equal("Keep this game"))
+
+			testRenderer.onChoose(0)
+			Expect(testStorage.restoredBackup).To(Equal(3))
+			Expect(testRenderer.toastMessage).To(Equal("Backup 3 restored!"))
+		})
+
+		It("should tell about content left out of a clean load", func() {
+			testStorage.report = storage.LoadReport{
+				Outcome:        storage.LoadClean,
+				UnknownContent: []string{"building removed_miner"},
+				BrokenSaveKey:  "save.json.corrupt.bak",
+			}
+			testGame.ShowLoadReport()
+
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("Left out: building removed_miner."))
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("The original save is kept in:\nsave.json.corrupt.bak"))
+			Expect(testRenderer.dialogMessage).NotTo(ContainSubstring("no other backup"))
+			Expect(testRenderer.dialogOptions).To(Equal([]string{"Keep this game"}))
+		})
+
+		It("should keep the game on the last choice", func() {
+			testStorage.report = storage.LoadReport{
+				Outcome: storage.LoadFailed,
+				Problem: errors.New("invalid character"),
+				Backups: []storage.BackupSummary{{Number: 2, Money: 10}},
+			}
+			testGame.ShowLoadReport()
+
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("A new game was started."))
+			Expect(testRenderer.dialogOptions).To(HaveLen(2))
+			Expect(testRenderer.dialogOptions[1]).To(Equal("Keep the new game"))
+			testRenderer.onChoose(1)
+			Expect(testStorage.restoredBackup).To(BeZero())
+		})
+	})
+
+	Describe("Export and import", func() {
+		It("should export the save through the transfer driver", func() {
+			testHandler.SetPressedKey(input.KeyTypeExport)
+			Expect(testGame.Update()).To(Succeed())
+
+			Expect(testTransfer.exportedText).To(Equal("clicker:exported"))
+			Expect(testRenderer.GetPopupMessage()).To(Equal("Save exported to mock!"))
+		})
+
+		It("should import the save from the transfer driver once confirmed", func() {
+			testTransfer.importText = "clicker:imported"
+			testHandler.SetPressedKey(input.KeyTypeImport)
+			Expect(testGame.Update()).To(Succeed())
+
+			Expect(testRenderer.dialogMessage).To(ContainSubstring("Import the save from mock?"))
+			Expect(testStorage.importedText).To(BeEmpty())
+
+			testRenderer.onChoose(0)
+			Expect(testStorage.importedText).To(Equal("clicker:imported"))
+			Expect(testRenderer.GetPopupMessage()).To(Equal("Save imported successfully!"))
+		})
+
+		It("should keep the game when the import is cancelled", func() {
+			testTransfer.importText = "clicker:imported"
+			testHandler.SetPressedKey(input.KeyTypeImport)
+			Expect(testGame.Update()).To(Succeed())
+
+			testRenderer.onChoose(1)
+			Expect(testStorage.importedText).To(BeEmpty())
+			Expect(testRenderer.GetPopupMessage()).To(BeEmpty())
+		})
+
+		Context("with the renderer of the game", func() {
+			var renderer *presentation.DefaultRenderer
+
+			// clickImportDialog opens the import dialog and clicks the option
+			clickImportDialog := func(option int) {
+				testHandler.SetPressedKey(input.KeyTypeImport)
+				Expect(testGame.Update()).To(Succeed())
+				testGame.Draw(mockScreen)
+				testHandler.SetPressedKey(input.KeyTypeNone)
+				testHandler.clicked = true
+				testHandler.mouseX, testHandler.mouseY = renderer.GetDialogOptionPosition(option)
+				Expect(testGame.Update()).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				gameState := state.NewGameState()
+				preferences := storage.NewPreferencesStorage(driver.NewMemoryStore().NewStorageDriver("preferences.json"))
+				r, err := presentation.NewRenderer(testConfig,
+					usecase.NewPlayerUsecase(gameState),
+					usecase.NewManualWorkUseCase(gameState),
+					usecase.NewBuildingUseCase(gameState),
+					usecase.NewUpgradeUseCase(gameState),
+					usecase.NewMarketUseCase(gameState),
+					usecase.NewPreferencesUseCase(preferences, testConfig, model.DefaultPreferences()),
+					input.DefaultBindings(),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				renderer = r.(*presentation.DefaultRenderer)
+				testGame = NewGame(testConfig, gameState, testStorage, testTransfer, renderer, testHandler)
+				testTransfer.importText = "clicker:imported"
+			})
+
+			It("should keep the game when Cancel is clicked", func() {
+				clickImportDialog(1)
+				Expect(renderer.IsPopupActive()).To(BeFalse())
+				Expect(testStorage.importedText).To(BeEmpty())
+			})
+
+			It("should import when Import is clicked", func() {
+				clickImportDialog(0)
+				Expect(testStorage.importedText).To(Equal("clicker:imported"))
+			})
+		})
+
+		It("should report an invalid save string", func() {
+			testStorage.importErr = errors.New("invalid")
+			testHandler.SetPressedKey(input.KeyTypeImport)
+			Expect(testGame.Update()).To(Succeed())
+			testRenderer.onChoose(0)
+
+			Expect(testRenderer.GetPopupMessage()).To(Equal("Invalid save string!"))
+		})
+
+		It("should not export while a popup is active", func() {
+			testRenderer.popupActive = true
+			testHandler.SetPressedKey(input.KeyTypeExport)
+			Expect(testGame.Update()).To(Succeed())
+
+			Expect(testTransfer.exportedText).To(BeEmpty())
+		})
 	})
 
 	Describe("Draw", func() {
@@ -343,10 +811,21 @@ var _ = Describe("Game", func() {
 	})
 
 	Describe("Layout", func() {
-		It("should return the correct screen dimensions", func() {
-			width, height := testGame.Layout(800, 600)
-			Expect(width).To(Equal(testConfig.ScreenWidth))
-			Expect(height).To(Equal(testConfig.ScreenHeight))
+		It("should follow the size of the window", func() {
+			testGame.deviceScale = func() float64 { return 1 }
+			width, height := testGame.Layout(1024, 768)
+			Expect(width).To(Equal(1024))
+			Expect(height).To(Equal(768))
+			Expect(testRenderer.width).To(Equal(1024))
+			Expect(testRenderer.height).To(Equal(768))
+		})
+
+		It("should use device pixels on high DPI displays", func() {
+			testGame.deviceScale = func() float64 { return 1.5 }
+			width, height := testGame.Layout(801, 600)
+			Expect(width).To(Equal(1202))
+			Expect(height).To(Equal(900))
+			Expect(testRenderer.scale).To(Equal(1.5))
 		})
 	})
 
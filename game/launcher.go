@@ -0,0 +1,81 @@
+package game
+
+import (
+	"context"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/presentation"
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Launcher shows the slot picker and starts the game once a slot is chosen
+type Launcher struct {
+	ctx          context.Context // Ends the launcher while the picker is shown
+	config       *config.Config
+	picker       presentation.SlotPicker
+	inputHandler input.Handler
+	start        func(slot string) (*Game, error) // Builds the game for the chosen slot
+	game         *Game
+	deviceScale  func() float64 // Device scale factor of the monitor, replaced in tests
+}
+
+func NewLauncher(ctx context.Context, c *config.Config, picker presentation.SlotPicker, inputHandler input.Handler, start func(slot string) (*Game, error)) *Launcher {
+	return &Launcher{
+		ctx:          ctx,
+		config:       c,
+		picker:       picker,
+		inputHandler: inputHandler,
+		start:        start,
+		deviceScale:  deviceScaleFactor,
+	}
+}
+
+func (l *Launcher) Update() error {
+	if l.game != nil {
+		return l.game.Update()
+	}
+
+	if l.ctx.Err() != nil {
+		return ebiten.Termination
+	}
+	l.inputHandler.SetTextMode(l.picker.IsTyping())
+	l.inputHandler.Update()
+	defer l.inputHandler.ResetClickState()
+	if l.inputHandler.IsCloseRequested() {
+		// No game has been loaded yet, so there is nothing to save
+		return ebiten.Termination
+	}
+
+	mouseX, mouseY := l.inputHandler.GetMouseCursor()
+	l.picker.HandleInput(l.inputHandler.GetPressedKey(), l.inputHandler.GetInputChars(), l.inputHandler.IsClicked(), mouseX, mouseY)
+	slot, ok := l.picker.Selected()
+	if !ok {
+		return nil
+	}
+	g, err := l.start(slot)
+	if err != nil {
+		return err
+	}
+	l.game = g
+	return nil
+}
+
+func (l *Launcher) Draw(screen *ebiten.Image) {
+	if l.game != nil {
+		l.game.Draw(screen)
+		return
+	}
+	l.picker.Draw(screen)
+}
+
+func (l *Launcher) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if l.game != nil {
+		return l.game.Layout(outsideWidth, outsideHeight)
+	}
+	scale := l.deviceScale()
+	width, height := screenSize(outsideWidth, outsideHeight, scale)
+	l.picker.Resize(width, height, scale)
+	return width, height
+}
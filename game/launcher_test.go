@@ -0,0 +1,171 @@
+package game
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/presentation"
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type mockSlotPicker struct {
+	selected    string
+	isSelected  bool
+	drawCalled  bool
+	lastHandled input.KeyType
+	width       int
+	height      int
+	typing      bool
+}
+
+func (m *mockSlotPicker) Update() {}
+
+func (m *mockSlotPicker) Draw(screen *ebiten.Image) {
+	m.drawCalled = true
+}
+
+func (m *mockSlotPicker) Resize(width, height int, scale float64) {
+	m.width, m.height = width, height
+}
+
+func (m *mockSlotPicker) HandleInput(keyType input.KeyType, chars []rune, isClicked bool, mouseX, mouseY int) {
+	m.lastHandled = keyType
+}
+
+func (m *mockSlotPicker) Selected() (string, bool) {
+	return m.selected, m.isSelected
+}
+
+func (m *mockSlotPicker) IsTyping() bool {
+	return m.typing
+}
+
+type mockSlotUseCase struct {
+	slots []dto.Slot
+}
+
+func (m *mockSlotUseCase) GetSlots() []dto.Slot {
+	return m.slots
+}
+
+func (m *mockSlotUseCase) CreateSlotAction(name string) (bool, string) {
+	return false, ""
+}
+
+func (m *mockSlotUseCase) CopySlotAction(cursor int) (bool, string) {
+	return false, ""
+}
+
+func (m *mockSlotUseCase) RenameSlotAction(cursor int, name string) (bool, string) {
+	return false, ""
+}
+
+func (m *mockSlotUseCase) DeleteSlotAction(cursor int) (bool, string) {
+	return false, ""
+}
+
+var _ = Describe("Launcher", func() {
+	var (
+		testConfig   *config.Config
+		testPicker   *mockSlotPicker
+		testHandler  *mockInputHandler
+		startedSlots []string
+		startErr     error
+		launcher     *Launcher
+		ctx          context.Context
+		cancel       context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		testConfig = config.NewConfig()
+		testPicker = &mockSlotPicker{}
+		testHandler = &mockInputHandler{}
+		startedSlots = nil
+		startErr = nil
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		launcher = NewLauncher(ctx, testConfig, testPicker, testHandler, func(slot string) (*Game, error) {
+			startedSlots = append(startedSlots, slot)
+			if startErr != nil {
+				return nil, startErr
+			}
+			return NewGame(testConfig, &mockGameState{}, &mockStorage{}, &mockTransfer{}, &mockRenderer{}, testHandler), nil
+		})
+	})
+
+	It("should end when the window is closed in the picker", func() {
+		testHandler.closeRequested = true
+		Expect(launcher.Update()).To(MatchError(ebiten.Termination))
+		Expect(startedSlots).To(BeEmpty())
+	})
+
+	It("should end when the context is cancelled in the picker", func() {
+		cancel()
+		Expect(launcher.Update()).To(MatchError(ebiten.Termination))
+	})
+
+	It("should pass the input to the picker until a slot is selected", func() {
+		testHandler.SetPressedKey(input.KeyTypeDown)
+		Expect(launcher.Update()).To(Succeed())
+
+		Expect(testPicker.lastHandled).To(Equal(input.KeyTypeDown))
+		Expect(startedSlots).To(BeEmpty())
+	})
+
+	It("should put the input in text mode while a slot name is typed", func() {
+		testPicker.typing = true
+		Expect(launcher.Update()).To(Succeed())
+		Expect(testHandler.textMode).To(BeTrue())
+
+		testPicker.typing = false
+		Expect(launcher.Update()).To(Succeed())
+		Expect(testHandler.textMode).To(BeFalse())
+	})
+
+	It("should start the game for the selected slot once", func() {
+		testPicker.selected = "balance"
+		testPicker.isSelected = true
+
+		Expect(launcher.Update()).To(Succeed())
+		Expect(launcher.Update()).To(Succeed())
+		Expect(startedSlots).To(Equal([]string{"balance"}))
+	})
+
+	It("should lay the picker out for the size of the window", func() {
+		launcher.deviceScale = func() float64 { return 2 }
+		width, height := launcher.Layout(640, 480)
+		Expect(width).To(Equal(1280))
+		Expect(height).To(Equal(960))
+		Expect(testPicker.width).To(Equal(1280))
+		Expect(testPicker.height).To(Equal(960))
+	})
+
+	It("should return the error if the game cannot be started", func() {
+		testPicker.isSelected = true
+		startErr = errors.New("newer save")
+
+		Expect(launcher.Update()).To(MatchError("newer save"))
+	})
+
+	It("should start the slot tapped twice in the picker", func() {
+		picker, err := presentation.NewSlotPicker(testConfig, &mockSlotUseCase{
+			slots: []dto.Slot{{Name: config.DefaultSlot}, {Name: "balance"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		launcher.picker = picker
+		testHandler.SetPressedKey(input.KeyTypeNone)
+		testHandler.clicked = true
+		testHandler.mouseX, testHandler.mouseY = picker.(*presentation.DefaultSlotPicker).GetSlotPosition(1)
+
+		Expect(launcher.Update()).To(Succeed())
+		Expect(startedSlots).To(BeEmpty())
+		Expect(launcher.Update()).To(Succeed())
+		Expect(startedSlots).To(Equal([]string{"balance"}))
+	})
+})
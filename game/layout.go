@@ -0,0 +1,24 @@
+package game
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// deviceScaleFactor returns the device pixels per window pixel of the monitor the window is on
+func deviceScaleFactor() float64 {
+	if m := ebiten.Monitor(); m != nil {
+		return m.DeviceScaleFactor()
+	}
+	return 1
+}
+
+// screenSize returns the screen size in device pixels for the size of the window or the browser canvas,
+// so that the layout uses the whole window and the text stays sharp on high DPI displays
+func screenSize(outsideWidth, outsideHeight int, scale float64) (int, int) {
+	if scale <= 0 {
+		scale = 1
+	}
+	return int(math.Ceil(float64(outsideWidth) * scale)), int(math.Ceil(float64(outsideHeight) * scale))
+}
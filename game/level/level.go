@@ -6,6 +6,21 @@ import (
 	"github.com/kmdkuk/clicker/domain/model"
 )
 
+// building_ids are stored in saves, so an ID must never change or be reused.
+// Rename or remove content through the content migrations in infrastructure/storage instead.
+var building_ids = []string{
+	"cpu_miner",
+	"gpu_rig",
+	"asic_miner",
+	"mining_farm",
+	"staking_pool",
+	"dex_platform",
+	"layer2_network",
+	"blockchain_validator",
+	"quantum_mining_cluster",
+	"ai_trading_algorithm",
+}
+
 var building_names = []string{
 	"CPU Miner",
 	"GPU Rig",
@@ -45,15 +60,75 @@ var building_base_generate_rates = []float64{
 	160000.0,
 }
 
+var building_is_mining = []bool{
+	true,
+	true,
+	true,
+	true,
+	false,
+	false,
+	false,
+	false,
+	true,
+	false,
+}
+
+var building_base_upkeeps = []float64{
+	0.002,
+	0.02,
+	0.16,
+	0.94,
+	2.6,
+	14.0,
+	78.0,
+	440.0,
+	5200.0,
+	16000.0,
+}
+
+var building_tier_suffixes = []string{
+	"",
+	" mk2",
+	" mk3",
+}
+
+var building_tier_rate_multipliers = []float64{
+	1,
+	3,
+	10,
+}
+
+var building_tier_cost_multipliers = []float64{
+	0,
+	1000,
+	100000,
+}
+
+func newBuildingTiers(i int) []model.BuildingTier {
+	tiers := make([]model.BuildingTier, len(building_tier_suffixes))
+	for j := range tiers {
+		tiers[j] = model.BuildingTier{
+			Name:                   building_names[i] + building_tier_suffixes[j],
+			GenerateRateMultiplier: building_tier_rate_multipliers[j],
+			Cost:                   building_base_costs[i] * building_tier_cost_multipliers[j],
+		}
+	}
+	return tiers
+}
+
 func NewBuildings() []model.Building {
 	buildings := make([]model.Building, len(building_names))
 	for i := 0; i < len(building_names); i++ {
 		buildings[i] = model.Building{
 			ID:               i,
+			Key:              building_ids[i],
 			Name:             building_names[i],
 			BaseCost:         building_base_costs[i],
 			BaseGenerateRate: building_base_generate_rates[i],
 			Count:            0,
+			IsMining:         building_is_mining[i],
+			BaseUpkeep:       building_base_upkeeps[i],
+			Tiers:            newBuildingTiers(i),
 		}
 	}
 	return buildings
@@ -102,7 +177,7 @@ func newBuildingUpgrade() []model.Upgrade {
 	for i := 0; i < len(building_names); i++ {
 		for j := 0; j < upgrtade_count_per_unit; j++ {
 			upgrades = append(upgrades, model.Upgrade{
-				ID:                 fmt.Sprintf("%d_%d", i, j),
+				ID:                 fmt.Sprintf("%s_%d", building_ids[i], j),
 				Name:               fmt.Sprintf("%s Upgrade %d", building_names[i], j+1),
 				Cost:               building_base_costs[i] * upgrade_base_cost_multiplier[j],
 				TargetBuilding:     i,
@@ -120,6 +195,42 @@ func newBuildingUpgrade() []model.Upgrade {
 	return upgrades
 }
 
+var efficiency_upgrade_unlock_count = []int{
+	10,
+	50,
+	150,
+}
+
+var efficiency_upgrade_cost_multiplier = []float64{
+	100,
+	10000,
+	1000000,
+}
+
+func newEfficiencyUpgrade() []model.Upgrade {
+	var upgrades []model.Upgrade
+	for i := 0; i < len(building_names); i++ {
+		for j := 0; j < len(efficiency_upgrade_unlock_count); j++ {
+			upgrades = append(upgrades, model.Upgrade{
+				ID:                 fmt.Sprintf("efficiency_%s_%d", building_ids[i], j),
+				Name:               fmt.Sprintf("%s Efficiency %d", building_names[i], j+1),
+				Cost:               building_base_costs[i] * efficiency_upgrade_cost_multiplier[j],
+				TargetBuilding:     i,
+				IsTargetManualWork: false,
+				IsTargetUpkeep:     true,
+				IsPurchased:        false,
+				Effect: func(upkeep float64) float64 {
+					return upkeep * 0.5
+				},
+				IsReleased: func(g model.GameStateReader) bool {
+					return g.GetBuildings()[i].Count >= efficiency_upgrade_unlock_count[j]
+				},
+			})
+		}
+	}
+	return upgrades
+}
+
 func newManualWorkUpgrade() []model.Upgrade {
 	var upgrades []model.Upgrade
 	for i := 0; i < upgrtade_count_per_unit; i++ {
@@ -144,5 +255,6 @@ func newManualWorkUpgrade() []model.Upgrade {
 func NewUpgrades() []model.Upgrade {
 	upgrades := newBuildingUpgrade()
 	upgrades = append(upgrades, newManualWorkUpgrade()...)
+	upgrades = append(upgrades, newEfficiencyUpgrade()...)
 	return upgrades
 }
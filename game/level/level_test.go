@@ -10,9 +10,15 @@ const buildings_count = 10
 var _ = Describe("Level", func() {
 	Describe("NewBuildings", func() {
 		It("correct buildings_*", func() {
+			Expect(len(building_ids)).To(Equal(buildings_count))
 			Expect(len(building_names)).To(Equal(buildings_count))
 			Expect(len(building_base_costs)).To(Equal(buildings_count))
 			Expect(len(building_base_generate_rates)).To(Equal(buildings_count))
+			Expect(len(building_is_mining)).To(Equal(buildings_count))
+			Expect(len(building_base_upkeeps)).To(Equal(buildings_count))
+			for i := 0; i < buildings_count; i++ {
+				Expect(building_base_upkeeps[i]).To(BeNumerically("<", building_base_generate_rates[i]), "Upkeep should never exceed the income of a building")
+			}
 			for i := 0; i < buildings_count-1; i++ {
 				Expect(building_base_costs[i]).To(BeNumerically("<", building_base_costs[i+1]), "Buildings should have increasing base costs")
 				Expect(building_base_generate_rates[i]).To(BeNumerically("<", building_base_generate_rates[i+1]), "Buildings should have increasing generate rates")
@@ -24,5 +30,57 @@ var _ = Describe("Level", func() {
 				NewBuildings()
 			}).NotTo(Panic())
 		})
+
+		It("should have unique keys", func() {
+			keys := map[string]bool{}
+			for _, b := range NewBuildings() {
+				Expect(b.Key).NotTo(BeEmpty())
+				Expect(keys).NotTo(HaveKey(b.Key))
+				keys[b.Key] = true
+			}
+		})
+
+		It("should give every building increasingly strong and expensive tiers", func() {
+			Expect(len(building_tier_rate_multipliers)).To(Equal(len(building_tier_suffixes)))
+			Expect(len(building_tier_cost_multipliers)).To(Equal(len(building_tier_suffixes)))
+			for _, b := range NewBuildings() {
+				Expect(b.Tiers).To(HaveLen(len(building_tier_suffixes)))
+				Expect(b.Tiers[0].Name).To(Equal(b.Name))
+				for j := 1; j < len(b.Tiers); j++ {
+					Expect(b.Tiers[j].GenerateRateMultiplier).To(BeNumerically(">", b.Tiers[j-1].GenerateRateMultiplier))
+					Expect(b.Tiers[j].Cost).To(BeNumerically(">", b.Tiers[j-1].Cost))
+				}
+			}
+		})
+	})
+
+	Describe("NewUpgrades", func() {
+		It("should have unique IDs", func() {
+			ids := map[string]bool{}
+			for _, u := range NewUpgrades() {
+				Expect(ids).NotTo(HaveKey(u.ID))
+				ids[u.ID] = true
+			}
+		})
+
+		It("should key building upgrades by the building instead of its position", func() {
+			for _, u := range NewUpgrades() {
+				if u.TargetBuilding >= 0 {
+					Expect(u.ID).To(ContainSubstring(building_ids[u.TargetBuilding] + "_"))
+				}
+			}
+		})
+
+		It("should add efficiency upgrades targeting the upkeep of every building", func() {
+			count := 0
+			for _, u := range NewUpgrades() {
+				if u.IsTargetUpkeep {
+					Expect(u.TargetBuilding).To(BeNumerically(">=", 0))
+					Expect(u.Effect(1.0)).To(BeNumerically("<", 1.0))
+					count++
+				}
+			}
+			Expect(count).To(Equal(buildings_count * len(efficiency_upgrade_unlock_count)))
+		})
 	})
 })
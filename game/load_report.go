@@ -0,0 +1,89 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kmdkuk/clicker/infrastructure/storage"
+	"github.com/kmdkuk/clicker/presentation/formatter"
+)
+
+// ShowLoadReport tells the player when the save could not be loaded as it was saved,
+// and lets them keep the game or restore one of the backups instead
+func (g *Game) ShowLoadReport() {
+	report := g.storage.LastLoadReport()
+	message, options := loadReportDialog(report)
+	if message == "" {
+		return
+	}
+	g.renderer.ShowDialog(message, options, func(choice int) {
+		if choice < 0 || choice >= len(report.Backups) {
+			return
+		}
+		g.renderer.ShowToast(g.restoreBackup(report.Backups[choice].Number))
+	})
+}
+
+// restoreBackup replaces the game state with the n-th backup and returns the message to show
+func (g *Game) restoreBackup(n int) string {
+	g.saveMu.Lock()
+	defer g.saveMu.Unlock()
+	if err := g.storage.RestoreBackupInto(g.gameState, n); err != nil {
+		slog.Warn("Restore failed", "backup", n, "error", err)
+		return fmt.Sprintf("Failed to restore backup %d!", n)
+	}
+	// Snapshots from before the restore must not overwrite it
+	g.generation++
+	return fmt.Sprintf("Backup %d restored!", n)
+}
+
+// loadReportDialog explains the report to the player. It returns an empty message if there is nothing to tell.
+// The options restore report.Backups in order and the last one keeps the loaded game.
+func loadReportDialog(report storage.LoadReport) (string, []string) {
+	var lines []string
+	keep := "Keep this game"
+	brokenSave := "The broken save is kept in:"
+	switch report.Outcome {
+	case storage.LoadClean:
+		if len(report.UnknownContent) == 0 {
+			return "", nil
+		}
+		lines = append(lines, "Your save has content this version of the game does not know.")
+		brokenSave = "The original save is kept in:"
+	case storage.LoadRecovered:
+		lines = append(lines, "Your save could not be loaded as it was saved.")
+		switch {
+		case report.RestoredBackup > 0:
+			lines = append(lines, fmt.Sprintf("The game was restored from backup %d.", report.RestoredBackup))
+		case report.Fixed:
+			lines = append(lines, "Invalid values were reset and the rest of the save was kept.")
+		default:
+			lines = append(lines, "Recovered from the damaged save: "+strings.Join(report.Recovered, ", ")+".")
+		}
+	case storage.LoadFailed:
+		lines = append(lines, "Your save could not be loaded and nothing could be recovered.", "A new game was started.")
+		keep = "Keep the new game"
+	default:
+		return "", nil
+	}
+	if len(report.UnknownContent) > 0 {
+		lines = append(lines, "Left out: "+strings.Join(report.UnknownContent, ", ")+".")
+	}
+	if report.Problem != nil {
+		lines = append(lines, "Problem: "+report.Problem.Error())
+	}
+	if report.BrokenSaveKey != "" {
+		lines = append(lines, brokenSave, report.BrokenSaveKey)
+	}
+	if len(report.Backups) == 0 && report.Outcome != storage.LoadClean {
+		lines = append(lines, "There is no other backup to restore.")
+	}
+
+	options := []string{}
+	for _, backup := range report.Backups {
+		options = append(options, fmt.Sprintf("Restore backup %d (Money: %s)", backup.Number, formatter.FormatCurrency(backup.Money, "$")))
+	}
+	// Keeping the game is the safe choice, so it comes last like Cancel
+	return strings.Join(lines, "\n"), append(options, keep)
+}
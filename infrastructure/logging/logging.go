@@ -0,0 +1,86 @@
+// Package logging routes the log output of the game to the sinks chosen in the config.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/kmdkuk/clicker/config"
+)
+
+// Setup makes slog.Default, and with it the standard log package, write to a file if cfg.LogFile is set
+// or to stderr otherwise. Debug records are only written in debug mode, where they are also shown
+// in the returned overlay (nil outside debug mode).
+// The returned function closes the log file.
+func Setup(cfg *config.Config) (*Overlay, func() error, error) {
+	level := slog.LevelInfo
+	if cfg.EnableDebug {
+		level = slog.LevelDebug
+	}
+
+	var w io.Writer = os.Stderr
+	closeLog := func() error { return nil }
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		w = f
+		closeLog = f.Close
+	}
+	handlers := []slog.Handler{slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})}
+
+	var overlay *Overlay
+	if cfg.EnableDebug {
+		overlay = NewOverlay(OverlayLines)
+		handlers = append(handlers, overlay.Handler(level))
+	}
+	slog.SetDefault(slog.New(Fanout(handlers...)))
+	return overlay, closeLog, nil
+}
+
+// Fanout returns a handler that passes each record to all the handlers that accept its level
+func Fanout(handlers ...slog.Handler) slog.Handler {
+	return fanout(handlers)
+}
+
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
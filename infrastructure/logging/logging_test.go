@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmdkuk/clicker/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+	BeforeEach(func() {
+		defaultLogger := slog.Default()
+		DeferCleanup(func() { slog.SetDefault(defaultLogger) })
+	})
+
+	Describe("Setup", func() {
+		var cfg *config.Config
+
+		BeforeEach(func() {
+			cfg = config.NewConfig()
+			cfg.LogFile = filepath.Join(GinkgoT().TempDir(), "clicker.log")
+		})
+
+		readLog := func() string {
+			data, err := os.ReadFile(cfg.LogFile)
+			Expect(err).NotTo(HaveOccurred())
+			return string(data)
+		}
+
+		It("should write info and above to the file without an overlay", func() {
+			overlay, closeLog, err := Setup(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overlay).To(BeNil())
+
+			slog.Debug("hidden dump")
+			slog.Info("loaded save")
+			log.Print("from the log package")
+			Expect(closeLog()).To(Succeed())
+
+			Expect(readLog()).NotTo(ContainSubstring("hidden dump"))
+			Expect(readLog()).To(ContainSubstring("loaded save"))
+			Expect(readLog()).To(ContainSubstring("from the log package"))
+		})
+
+		It("should write debug records to the file and the overlay in debug mode", func() {
+			cfg.EnableDebug = true
+			overlay, closeLog, err := Setup(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overlay).NotTo(BeNil())
+
+			slog.Debug("recovered money", "money", 10)
+			Expect(closeLog()).To(Succeed())
+
+			Expect(readLog()).To(ContainSubstring("recovered money"))
+			Expect(overlay.String()).To(Equal("level=DEBUG msg=\"recovered money\" money=10"))
+		})
+
+		It("should fail when the log file cannot be opened", func() {
+			cfg.LogFile = filepath.Join(GinkgoT().TempDir(), "missing", "clicker.log")
+			_, _, err := Setup(cfg)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Fanout", func() {
+		It("should pass the record to each handler accepting its level", func() {
+			var info, debug bytes.Buffer
+			logger := slog.New(Fanout(
+				slog.NewTextHandler(&info, &slog.HandlerOptions{Level: slog.LevelInfo}),
+				slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			)).With("slot", "default")
+
+			logger.Debug("detail")
+			logger.Info("summary")
+
+			Expect(info.String()).NotTo(ContainSubstring("detail"))
+			Expect(info.String()).To(ContainSubstring("summary"))
+			Expect(debug.String()).To(ContainSubstring("detail"))
+			Expect(strings.Count(debug.String(), "slot=default")).To(Equal(2))
+		})
+	})
+
+	Describe("Overlay", func() {
+		It("should keep the last lines", func() {
+			overlay := NewOverlay(2)
+			logger := slog.New(overlay.Handler(slog.LevelInfo))
+			logger.Info("first")
+			logger.Info("second")
+			logger.Info("third")
+
+			Expect(overlay.String()).To(Equal("level=INFO msg=second\nlevel=INFO msg=third"))
+		})
+	})
+})
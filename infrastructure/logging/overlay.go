@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// OverlayLines is the number of log lines the debug overlay shows
+const OverlayLines = 10
+
+// Overlay keeps the last log lines to show them on screen.
+// In the wasm build this is the only place the log can be seen without the browser console.
+type Overlay struct {
+	mu    sync.Mutex
+	lines []string
+	size  int
+}
+
+func NewOverlay(size int) *Overlay {
+	return &Overlay{
+		size: size,
+	}
+}
+
+// Handler returns a handler writing to the overlay. The time is left out to keep the lines short.
+func (o *Overlay) Handler(level slog.Leveler) slog.Handler {
+	return slog.NewTextHandler(o, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+// Write stores each line written by the handler, dropping the oldest lines beyond the size
+func (o *Overlay) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, line := range strings.Split(string(bytes.TrimRight(p, "\n")), "\n") {
+		o.lines = append(o.lines, line)
+	}
+	if len(o.lines) > o.size {
+		o.lines = append([]string(nil), o.lines[len(o.lines)-o.size:]...)
+	}
+	return len(p), nil
+}
+
+// String returns the stored lines, oldest first
+func (o *Overlay) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return strings.Join(o.lines, "\n")
+}
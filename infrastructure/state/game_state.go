@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/kmdkuk/clicker/domain/model"
@@ -14,6 +15,7 @@ type GameState interface {
 	UpdateBuildings(now time.Time)
 	GetBuildings() []model.Building
 	SetBuildingCount(buildingIndex int, count int) error
+	SetBuildingLevel(buildingIndex int, level int) error
 	GetUpgrades() []model.Upgrade
 	SetUpgrades(upgrades []model.Upgrade)
 	SetUpgradesIsPurchased(upgradeIndex int, isPurchased bool) error
@@ -21,6 +23,15 @@ type GameState interface {
 	GetMoney() float64
 	GetManualWork() *model.ManualWork
 	SetManualWorkCount(count int) error
+	GetCoins() float64
+	UpdateCoins(amount float64)
+	GetMarket() *model.Market
+	SetMarket(market model.Market)
+	GetTotalUpkeep() float64
+	GetThrottle() float64
+	IsTampered() bool
+	SetTampered(tampered bool)
+	Clone() GameState
 }
 
 // GameState はゲームの状態を管理します
@@ -29,7 +40,11 @@ type DefaultGameState struct {
 	ManualWork model.ManualWork `json:"manual_work"`
 	Buildings  []model.Building `json:"buildings"`
 	Upgrades   []model.Upgrade  `json:"upgrades"`
+	Coins      float64          `json:"coins"`
+	Market     model.Market     `json:"market"`
+	Throttle   float64          `json:"throttle"` // Share of capacity the buildings ran at during the last update
 	LastUpdate time.Time        `json:"last_update"`
+	Tampered   bool             `json:"tampered"` // The save was edited outside the game
 }
 
 func NewGameState() GameState {
@@ -38,6 +53,8 @@ func NewGameState() GameState {
 		ManualWork: model.ManualWork{Name: "Manual Work", BaseValue: 0.1, Count: 0},
 		Buildings:  level.NewBuildings(),
 		Upgrades:   level.NewUpgrades(),
+		Market:     model.NewMarket(time.Now().UnixNano()),
+		Throttle:   1,
 		LastUpdate: time.Now(),
 	}
 }
@@ -57,6 +74,17 @@ func (g *DefaultGameState) SetBuildingCount(buildingIndex int, count int) error
 	return nil
 }
 
+func (g *DefaultGameState) SetBuildingLevel(buildingIndex int, level int) error {
+	if buildingIndex < 0 || buildingIndex >= len(g.Buildings) {
+		return fmt.Errorf("invalid building index: %d", buildingIndex)
+	}
+	if level < 0 || level > g.Buildings[buildingIndex].MaxLevel() {
+		return fmt.Errorf("invalid building level: %d", level)
+	}
+	g.Buildings[buildingIndex].Level = level
+	return nil
+}
+
 func (g *DefaultGameState) GetUpgrades() []model.Upgrade {
 	return g.Upgrades
 }
@@ -109,23 +137,119 @@ func (g *DefaultGameState) UpdateMoney(amount float64) {
 	g.Money += amount
 }
 
+func (g *DefaultGameState) GetCoins() float64 {
+	return g.Coins
+}
+
+func (g *DefaultGameState) UpdateCoins(amount float64) {
+	g.Coins += amount
+}
+
+func (g *DefaultGameState) GetMarket() *model.Market {
+	return &g.Market
+}
+
+func (g *DefaultGameState) SetMarket(market model.Market) {
+	g.Market = market
+}
+
+// GetTotalGenerateRate returns the money generated per second, valuing mined coins at the current price
 func (g *DefaultGameState) GetTotalGenerateRate() float64 {
 	totalRate := 0.0
 	for _, building := range g.Buildings {
-		if building.IsUnlocked() {
-			totalRate += building.TotalGenerateRate(g.Upgrades)
+		if !building.IsUnlocked() {
+			continue
+		}
+		rate := building.TotalGenerateRate(g.Upgrades)
+		if building.IsMining {
+			rate = g.Market.ConvertToCoins(rate) * g.Market.Price
 		}
+		totalRate += rate
 	}
 	return totalRate
 }
 
+// GetTotalUpkeep returns the operating cost per second of all unlocked buildings
+func (g *DefaultGameState) GetTotalUpkeep() float64 {
+	totalUpkeep := 0.0
+	for _, building := range g.Buildings {
+		if building.IsUnlocked() {
+			totalUpkeep += building.TotalUpkeep(g.Upgrades)
+		}
+	}
+	return totalUpkeep
+}
+
+func (g *DefaultGameState) GetThrottle() float64 {
+	return g.Throttle
+}
+
+// UpdateBuildings generates income and pays the upkeep for the elapsed time.
+// Upkeep the money cannot cover is paid by selling the coins mined in the same update at the current price.
+// When neither can cover the upkeep, all buildings are throttled so that the money reaches zero
+// instead of going negative.
 func (g *DefaultGameState) UpdateBuildings(now time.Time) {
 	elapsed := now.Sub(g.LastUpdate).Seconds()
 	g.LastUpdate = now
 
+	moneyIncome, coinIncome, upkeep := 0.0, 0.0, 0.0
 	for _, building := range g.Buildings {
-		if building.IsUnlocked() {
-			g.UpdateMoney(building.GenerateIncome(elapsed, g.Upgrades))
+		if !building.IsUnlocked() {
+			continue
 		}
+		income := building.GenerateIncome(elapsed, g.Upgrades)
+		if building.IsMining {
+			coinIncome += g.Market.ConvertToCoins(income)
+		} else {
+			moneyIncome += income
+		}
+		upkeep += building.TotalUpkeep(g.Upgrades) * elapsed
+	}
+
+	price := g.Market.Price
+	g.Throttle = calcThrottle(g.Money, moneyIncome+coinIncome*price, upkeep)
+	money := g.Money + (moneyIncome-upkeep)*g.Throttle
+	coins := coinIncome * g.Throttle
+	if money < 0 && price > 0 {
+		// 足りない分は掘ったコインを売って払う
+		sold := math.Min(coins, -money/price)
+		coins -= sold
+		money += sold * price
+	}
+	// Rounding errors must not leave the player in debt
+	g.Money = math.Max(money, 0)
+	g.UpdateCoins(coins)
+	g.Market.Advance(elapsed)
+}
+
+// calcThrottle returns the largest share in [0, 1] the buildings can run at without sending money negative.
+// The income includes the value of the mined coins, which are sold to pay the upkeep when the money runs out.
+func calcThrottle(money, income, upkeep float64) float64 {
+	deficit := upkeep - income
+	if deficit <= 0 {
+		return 1
+	}
+	if money <= 0 {
+		return 0
 	}
+	return math.Min(1, money/deficit)
+}
+
+// IsTampered reports whether the state was loaded from a save edited outside the game
+func (g *DefaultGameState) IsTampered() bool {
+	return g.Tampered
+}
+
+func (g *DefaultGameState) SetTampered(tampered bool) {
+	g.Tampered = tampered
+}
+
+// Clone returns a copy of the state that shares nothing the game changes,
+// so that it can be saved on another goroutine while the game goes on
+func (g *DefaultGameState) Clone() GameState {
+	clone := *g
+	clone.Buildings = append([]model.Building(nil), g.Buildings...)
+	clone.Upgrades = append([]model.Upgrade(nil), g.Upgrades...)
+	clone.Market.History = append([]float64(nil), g.Market.History...)
+	return &clone
 }
@@ -19,6 +19,7 @@ var _ = Describe("DefaultGameState", func() {
 			ManualWork: model.ManualWork{Name: "Manual Work: $0.1", BaseValue: 0.1, Count: 0},
 			Buildings:  level.NewBuildings(),
 			Upgrades:   level.NewUpgrades(),
+			Market:     model.NewMarket(1),
 			LastUpdate: time.Now(),
 		} // Update to use gameState
 	})
@@ -39,11 +40,85 @@ var _ = Describe("DefaultGameState", func() {
 	Describe("updateBuildings", func() {
 		It("should generate income from unlocked buildings", func() {
 			now := time.Now()
+			gameState.Buildings[4].Count = 1                 // Unlock a non-mining building
+			gameState.LastUpdate = now.Add(-1 * time.Second) // Simulate 1 second elapsed
+
+			gameState.UpdateBuildings(now)
+			// The upkeep is paid from the income
+			Expect(gameState.GetMoney()).To(BeNumerically("~", gameState.Buildings[4].BaseGenerateRate-gameState.Buildings[4].BaseUpkeep, 0.00001))
+			Expect(gameState.GetThrottle()).To(Equal(1.0))
+		})
+
+		It("should generate coins from unlocked mining buildings", func() {
+			now := time.Now()
+			gameState.Money = 1.0                            // Enough money for the upkeep
 			gameState.Buildings[0].Count = 1                 // Unlock the first building
 			gameState.LastUpdate = now.Add(-1 * time.Second) // Simulate 1 second elapsed
 
 			gameState.UpdateBuildings(now)
-			Expect(gameState.GetMoney()).To(Equal(gameState.Buildings[0].BaseGenerateRate))
+			Expect(gameState.GetCoins()).To(BeNumerically("~", gameState.Buildings[0].BaseGenerateRate/model.CoinBasePrice, 0.00001))
+		})
+
+		It("should pay the upkeep of mining buildings from money", func() {
+			now := time.Now()
+			gameState.Money = 1.0
+			gameState.Buildings[0].Count = 1
+			gameState.LastUpdate = now.Add(-1 * time.Second)
+
+			gameState.UpdateBuildings(now)
+			Expect(gameState.GetMoney()).To(BeNumerically("~", 1.0-gameState.Buildings[0].BaseUpkeep, 0.00001))
+			Expect(gameState.GetThrottle()).To(Equal(1.0))
+		})
+
+		It("should pay the upkeep of mining buildings with mined coins when there is no money", func() {
+			now := time.Now()
+			gameState.Buildings[0].Count = 1
+			gameState.LastUpdate = now.Add(-1 * time.Second)
+
+			gameState.UpdateBuildings(now)
+			Expect(gameState.GetThrottle()).To(Equal(1.0))
+			Expect(gameState.GetMoney()).To(BeNumerically("~", 0, 0.00001))
+			Expect(gameState.GetMoney()).To(BeNumerically(">=", 0))
+			mined := gameState.Buildings[0].BaseGenerateRate / model.CoinBasePrice
+			Expect(gameState.GetCoins()).To(BeNumerically("~", mined-gameState.Buildings[0].BaseUpkeep/model.CoinBasePrice, 0.00001))
+		})
+
+		It("should shut down buildings instead of sending money negative", func() {
+			now := time.Now()
+			gameState.Buildings[0].Count = 1
+			gameState.Market.Price = 0.1 // The mined coins are worth less than the upkeep
+			gameState.LastUpdate = now.Add(-1 * time.Second)
+
+			gameState.UpdateBuildings(now)
+			Expect(gameState.GetMoney()).To(Equal(0.0))
+			Expect(gameState.GetCoins()).To(Equal(0.0))
+			Expect(gameState.GetThrottle()).To(Equal(0.0))
+		})
+
+		It("should throttle buildings when money and coins only cover part of the upkeep", func() {
+			now := time.Now()
+			gameState.Buildings[0].Count = 10
+			gameState.Market.Price = 0.1
+			upkeep := gameState.Buildings[0].BaseUpkeep * 10
+			coinValue := gameState.Buildings[0].BaseGenerateRate * 10 / model.CoinBasePrice * 0.1
+			gameState.Money = (upkeep - coinValue) / 4
+			gameState.LastUpdate = now.Add(-1 * time.Second)
+
+			gameState.UpdateBuildings(now)
+			Expect(gameState.GetMoney()).To(BeNumerically("~", 0, 0.00001))
+			Expect(gameState.GetMoney()).To(BeNumerically(">=", 0))
+			Expect(gameState.GetThrottle()).To(BeNumerically("~", 0.25, 0.00001))
+			// The mined coins are sold for the upkeep
+			Expect(gameState.GetCoins()).To(BeNumerically("~", 0, 0.00001))
+		})
+
+		It("should advance the market", func() {
+			now := time.Now()
+			gameState.LastUpdate = now.Add(-3 * time.Second) // Simulate 3 seconds elapsed
+
+			gameState.UpdateBuildings(now)
+			Expect(gameState.GetMarket().Ticks).To(Equal(3))
+			Expect(gameState.GetMarket().History).To(HaveLen(4))
 		})
 
 		It("should not generate income from locked buildings", func() {
@@ -83,8 +158,65 @@ var _ = Describe("DefaultGameState", func() {
 			Expect(gameState.GetTotalGenerateRate()).To(BeNumerically("~", expectedRate, 0.00001))
 		})
 
+		It("should value mined coins at the current price", func() {
+			gameState.Buildings[0].Count = 1
+			gameState.Market.Price = 2 * model.CoinBasePrice
+
+			expectedRate := gameState.Buildings[0].BaseGenerateRate * 2
+			Expect(gameState.GetTotalGenerateRate()).To(BeNumerically("~", expectedRate, 0.00001))
+		})
+
 		It("should return 0 if no buildings are unlocked", func() {
 			Expect(gameState.GetTotalGenerateRate()).To(Equal(0.0))
 		})
 	})
+
+	Describe("SetBuildingLevel", func() {
+		It("should set the level of a building", func() {
+			Expect(gameState.SetBuildingLevel(0, 2)).To(Succeed())
+			Expect(gameState.Buildings[0].Level).To(Equal(2))
+		})
+
+		It("should reject levels without a tier", func() {
+			Expect(gameState.SetBuildingLevel(0, gameState.Buildings[0].MaxLevel()+1)).NotTo(Succeed())
+			Expect(gameState.SetBuildingLevel(0, -1)).NotTo(Succeed())
+		})
+
+		It("should reject invalid building indexes", func() {
+			Expect(gameState.SetBuildingLevel(-1, 0)).NotTo(Succeed())
+			Expect(gameState.SetBuildingLevel(len(gameState.Buildings), 0)).NotTo(Succeed())
+		})
+	})
+
+	Describe("Clone", func() {
+		It("should not change with the original", func() {
+			gameState.Buildings[0].Count = 1
+			gameState.Upgrades[0].IsPurchased = true
+			clone := gameState.Clone()
+
+			gameState.UpdateMoney(10)
+			gameState.Buildings[0].Count = 2
+			gameState.Upgrades[0].IsPurchased = false
+			gameState.Market.History[0] = 99
+
+			Expect(clone.GetMoney()).To(Equal(0.0))
+			Expect(clone.GetBuildings()[0].Count).To(Equal(1))
+			Expect(clone.GetUpgrades()[0].IsPurchased).To(BeTrue())
+			Expect(clone.GetMarket().History[0]).NotTo(Equal(99.0))
+		})
+	})
+
+	Describe("GetTotalUpkeep", func() {
+		It("should sum the upkeep of all unlocked buildings", func() {
+			gameState.Buildings[0].Count = 1
+			gameState.Buildings[4].Count = 2
+
+			expectedUpkeep := gameState.Buildings[0].BaseUpkeep + gameState.Buildings[4].BaseUpkeep*2
+			Expect(gameState.GetTotalUpkeep()).To(BeNumerically("~", expectedUpkeep, 0.00001))
+		})
+
+		It("should return 0 if no buildings are unlocked", func() {
+			Expect(gameState.GetTotalUpkeep()).To(Equal(0.0))
+		})
+	})
 })
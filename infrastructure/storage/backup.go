@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+)
+
+var (
+	ErrNoValidBackup = errors.New("no valid backup found")
+	errBackupMissing = errors.New("backup does not exist")
+)
+
+// backupKey returns the key of the n-th newest backup, starting at 1
+func (s *DefaultStorage) backupKey(n int) string {
+	return fmt.Sprintf("%s.%d.bak", s.baseKey(), n)
+}
+
+// corruptKey returns the key the save is preserved under when it could not be loaded cleanly
+func (s *DefaultStorage) corruptKey() string {
+	return s.baseKey() + ".corrupt.bak"
+}
+
+func (s *DefaultStorage) baseKey() string {
+	if key := s.storageDriver.GetKeyName(); key != "" {
+		return key
+	}
+	return "save.json"
+}
+
+// rotateBackups shifts the backups by one and stores the data as the newest backup.
+// Backups older than the retention are overwritten.
+func (s *DefaultStorage) rotateBackups(data []byte) error {
+	if s.backupRetention <= 0 {
+		return nil
+	}
+	for i := s.backupRetention - 1; i >= 1; i-- {
+		old, err := s.newBackupDriver(s.backupKey(i)).LoadData()
+		if err != nil || len(old) == 0 {
+			continue
+		}
+		if err := s.newBackupDriver(s.backupKey(i + 1)).SaveData(old); err != nil {
+			return fmt.Errorf("failed to rotate backup %d: %w", i, err)
+		}
+	}
+	if err := s.newBackupDriver(s.backupKey(1)).SaveData(data); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// RestoreBackup loads the newest backup that passes validation
+func (s *DefaultStorage) RestoreBackup() (state.GameState, error) {
+	gameState, _, err := s.restoreNewestBackup()
+	return gameState, err
+}
+
+// restoreNewestBackup loads the newest backup that passes validation and returns its number
+func (s *DefaultStorage) restoreNewestBackup() (state.GameState, int, error) {
+	for i := 1; i <= s.backupRetention; i++ {
+		gameState, err := s.loadBackup(i)
+		if errors.Is(err, errBackupMissing) {
+			continue
+		}
+		if err != nil {
+			slog.Warn("Skipping backup", "backup", i, "error", err)
+			continue
+		}
+		slog.Info("Restored game state from backup", "backup", i)
+		return gameState, i, nil
+	}
+	return &state.DefaultGameState{}, 0, ErrNoValidBackup
+}
+
+func (s *DefaultStorage) loadBackup(n int) (state.GameState, error) {
+	save, err := s.loadBackupSave(n)
+	if err != nil {
+		return nil, err
+	}
+	return save.ConvertToGameState()
+}
+
+// loadBackupSave decodes the n-th backup, which must be neither corrupted, edited nor invalid
+func (s *DefaultStorage) loadBackupSave(n int) (Save, error) {
+	data, err := s.newBackupDriver(s.backupKey(n)).LoadData()
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && data == nil) {
+		return Save{}, errBackupMissing
+	}
+	if err != nil {
+		return Save{}, err
+	}
+	data, integrity, err := unwrapEnvelope(data)
+	if err != nil {
+		return Save{}, err
+	}
+	if integrity == IntegrityCorrupted || integrity == IntegrityEdited {
+		return Save{}, fmt.Errorf("backup is %s", integrity)
+	}
+	data, _, err = migrateSave(data)
+	if err != nil {
+		return Save{}, err
+	}
+	var save Save
+	if err := json.Unmarshal(data, &save); err != nil {
+		return Save{}, err
+	}
+	if err := save.Validation(); err != nil {
+		return Save{}, err
+	}
+	return save, nil
+}
+
+// restoreOrElse returns the newest valid backup, or the result of recover if there is none.
+// The restored state is saved right away so that the broken save is replaced.
+func (s *DefaultStorage) restoreOrElse(recover func() (state.GameState, error)) (state.GameState, error) {
+	gameState, n, err := s.restoreNewestBackup()
+	if err != nil {
+		return recover()
+	}
+	s.report.RestoredBackup = n
+	if err := s.SaveGameState(gameState); err != nil {
+		slog.Warn("Failed to save restored state", "error", err)
+	}
+	return gameState, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"errors"
+	"os"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup", func() {
+	var (
+		mockDriver  *driver.FaultDriver
+		store       *driver.MemoryStore
+		testStorage *DefaultStorage
+	)
+
+	backupMoney := func(n int) float64 {
+		data := store.Get(testStorage.backupKey(n))
+		Expect(data).NotTo(BeNil())
+		return decodeSaved(data).Money
+	}
+	saveMoney := func(money float64) {
+		gameState := state.NewGameState()
+		gameState.UpdateMoney(money)
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		testStorage, mockDriver, store = newTestStorage(3)
+	})
+
+	Describe("rotation", func() {
+		It("should keep the last saves up to the retention", func() {
+			for i := 1; i <= 4; i++ {
+				saveMoney(float64(i))
+			}
+
+			Expect(backupMoney(1)).To(Equal(4.0))
+			Expect(backupMoney(2)).To(Equal(3.0))
+			Expect(backupMoney(3)).To(Equal(2.0))
+			Expect(store.Get(testStorage.backupKey(4))).To(BeNil())
+		})
+
+		It("should not keep backups without retention", func() {
+			testStorage, mockDriver, store = newTestStorage(0)
+			saveMoney(1)
+
+			Expect(mockDriver.Saves).To(Equal(1))
+			Expect(store.ListKeys()).To(Equal([]string{testSaveKey}))
+		})
+	})
+
+	Describe("restore", func() {
+		BeforeEach(func() {
+			saveMoney(10)
+			saveMoney(20)
+		})
+
+		It("should restore the newest backup if the save is corrupted", func() {
+			store.Set(testSaveKey, []byte(`{"money": 1, "buildings": "broken"`))
+
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gameState.GetMoney()).To(Equal(20.0))
+		})
+
+		It("should skip backups that fail validation", func() {
+			store.Set(testStorage.backupKey(1), []byte(`{"version": 3, "money": -5}`))
+			store.Set(testSaveKey, []byte(`not json`))
+
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gameState.GetMoney()).To(Equal(10.0))
+		})
+
+		It("should restore the newest backup if the save is missing", func() {
+			mockDriver.LoadError = os.ErrNotExist
+
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gameState.GetMoney()).To(Equal(20.0))
+		})
+
+		It("should preserve the broken save once", func() {
+			store.Set(testSaveKey, []byte(`not json`))
+			_, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(store.Get(testStorage.corruptKey()))).To(Equal("not json"))
+		})
+
+		It("should not restore over a save from a newer version", func() {
+			store.Set(testSaveKey, loadGolden("save_future.json"))
+
+			_, err := testStorage.LoadGameState()
+			Expect(errors.Is(err, ErrNewerSaveVersion)).To(BeTrue())
+			Expect(string(store.Get(testSaveKey))).To(ContainSubstring(`"version":99`))
+		})
+	})
+
+	It("should report when there is no valid backup", func() {
+		_, err := testStorage.RestoreBackup()
+		Expect(errors.Is(err, ErrNoValidBackup)).To(BeTrue())
+	})
+})
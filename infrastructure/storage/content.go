@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/kmdkuk/clicker/game/level"
+)
+
+// buildingRenames and upgradeRenames map IDs that were renamed or removed in game/level to their
+// current ID, so that saves keep their progress when the content changes.
+// An empty ID marks removed content, which is dropped from the save when it is loaded.
+// Add an entry whenever an ID changes; IDs of removed content must never be reused.
+// Renaming a building renames its upgrades too, so they need no entries of their own.
+var (
+	buildingRenames = map[string]string{}
+	upgradeRenames  = map[string]string{}
+)
+
+// migrateContent renames the buildings and upgrades of a current save document after the
+// content migration tables. Fields that do not decode are left to the partial recovery.
+func migrateContent(doc saveDocument) {
+	for _, field := range []string{"buildings", "building_levels"} {
+		var values map[string]int
+		if err := json.Unmarshal(doc[field], &values); err != nil || values == nil {
+			continue
+		}
+		if renameKeys(values) {
+			doc[field], _ = json.Marshal(values)
+		}
+	}
+
+	var upgradings []upgrade
+	if err := json.Unmarshal(doc["upgradings"], &upgradings); err != nil || upgradings == nil {
+		return
+	}
+	present := map[string]bool{}
+	for _, u := range upgradings {
+		present[u.ID] = true
+	}
+	changed := false
+	migrated := upgradings[:0]
+	for _, u := range upgradings {
+		to, ok := renamedUpgrade(u.ID)
+		switch {
+		case !ok:
+		case to == "":
+			slog.Info("Dropped removed upgrade from the save", "upgrade", u.ID)
+			changed = true
+			continue
+		case present[to]:
+			slog.Warn("Renamed upgrade is already in the save, keeping the current one", "from", u.ID, "to", to)
+			changed = true
+			continue
+		default:
+			present[to] = true
+			u.ID = to
+			changed = true
+		}
+		migrated = append(migrated, u)
+	}
+	if changed {
+		doc["upgradings"], _ = json.Marshal(migrated)
+	}
+}
+
+// renameKeys renames the building IDs of values in place and reports whether anything changed
+func renameKeys(values map[string]int) bool {
+	var renamed []string
+	for from := range values {
+		if _, ok := buildingRenames[from]; ok {
+			renamed = append(renamed, from)
+		}
+	}
+	sort.Strings(renamed)
+	for _, from := range renamed {
+		value, to := values[from], buildingRenames[from]
+		delete(values, from)
+		switch _, exists := values[to]; {
+		case to == "":
+			slog.Info("Dropped removed building from the save", "building", from)
+		case exists:
+			slog.Warn("Renamed building is already in the save, keeping the current one", "from", from, "to", to)
+		default:
+			values[to] = value
+		}
+	}
+	return len(renamed) > 0
+}
+
+// renamedUpgrade returns the current ID of an upgrade that was renamed or removed, directly or
+// through its building. ok is false if the upgrade was not affected.
+func renamedUpgrade(id string) (to string, ok bool) {
+	if to, ok := upgradeRenames[id]; ok {
+		return to, true
+	}
+	for _, prefix := range []string{"", "efficiency_"} {
+		for from, to := range buildingRenames {
+			suffix, found := strings.CutPrefix(id, prefix+from+"_")
+			if !found || !isNumber(suffix) {
+				continue
+			}
+			if to == "" {
+				return "", true
+			}
+			return prefix + to + "_" + suffix, true
+		}
+	}
+	return "", false
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// UnknownContent lists the buildings and upgrades of the save that the game does not know,
+// e.g. after content was removed without an entry in the content migrations.
+// They are left out when the save is applied to a game state.
+func (s *Save) UnknownContent() []string {
+	buildings := map[string]bool{}
+	for _, b := range level.NewBuildings() {
+		buildings[b.Key] = true
+	}
+	upgrades := map[string]bool{}
+	for _, u := range level.NewUpgrades() {
+		upgrades[u.ID] = true
+	}
+
+	unknown := map[string]bool{}
+	for _, values := range []map[string]int{s.Buildings, s.BuildingLevels} {
+		for key := range values {
+			if !buildings[key] {
+				unknown[fmt.Sprintf("building %s", key)] = true
+			}
+		}
+	}
+	for _, u := range s.Upgradings {
+		if !upgrades[u.ID] {
+			unknown[fmt.Sprintf("upgrade %s", u.ID)] = true
+		}
+	}
+
+	var list []string
+	for entry := range unknown {
+		list = append(list, entry)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Operations of a journalChange
+const (
+	changeSet    = "set"
+	changeRemove = "remove"
+	changeAppend = "append"
+)
+
+// journalChange is one change of a field of the save. The field is addressed by a JSON Pointer (RFC 6901),
+// e.g. "/buildings/cpu_miner" or "/upgradings/3/is_purchased".
+type journalChange struct {
+	Op     string            `json:"op"`
+	Path   string            `json:"path"`
+	Value  json.RawMessage   `json:"value,omitempty"`  // New value of a set field
+	Drop   int               `json:"drop,omitempty"`   // Elements the array dropped from its front before the append
+	Values []json.RawMessage `json:"values,omitempty"` // Elements appended to the array
+}
+
+// saveTree decodes the serialized save into nested maps and slices.
+// Numbers are kept as they were written, so that they are compared and restored exactly.
+func saveTree(save Save) (any, error) {
+	payload, err := json.Marshal(save)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal save data: %w", err)
+	}
+	return decodeTree(payload)
+}
+
+func decodeTree(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("failed to decode save data: %w", err)
+	}
+	return tree, nil
+}
+
+// marshalTree encodes a decoded tree. Decoded trees only hold JSON values, so they always marshal.
+func marshalTree(tree any) []byte {
+	data, _ := json.Marshal(tree)
+	return data
+}
+
+// diffTree appends the changes that turn old into new.
+// Objects and arrays of the same length are compared field by field, and an array that dropped
+// elements from its front and grew at the end, like the price history, records only the new elements.
+func diffTree(path string, old, new any, changes []journalChange) []journalChange {
+	if reflect.DeepEqual(old, new) {
+		return changes
+	}
+	switch n := new.(type) {
+	case map[string]any:
+		o, ok := old.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range slices.Sorted(maps.Keys(o)) {
+			if _, ok := n[key]; !ok {
+				changes = append(changes, journalChange{Op: changeRemove, Path: path + "/" + escapePointer(key)})
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(n)) {
+			if value, ok := o[key]; ok {
+				changes = diffTree(path+"/"+escapePointer(key), value, n[key], changes)
+			} else {
+				changes = append(changes, journalChange{Op: changeSet, Path: path + "/" + escapePointer(key), Value: marshalTree(n[key])})
+			}
+		}
+		return changes
+	case []any:
+		o, ok := old.([]any)
+		if !ok {
+			break
+		}
+		if drop, ok := appendedTo(o, n); ok {
+			values := make([]json.RawMessage, 0, len(n)-(len(o)-drop))
+			for _, value := range n[len(o)-drop:] {
+				values = append(values, marshalTree(value))
+			}
+			return append(changes, journalChange{Op: changeAppend, Path: path, Drop: drop, Values: values})
+		}
+		if len(o) == len(n) {
+			for i := range n {
+				changes = diffTree(path+"/"+strconv.Itoa(i), o[i], n[i], changes)
+			}
+			return changes
+		}
+	}
+	return append(changes, journalChange{Op: changeSet, Path: path, Value: marshalTree(new)})
+}
+
+// appendedTo returns how many elements old dropped from its front if new continues what is left of it.
+// It fails if nothing is left of old, then the array is rather set as a whole.
+func appendedTo(old, new []any) (int, bool) {
+	for drop := 0; drop < len(old); drop++ {
+		kept := old[drop:]
+		if len(kept) <= len(new) && reflect.DeepEqual(kept, new[:len(kept)]) {
+			return drop, true
+		}
+	}
+	return 0, false
+}
+
+// applyChange applies the change to the tree and returns the tree, which is replaced if the change sets the root
+func applyChange(tree any, change journalChange) (any, error) {
+	keys := splitPointer(change.Path)
+	if len(keys) == 0 {
+		if change.Op != changeSet {
+			return tree, fmt.Errorf("cannot %s the whole save", change.Op)
+		}
+		return decodeTree(change.Value)
+	}
+	parent := tree
+	for _, key := range keys[:len(keys)-1] {
+		child, err := childOf(parent, key)
+		if err != nil {
+			return tree, fmt.Errorf("%s: %w", change.Path, err)
+		}
+		parent = child
+	}
+	key := keys[len(keys)-1]
+
+	var value any
+	switch change.Op {
+	case changeSet:
+		decoded, err := decodeTree(change.Value)
+		if err != nil {
+			return tree, fmt.Errorf("%s: %w", change.Path, err)
+		}
+		value = decoded
+	case changeRemove:
+		object, ok := parent.(map[string]any)
+		if !ok {
+			return tree, fmt.Errorf("%s: only fields of objects can be removed", change.Path)
+		}
+		delete(object, key)
+		return tree, nil
+	case changeAppend:
+		current, err := childOf(parent, key)
+		if err != nil {
+			return tree, fmt.Errorf("%s: %w", change.Path, err)
+		}
+		array, ok := current.([]any)
+		if !ok || change.Drop > len(array) {
+			return tree, fmt.Errorf("%s: cannot append to %T", change.Path, current)
+		}
+		appended := slices.Clone(array[change.Drop:])
+		for _, raw := range change.Values {
+			element, err := decodeTree(raw)
+			if err != nil {
+				return tree, fmt.Errorf("%s: %w", change.Path, err)
+			}
+			appended = append(appended, element)
+		}
+		value = appended
+	default:
+		return tree, fmt.Errorf("%s: unknown change %q", change.Path, change.Op)
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		p[key] = value
+	case []any:
+		i, err := arrayIndex(p, key)
+		if err != nil {
+			return tree, fmt.Errorf("%s: %w", change.Path, err)
+		}
+		p[i] = value
+	default:
+		return tree, fmt.Errorf("%s: %T has no fields", change.Path, parent)
+	}
+	return tree, nil
+}
+
+// childOf returns the field of an object or the element of an array
+func childOf(parent any, key string) (any, error) {
+	switch p := parent.(type) {
+	case map[string]any:
+		child, ok := p[key]
+		if !ok {
+			return nil, fmt.Errorf("no field %q", key)
+		}
+		return child, nil
+	case []any:
+		i, err := arrayIndex(p, key)
+		if err != nil {
+			return nil, err
+		}
+		return p[i], nil
+	}
+	return nil, fmt.Errorf("%T has no fields", parent)
+}
+
+func arrayIndex(array []any, key string) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= len(array) {
+		return 0, fmt.Errorf("no element %q", key)
+	}
+	return i, nil
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func escapePointer(key string) string {
+	return pointerEscaper.Replace(key)
+}
+
+// splitPointer returns the unescaped keys of a JSON Pointer, none for the whole document
+func splitPointer(path string) []string {
+	if path == "" {
+		return nil
+	}
+	keys := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, key := range keys {
+		keys[i] = pointerUnescaper.Replace(key)
+	}
+	return keys
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Delta", func() {
+	tree := func(data string) any {
+		decoded, err := decodeTree([]byte(data))
+		Expect(err).NotTo(HaveOccurred())
+		return decoded
+	}
+
+	DescribeTable("diffTree and applyChange",
+		func(old, new string, expected int) {
+			changes := diffTree("", tree(old), tree(new), nil)
+			Expect(changes).To(HaveLen(expected))
+
+			replayed := tree(old)
+			for _, change := range changes {
+				var err error
+				replayed, err = applyChange(replayed, change)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(replayed).To(Equal(tree(new)))
+		},
+		Entry("nothing changed", `{"a":1,"b":[1,2]}`, `{"a":1,"b":[1,2]}`, 0),
+		Entry("nested field", `{"a":{"x":1,"y":2}}`, `{"a":{"x":1,"y":3}}`, 1),
+		Entry("added and removed fields", `{"a":{"x":1}}`, `{"a":{"y":2}}`, 2),
+		Entry("element of an array", `{"a":[{"x":1},{"x":2}]}`, `{"a":[{"x":1},{"x":3}]}`, 1),
+		Entry("array that grew", `{"a":[1,2]}`, `{"a":[1,2,3]}`, 1),
+		Entry("array that shifted", `{"a":[1,2,3]}`, `{"a":[2,3,4]}`, 1),
+		Entry("array that shrank", `{"a":[1,2,3]}`, `{"a":[4]}`, 1),
+		Entry("field that changed its type", `{"a":{"x":1}}`, `{"a":[1]}`, 1),
+		Entry("key with a slash", `{"a/b":1}`, `{"a/b":2}`, 1),
+		Entry("whole document", `[1]`, `{"a":1}`, 1),
+		Entry("large number", `{"a":18446744073709551615}`, `{"a":18446744073709551614}`, 1),
+	)
+
+	It("should keep only the new elements of an array that grew", func() {
+		changes := diffTree("", tree(`{"a":[1,2,3]}`), tree(`{"a":[2,3,4]}`), nil)
+		Expect(changes[0].Op).To(Equal(changeAppend))
+		Expect(changes[0].Drop).To(Equal(1))
+		Expect(changes[0].Values).To(HaveLen(1))
+	})
+
+	It("should reject a change of a field that does not exist", func() {
+		_, err := applyChange(tree(`{"a":1}`), journalChange{Op: changeSet, Path: "/b/c", Value: []byte(`1`)})
+		Expect(err).To(HaveOccurred())
+		_, err = applyChange(tree(`{"a":[1]}`), journalChange{Op: changeSet, Path: "/a/1", Value: []byte(`1`)})
+		Expect(err).To(HaveOccurred())
+		_, err = applyChange(tree(`{"a":1}`), journalChange{Op: changeAppend, Path: "/a"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,95 @@
+package driver
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrInjectedFault is returned by FaultDriver for the writes it cuts short
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultDriver wraps a driver to inject the faults a real storage can have, for tests.
+// Without any fault set it passes everything through to the wrapped driver.
+type FaultDriver struct {
+	StorageDriver
+	mu sync.Mutex
+
+	SaveError error // Returned by SaveData without writing anything
+	LoadError error // Returned by LoadData without reading anything
+	// TruncateSave makes SaveData and AppendData write only the first bytes and fail, like a crash
+	// in the middle of a write that is not atomic. Zero writes everything.
+	TruncateSave int
+	// CorruptLoad alters the data returned by LoadData, like a bit flip on the disk
+	CorruptLoad func(data []byte) []byte
+
+	Saves int // Number of SaveData and AppendData calls
+	Loads int // Number of LoadData calls
+}
+
+func NewFaultDriver(storageDriver StorageDriver) *FaultDriver {
+	return &FaultDriver{
+		StorageDriver: storageDriver,
+	}
+}
+
+func (f *FaultDriver) SaveData(data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Saves++
+	if f.SaveError != nil {
+		return f.SaveError
+	}
+	if f.TruncateSave > 0 && f.TruncateSave < len(data) {
+		if err := f.StorageDriver.SaveData(data[:f.TruncateSave]); err != nil {
+			return err
+		}
+		return ErrInjectedFault
+	}
+	return f.StorageDriver.SaveData(data)
+}
+
+func (f *FaultDriver) AppendData(data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Saves++
+	if f.SaveError != nil {
+		return f.SaveError
+	}
+	if f.TruncateSave > 0 && f.TruncateSave < len(data) {
+		if err := AppendData(f.StorageDriver, data[:f.TruncateSave]); err != nil {
+			return err
+		}
+		return ErrInjectedFault
+	}
+	return AppendData(f.StorageDriver, data)
+}
+
+func (f *FaultDriver) LoadData() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Loads++
+	if f.LoadError != nil {
+		return nil, f.LoadError
+	}
+	data, err := f.StorageDriver.LoadData()
+	if err != nil || f.CorruptLoad == nil {
+		return data, err
+	}
+	return f.CorruptLoad(data), nil
+}
+
+// FlipByte returns a CorruptLoad function that inverts the bits of the byte at offset,
+// counted from the end if negative
+func FlipByte(offset int) func(data []byte) []byte {
+	return func(data []byte) []byte {
+		i := offset
+		if i < 0 {
+			i += len(data)
+		}
+		if i < 0 || i >= len(data) {
+			return data
+		}
+		data[i] = ^data[i]
+		return data
+	}
+}
@@ -0,0 +1,203 @@
+//go:build js && wasm
+// +build js,wasm
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall/js"
+)
+
+const (
+	indexedDBName    = "clicker"
+	indexedDBVersion = 1
+	indexedDBStore   = "saves"
+)
+
+var (
+	indexedDBOnce sync.Once
+	indexedDB     js.Value
+	indexedDBErr  error
+)
+
+// openIndexedDB opens the database once and returns it for every later call.
+// IndexedDB is asynchronous, so the callbacks are bridged to the calling goroutine with channels.
+// This must not be called from a JS callback, which would deadlock.
+func openIndexedDB() (js.Value, error) {
+	indexedDBOnce.Do(func() {
+		factory := js.Global().Get("indexedDB")
+		if factory.IsUndefined() || factory.IsNull() {
+			indexedDBErr = errors.New("IndexedDB is not available")
+			return
+		}
+		req := factory.Call("open", indexedDBName, indexedDBVersion)
+		upgrade := js.FuncOf(func(this js.Value, args []js.Value) any {
+			req.Get("result").Call("createObjectStore", indexedDBStore)
+			return nil
+		})
+		defer upgrade.Release()
+		req.Set("onupgradeneeded", upgrade)
+		indexedDB, indexedDBErr = awaitRequest(req)
+	})
+	return indexedDB, indexedDBErr
+}
+
+// awaitRequest waits for the IDBRequest to succeed or fail and returns its result
+func awaitRequest(req js.Value) (js.Value, error) {
+	done := make(chan error, 1)
+	onSuccess := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- nil
+		return nil
+	})
+	defer onSuccess.Release()
+	onError := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- domError("IndexedDB request failed", req.Get("error"))
+		return nil
+	})
+	defer onError.Release()
+	req.Set("onsuccess", onSuccess)
+	req.Set("onerror", onError)
+
+	if err := <-done; err != nil {
+		return js.Undefined(), err
+	}
+	return req.Get("result"), nil
+}
+
+// awaitTransaction waits until the transaction is committed, so that the write survives closing the tab
+func awaitTransaction(tx js.Value) error {
+	done := make(chan error, 1)
+	onComplete := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- nil
+		return nil
+	})
+	defer onComplete.Release()
+	onError := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- domError("IndexedDB transaction failed", tx.Get("error"))
+		return nil
+	})
+	defer onError.Release()
+	tx.Set("oncomplete", onComplete)
+	tx.Set("onerror", onError)
+	tx.Set("onabort", onError)
+	return <-done
+}
+
+func domError(message string, err js.Value) error {
+	if err.IsUndefined() || err.IsNull() {
+		return errors.New(message)
+	}
+	return fmt.Errorf("%s: %s", message, err.Get("message").String())
+}
+
+// store starts a transaction on the save store
+func store(mode string) (js.Value, js.Value, error) {
+	db, err := openIndexedDB()
+	if err != nil {
+		return js.Undefined(), js.Undefined(), err
+	}
+	tx := db.Call("transaction", indexedDBStore, mode)
+	return tx, tx.Call("objectStore", indexedDBStore), nil
+}
+
+// StorageIndexedDB stores the data in IndexedDB, which has a much larger quota than localStorage
+// and does not block the page while writing.
+// A save still in localStorage from older versions is moved to IndexedDB the first time it is loaded.
+type StorageIndexedDB struct {
+	key    string
+	legacy *StorageWasm
+}
+
+func (s *StorageIndexedDB) SaveData(data []byte) error {
+	tx, objectStore, err := store("readwrite")
+	if err != nil {
+		return err
+	}
+	objectStore.Call("put", string(data), s.key)
+	if err := awaitTransaction(tx); err != nil {
+		return err
+	}
+	// A newer save in IndexedDB must not be shadowed by the old one in localStorage
+	return s.legacy.remove()
+}
+
+func (s *StorageIndexedDB) LoadData() ([]byte, error) {
+	_, objectStore, err := store("readonly")
+	if err != nil {
+		return nil, err
+	}
+	value, err := awaitRequest(objectStore.Call("get", s.key))
+	if err != nil {
+		return nil, err
+	}
+	if !value.IsUndefined() && !value.IsNull() {
+		return []byte(value.String()), nil
+	}
+	return s.migrate()
+}
+
+// migrate moves the save of the key from localStorage to IndexedDB
+func (s *StorageIndexedDB) migrate() ([]byte, error) {
+	data, err := s.legacy.LoadData()
+	if err != nil {
+		return nil, err
+	}
+	// If the move fails the game keeps running on the localStorage save and it is retried on the next load
+	_ = s.SaveData(data)
+	return data, nil
+}
+
+func (s *StorageIndexedDB) GetKeyName() string {
+	return s.key
+}
+
+// KeyManagerIndexedDB lists the keys in IndexedDB together with the ones not yet moved from localStorage
+type KeyManagerIndexedDB struct {
+	legacy *KeyManagerWasm
+}
+
+func (k *KeyManagerIndexedDB) ListKeys() ([]string, error) {
+	_, objectStore, err := store("readonly")
+	if err != nil {
+		return nil, err
+	}
+	result, err := awaitRequest(objectStore.Call("getAllKeys"))
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	keys := []string{}
+	for i := 0; i < result.Length(); i++ {
+		key := result.Index(i).String()
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	legacyKeys, err := k.legacy.ListKeys()
+	if err != nil {
+		// localStorage may be disabled while IndexedDB works
+		return keys, nil
+	}
+	for _, key := range legacyKeys {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (k *KeyManagerIndexedDB) DeleteKey(key string) error {
+	tx, objectStore, err := store("readwrite")
+	if err != nil {
+		return err
+	}
+	objectStore.Call("delete", key)
+	if err := awaitTransaction(tx); err != nil {
+		return err
+	}
+	if err := k.legacy.DeleteKey(key); err != nil && !errors.Is(err, errLocalStorageUnavailable) {
+		return err
+	}
+	return nil
+}
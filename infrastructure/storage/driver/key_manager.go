@@ -0,0 +1,7 @@
+package driver
+
+// KeyManager enumerates and removes the keys data is stored under
+type KeyManager interface {
+	ListKeys() ([]string, error)
+	DeleteKey(key string) error
+}
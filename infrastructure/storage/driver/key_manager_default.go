@@ -0,0 +1,49 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// NewKeyManager returns a key manager for the files in dir
+func NewKeyManager(dir string) KeyManager {
+	if dir == "" {
+		dir = "."
+	}
+	return &DefaultKeyManager{
+		dir: dir,
+	}
+}
+
+type DefaultKeyManager struct {
+	dir string
+}
+
+func (k *DefaultKeyManager) ListKeys() ([]string, error) {
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			keys = append(keys, k.key(entry.Name()))
+		}
+	}
+	return keys, nil
+}
+
+func (k *DefaultKeyManager) DeleteKey(key string) error {
+	return os.Remove(key)
+}
+
+// key returns the path of the file in the same form as the keys passed to NewStorageDriver
+func (k *DefaultKeyManager) key(name string) string {
+	if k.dir == "." {
+		return name
+	}
+	return filepath.Join(k.dir, name)
+}
@@ -0,0 +1,44 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeyManagerDefault", func() {
+	var (
+		dir        string
+		keyManager KeyManager
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		keyManager = NewKeyManager(dir)
+	})
+
+	It("should list the saved keys", func() {
+		Expect(NewStorageDriver(filepath.Join(dir, "a.json")).SaveData([]byte("a"))).To(Succeed())
+		Expect(NewStorageDriver(filepath.Join(dir, "b.json")).SaveData([]byte("b"))).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(dir, "sub"), 0755)).To(Succeed())
+
+		keys, err := keyManager.ListKeys()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(ConsistOf(filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")))
+	})
+
+	It("should delete a key", func() {
+		key := filepath.Join(dir, "a.json")
+		Expect(NewStorageDriver(key).SaveData([]byte("a"))).To(Succeed())
+
+		Expect(keyManager.DeleteKey(key)).To(Succeed())
+		keys, err := keyManager.ListKeys()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+	})
+})
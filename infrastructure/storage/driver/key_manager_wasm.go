@@ -0,0 +1,44 @@
+//go:build js && wasm
+// +build js,wasm
+
+package driver
+
+// NewKeyManager returns a key manager for IndexedDB, or for localStorage if IndexedDB cannot be opened.
+// The dir is ignored.
+func NewKeyManager(dir string) KeyManager {
+	legacy := &KeyManagerWasm{}
+	if _, err := openIndexedDB(); err != nil {
+		return legacy
+	}
+	return &KeyManagerIndexedDB{
+		legacy: legacy,
+	}
+}
+
+// KeyManagerWasm manages the keys in localStorage
+type KeyManagerWasm struct{}
+
+func (k *KeyManagerWasm) ListKeys() ([]string, error) {
+	localStorage, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+	length := localStorage.Get("length").Int()
+	keys := make([]string, 0, length)
+	for i := 0; i < length; i++ {
+		key := localStorage.Call("key", i)
+		if !key.IsNull() {
+			keys = append(keys, key.String())
+		}
+	}
+	return keys, nil
+}
+
+func (k *KeyManagerWasm) DeleteKey(key string) error {
+	localStorage, err := localStorage()
+	if err != nil {
+		return err
+	}
+	localStorage.Call("removeItem", key)
+	return nil
+}
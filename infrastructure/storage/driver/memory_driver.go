@@ -0,0 +1,99 @@
+package driver
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+)
+
+// MemoryStore keeps data in memory under keys, like a directory of saves that never touches the disk.
+// It is meant for tests, which can create drivers for the save and its backups from the same store.
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		data: make(map[string][]byte),
+	}
+}
+
+// NewStorageDriver returns a driver storing the data under key in the store
+func (m *MemoryStore) NewStorageDriver(key string) StorageDriver {
+	return &MemoryStorageDriver{
+		store: m,
+		key:   key,
+	}
+}
+
+// Get returns a copy of the data stored under key, or nil if there is none
+func (m *MemoryStore) Get(key string) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[key]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(data)
+}
+
+// Set stores a copy of the data under key
+func (m *MemoryStore) Set(key string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = append([]byte{}, data...)
+}
+
+// ListKeys returns the stored keys in order
+func (m *MemoryStore) ListKeys() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.data))
+	for key := range m.data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func (m *MemoryStore) DeleteKey(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+// MemoryStorageDriver stores the data under its key in a MemoryStore
+type MemoryStorageDriver struct {
+	store *MemoryStore
+	key   string
+}
+
+func (s *MemoryStorageDriver) SaveData(data []byte) error {
+	s.store.Set(s.key, data)
+	return nil
+}
+
+func (s *MemoryStorageDriver) AppendData(data []byte) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.data[s.key] = append(s.store.data[s.key], data...)
+	return nil
+}
+
+// LoadData returns an error wrapping fs.ErrNotExist if no data is found, like the desktop driver
+func (s *MemoryStorageDriver) LoadData() ([]byte, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	data, ok := s.store.data[s.key]
+	if !ok {
+		return nil, fmt.Errorf("no data for %q: %w", s.key, fs.ErrNotExist)
+	}
+	return slices.Clone(data), nil
+}
+
+func (s *MemoryStorageDriver) GetKeyName() string {
+	return s.key
+}
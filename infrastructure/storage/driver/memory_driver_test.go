@@ -0,0 +1,133 @@
+package driver
+
+import (
+	"errors"
+	"io/fs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryStore", func() {
+	var store *MemoryStore
+
+	BeforeEach(func() {
+		store = NewMemoryStore()
+	})
+
+	It("should save and load through drivers sharing the store", func() {
+		Expect(store.NewStorageDriver("save.json").SaveData([]byte("test"))).To(Succeed())
+
+		data, err := store.NewStorageDriver("save.json").LoadData()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte("test")))
+		Expect(store.Get("save.json")).To(Equal([]byte("test")))
+	})
+
+	It("should report a missing key like the desktop driver", func() {
+		_, err := store.NewStorageDriver("save.json").LoadData()
+		Expect(errors.Is(err, fs.ErrNotExist)).To(BeTrue())
+		Expect(store.Get("save.json")).To(BeNil())
+	})
+
+	It("should not share the slices it stores", func() {
+		data := []byte("test")
+		store.Set("save.json", data)
+		data[0] = 'b'
+
+		loaded, err := store.NewStorageDriver("save.json").LoadData()
+		Expect(err).NotTo(HaveOccurred())
+		loaded[1] = 'x'
+		Expect(store.Get("save.json")).To(Equal([]byte("test")))
+	})
+
+	It("should list and delete keys", func() {
+		store.Set("b.json", []byte("b"))
+		store.Set("a.json", []byte("a"))
+		Expect(store.ListKeys()).To(Equal([]string{"a.json", "b.json"}))
+
+		Expect(store.DeleteKey("a.json")).To(Succeed())
+		Expect(store.ListKeys()).To(Equal([]string{"b.json"}))
+	})
+})
+
+var _ = Describe("FaultDriver", func() {
+	var (
+		store       *MemoryStore
+		faultDriver *FaultDriver
+	)
+
+	BeforeEach(func() {
+		store = NewMemoryStore()
+		faultDriver = NewFaultDriver(store.NewStorageDriver("save.json"))
+	})
+
+	It("should pass everything through without faults", func() {
+		Expect(faultDriver.SaveData([]byte("test"))).To(Succeed())
+		data, err := faultDriver.LoadData()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte("test")))
+		Expect(faultDriver.GetKeyName()).To(Equal("save.json"))
+		Expect(faultDriver.Saves).To(Equal(1))
+		Expect(faultDriver.Loads).To(Equal(1))
+	})
+
+	It("should fail saves without writing", func() {
+		store.Set("save.json", []byte("old"))
+		faultDriver.SaveError = errors.New("disk full")
+
+		Expect(faultDriver.SaveData([]byte("new"))).NotTo(Succeed())
+		Expect(store.Get("save.json")).To(Equal([]byte("old")))
+	})
+
+	It("should fail loads", func() {
+		faultDriver.LoadError = errors.New("permission denied")
+		_, err := faultDriver.LoadData()
+		Expect(err).To(MatchError("permission denied"))
+	})
+
+	It("should truncate saves like a crash in the middle of a write", func() {
+		faultDriver.TruncateSave = 3
+
+		err := faultDriver.SaveData([]byte("new save"))
+		Expect(errors.Is(err, ErrInjectedFault)).To(BeTrue())
+		Expect(store.Get("save.json")).To(Equal([]byte("new")))
+	})
+
+	It("should corrupt the loaded bytes", func() {
+		store.Set("save.json", []byte("abc"))
+		faultDriver.CorruptLoad = FlipByte(-1)
+
+		data, err := faultDriver.LoadData()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte{'a', 'b', ^byte('c')}))
+		Expect(store.Get("save.json")).To(Equal([]byte("abc")))
+	})
+})
+
+var _ = Describe("AppendData", func() {
+	It("should append through drivers that support it", func() {
+		store := NewMemoryStore()
+		storageDriver := store.NewStorageDriver("save.json.journal")
+		Expect(AppendData(storageDriver, []byte("a\n"))).To(Succeed())
+		Expect(AppendData(storageDriver, []byte("b\n"))).To(Succeed())
+		Expect(store.Get("save.json.journal")).To(Equal([]byte("a\nb\n")))
+	})
+
+	It("should fall back to rewriting the data", func() {
+		store := NewMemoryStore()
+		storageDriver := &struct{ StorageDriver }{store.NewStorageDriver("save.json.journal")}
+		Expect(AppendData(storageDriver, []byte("a\n"))).To(Succeed())
+		Expect(AppendData(storageDriver, []byte("b\n"))).To(Succeed())
+		Expect(store.Get("save.json.journal")).To(Equal([]byte("a\nb\n")))
+	})
+
+	It("should cut appended data short like a crash", func() {
+		store := NewMemoryStore()
+		faultDriver := NewFaultDriver(store.NewStorageDriver("save.json.journal"))
+		Expect(faultDriver.AppendData([]byte("a\n"))).To(Succeed())
+		faultDriver.TruncateSave = 1
+		Expect(faultDriver.AppendData([]byte("bc\n"))).To(MatchError(ErrInjectedFault))
+		Expect(store.Get("save.json.journal")).To(Equal([]byte("a\nb")))
+	})
+})
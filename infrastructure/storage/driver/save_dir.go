@@ -0,0 +1,19 @@
+package driver
+
+import (
+	"os"
+
+	"github.com/kmdkuk/clicker/config"
+)
+
+// ResolveSaveDir returns the directory the saves are stored in.
+// The dir given on the command line wins over $CLICKER_SAVE_DIR, which wins over the platform default.
+func ResolveSaveDir(dir string) (string, error) {
+	if dir == "" {
+		dir = os.Getenv(config.SaveDirEnv)
+	}
+	if dir == "" {
+		return DefaultSaveDir()
+	}
+	return dir, prepareSaveDir(dir)
+}
@@ -0,0 +1,30 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kmdkuk/clicker/config"
+)
+
+// DefaultSaveDir returns the clicker directory in the user config directory
+// (e.g. ~/.config/clicker, ~/Library/Application Support/clicker or %AppData%\clicker)
+func DefaultSaveDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	dir := filepath.Join(base, config.AppName)
+	return dir, prepareSaveDir(dir)
+}
+
+func prepareSaveDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"path/filepath"
+
+	"github.com/kmdkuk/clicker/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveSaveDir", func() {
+	It("should prefer the given dir", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "flag")
+		GinkgoT().Setenv(config.SaveDirEnv, GinkgoT().TempDir())
+
+		resolved, err := ResolveSaveDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(Equal(dir))
+		Expect(dir).To(BeADirectory())
+	})
+
+	It("should use the environment variable", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "env")
+		GinkgoT().Setenv(config.SaveDirEnv, dir)
+
+		resolved, err := ResolveSaveDir("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(Equal(dir))
+		Expect(dir).To(BeADirectory())
+	})
+
+	It("should default to the user config directory", func() {
+		base := GinkgoT().TempDir()
+		GinkgoT().Setenv(config.SaveDirEnv, "")
+		GinkgoT().Setenv("XDG_CONFIG_HOME", base)
+		GinkgoT().Setenv("HOME", base)
+		GinkgoT().Setenv("AppData", base)
+
+		resolved, err := ResolveSaveDir("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Base(resolved)).To(Equal(config.AppName))
+		Expect(resolved).To(BeADirectory())
+	})
+})
@@ -0,0 +1,13 @@
+//go:build js && wasm
+// +build js,wasm
+
+package driver
+
+// DefaultSaveDir returns an empty dir since localStorage has no directories
+func DefaultSaveDir() (string, error) {
+	return "", nil
+}
+
+func prepareSaveDir(dir string) error {
+	return nil
+}
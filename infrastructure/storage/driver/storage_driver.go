@@ -1,7 +1,42 @@
 package driver
 
+import (
+	"errors"
+	"io/fs"
+)
+
 type StorageDriver interface {
 	SaveData(data []byte) error
 	LoadData() ([]byte, error)
 	GetKeyName() string
 }
+
+// AppendDriver is implemented by drivers that can add data to the end of what they store without rewriting it
+type AppendDriver interface {
+	AppendData(data []byte) error
+}
+
+// AppendData adds the data to the end of what the driver stores.
+// Drivers that cannot append have their data loaded and saved again with the data added.
+func AppendData(d StorageDriver, data []byte) error {
+	if a, ok := d.(AppendDriver); ok {
+		return a.AppendData(data)
+	}
+	old, err := d.LoadData()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return d.SaveData(append(old, data...))
+}
+
+// FlushDriver is implemented by drivers that finish writing in the background
+type FlushDriver interface {
+	Flush()
+}
+
+// Flush waits until the data saved so far is written everywhere the driver writes it
+func Flush(d StorageDriver) {
+	if f, ok := d.(FlushDriver); ok {
+		f.Flush()
+	}
+}
@@ -4,7 +4,9 @@
 package driver
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/kmdkuk/clicker/config"
 )
@@ -24,9 +26,65 @@ type DefaultStorageDriver struct {
 	path string
 }
 
+// SaveData writes the data atomically.
+// The data is written to a temporary file in the same directory, synced and renamed over the save,
+// so a crash leaves either the old or the new save but never a partial one.
 func (s *DefaultStorageDriver) SaveData(data []byte) error {
-	return os.WriteFile(s.path, data, 0644)
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	// Remove the temporary file if anything fails before the rename
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace save: %w", err)
+	}
+	syncDir(dir)
+	return nil
 }
+
+// syncDir makes the rename durable. Not every platform can sync a directory, so errors are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	_ = d.Sync()
+}
+
+// AppendData adds the data to the end of the file.
+// Unlike SaveData it is not atomic, so a crash can leave only part of the data.
+func (s *DefaultStorageDriver) AppendData(data []byte) error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to append data: %w", err)
+	}
+	return f.Close()
+}
+
 func (s *DefaultStorageDriver) LoadData() ([]byte, error) {
 	return os.ReadFile(s.path)
 }
@@ -4,9 +4,8 @@
 package driver
 
 import (
-	"fmt"
 	"os"
-	"strings"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -22,23 +21,11 @@ var _ = Describe("StorageDriverDefault", func() {
 	var testByte []byte
 
 	BeforeEach(func() {
-		// 一意のファイル名を生成
-		testDescription := CurrentSpecReport()
-		testFileName := fmt.Sprintf("%s.json", strings.ReplaceAll(testDescription.FullText(), " ", "_"))
-		storageDriver = NewStorageDriver(testFileName)
+		// テストごとの一時ディレクトリに保存する
+		storageDriver = NewStorageDriver(filepath.Join(GinkgoT().TempDir(), "save.json"))
 		testByte = []byte("test")
 	})
 
-	AfterEach(func() {
-		// テスト終了後にファイルを削除
-		testDescription := CurrentSpecReport()
-		testFileName := fmt.Sprintf("%s.json", strings.ReplaceAll(testDescription.FullText(), " ", "_"))
-		if FileExists(testFileName) {
-			err := os.Remove(testFileName)
-			Expect(err).ToNot(HaveOccurred())
-		}
-	})
-
 	Describe("SaveData and LoadData", func() {
 		It("should save and load the game state correctly", func() {
 			// Save the game state
@@ -85,8 +72,29 @@ var _ = Describe("StorageDriverDefault", func() {
 			Expect(loadedByte).To(Equal([]byte{}))
 		})
 
+		It("should not leave temporary files behind", func() {
+			dir := GinkgoT().TempDir()
+			storageDriver = NewStorageDriver(filepath.Join(dir, "save.json"))
+			Expect(storageDriver.SaveData(testByte)).To(Succeed())
+			Expect(storageDriver.SaveData([]byte("new test"))).To(Succeed())
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Name()).To(Equal("save.json"))
+		})
+
+		It("should append to the file", func() {
+			Expect(storageDriver.SaveData(testByte)).To(Succeed())
+			Expect(AppendData(storageDriver, []byte(" more"))).To(Succeed())
+
+			loadedByte, err := storageDriver.LoadData()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loadedByte).To(Equal([]byte("test more")))
+		})
+
 		It("should return an error when saving fails", func() {
-			storageDriver = NewStorageDriver("invalid_path/test_save_file.json")
+			storageDriver = NewStorageDriver(filepath.Join(GinkgoT().TempDir(), "invalid_path", "test_save_file.json"))
 			err := storageDriver.SaveData([]byte("test"))
 			Expect(err).To(HaveOccurred())
 		})
@@ -5,43 +5,64 @@ package driver
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
 	"syscall/js"
 
 	"github.com/kmdkuk/clicker/config"
 )
 
+var errLocalStorageUnavailable = errors.New("localStorage is not available")
+
+// NewStorageDriver returns an IndexedDB driver, or a localStorage driver if IndexedDB cannot be opened
+// (e.g. in some private browsing modes)
 func NewStorageDriver(key string) StorageDriver {
 	if key == "" {
-		return &StorageWasm{
-			key: config.DefaultSaveKey,
-		}
+		key = config.DefaultSaveKey
 	}
-	return &StorageWasm{
+	legacy := &StorageWasm{
 		key: key,
 	}
+	if _, err := openIndexedDB(); err != nil {
+		return legacy
+	}
+	return &StorageIndexedDB{
+		key:    key,
+		legacy: legacy,
+	}
 }
 
+// StorageWasm stores the data in localStorage
 type StorageWasm struct {
 	key string
 }
 
-func (s *StorageWasm) SaveData(data []byte) error {
+func localStorage() (js.Value, error) {
 	localStorage := js.Global().Get("localStorage")
-	if localStorage.IsUndefined() {
-		return errors.New("localStorage is not available")
+	if localStorage.IsUndefined() || localStorage.IsNull() {
+		return js.Undefined(), errLocalStorageUnavailable
+	}
+	return localStorage, nil
+}
+
+func (s *StorageWasm) SaveData(data []byte) error {
+	localStorage, err := localStorage()
+	if err != nil {
+		return err
 	}
 	localStorage.Call("setItem", s.key, string(data))
 	return nil
 }
 
+// LoadData returns an error wrapping fs.ErrNotExist if no data is found, like the desktop driver
 func (s *StorageWasm) LoadData() ([]byte, error) {
-	localStorage := js.Global().Get("localStorage")
-	if localStorage.IsUndefined() {
-		return nil, errors.New("localStorage is not available")
+	localStorage, err := localStorage()
+	if err != nil {
+		return nil, err
 	}
 	data := localStorage.Call("getItem", s.key)
 	if data.IsNull() || data.IsUndefined() {
-		return nil, nil // Return nil if no data is found.
+		return nil, fmt.Errorf("%s: %w", s.key, fs.ErrNotExist)
 	}
 	return []byte(data.String()), nil
 }
@@ -49,3 +70,13 @@ func (s *StorageWasm) LoadData() ([]byte, error) {
 func (s *StorageWasm) GetKeyName() string {
 	return s.key
 }
+
+// remove deletes the data from localStorage, if localStorage is available at all
+func (s *StorageWasm) remove() error {
+	localStorage, err := localStorage()
+	if err != nil {
+		return nil
+	}
+	localStorage.Call("removeItem", s.key)
+	return nil
+}
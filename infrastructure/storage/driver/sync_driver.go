@@ -0,0 +1,284 @@
+package driver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Protocol of the sync server:
+//
+//	GET /saves/<name>  200 with the save and SavedAtHeader, or 404
+//	PUT /saves/<name>  204 with SavedAtHeader set, or 409 if the server has a newer save
+//	DELETE /saves/<name>  204, also if there is no save
+//
+// <name> is the base name of the save key, so the desktop and the browser builds share a slot.
+const (
+	SyncSavesPath = "/saves/"
+	// SavedAtHeader carries the time the save was written by the game, used to resolve conflicts
+	SavedAtHeader = "X-Clicker-Saved-At"
+	// syncTimeout keeps the game responsive when the server is unreachable
+	syncTimeout = 5 * time.Second
+)
+
+var (
+	ErrSyncUnavailable = errors.New("sync server is unavailable")
+	ErrSyncConflict    = errors.New("sync server has a newer save")
+)
+
+// SyncDriver keeps the save in a local driver and mirrors it to a sync server.
+//
+// Conflicts are resolved by last writer wins: the save written last by any game, according to the
+// time it was written, is the one kept. When the server is unreachable the local save is used and
+// pushed the next time the game saves or loads.
+// Saves are pushed in the background, so that an unreachable server does not hold up the game.
+type SyncDriver struct {
+	local    StorageDriver
+	meta     StorageDriver // Stores the time the local save was written
+	endpoint string
+	token    string
+	client   *http.Client
+	now      func() time.Time
+	// Only the newest save waiting to be pushed is kept
+	mu      sync.Mutex
+	idle    *sync.Cond // Broadcast when nothing is left to push
+	next    *pendingPush
+	pushing bool
+}
+
+// pendingPush is a save waiting to be pushed
+type pendingPush struct {
+	data    []byte
+	savedAt time.Time
+}
+
+// NewSyncDriver wraps the local driver to sync with the server at endpoint.
+// The token is sent as a bearer token if it is not empty.
+func NewSyncDriver(local StorageDriver, endpoint, token string) StorageDriver {
+	return newSyncDriver(local, endpoint, token)
+}
+
+func newSyncDriver(local StorageDriver, endpoint, token string) *SyncDriver {
+	d := &SyncDriver{
+		local:    local,
+		meta:     NewStorageDriver(local.GetKeyName() + ".sync"),
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+		client:   &http.Client{Timeout: syncTimeout},
+		now:      time.Now,
+	}
+	d.idle = sync.NewCond(&d.mu)
+	return d
+}
+
+// SaveData writes the save locally and pushes it in the background.
+// Errors from the server are only logged since the local save is kept and pushed again later.
+func (d *SyncDriver) SaveData(data []byte) error {
+	savedAt := d.now()
+	if err := d.saveLocal(data, savedAt); err != nil {
+		return err
+	}
+	d.pushLater(data, savedAt)
+	return nil
+}
+
+// Flush waits until the saves written so far are pushed or failed to be pushed
+func (d *SyncDriver) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for d.pushing {
+		d.idle.Wait()
+	}
+}
+
+// LoadData returns the newer of the local and the remote save and brings the other side up to date
+func (d *SyncDriver) LoadData() ([]byte, error) {
+	localData, localErr := d.local.LoadData()
+	localAt := d.localSavedAt()
+
+	remoteData, remoteAt, err := d.pull()
+	if err != nil {
+		slog.Warn("Failed to pull the save from the sync server, using the local save", "key", d.GetKeyName(), "error", err)
+		// Fall back to the local save, including its error if there is none
+		return localData, localErr
+	}
+	if remoteData == nil {
+		if localErr == nil {
+			d.pushLater(localData, localAt)
+		}
+		return localData, localErr
+	}
+	if localErr != nil || remoteAt.After(localAt) {
+		if err := d.saveLocal(remoteData, remoteAt); err != nil {
+			return nil, err
+		}
+		return remoteData, nil
+	}
+	if localAt.After(remoteAt) {
+		d.pushLater(localData, localAt)
+	}
+	return localData, nil
+}
+
+// pushLater pushes the save in the background, replacing a save that is still waiting to be pushed
+func (d *SyncDriver) pushLater(data []byte, savedAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.next = &pendingPush{data: bytes.Clone(data), savedAt: savedAt}
+	if !d.pushing {
+		d.pushing = true
+		go d.pushPending()
+	}
+}
+
+// pushPending pushes the waiting saves until none is left
+func (d *SyncDriver) pushPending() {
+	for {
+		d.mu.Lock()
+		p := d.next
+		d.next = nil
+		if p == nil {
+			d.pushing = false
+			d.idle.Broadcast()
+			d.mu.Unlock()
+			return
+		}
+		d.mu.Unlock()
+
+		err := d.push(p.data, p.savedAt)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrSyncConflict):
+			slog.Warn("Sync server has a newer save, it replaces the local save when the game loads", "key", d.GetKeyName())
+		default:
+			slog.Warn("Failed to push the save to the sync server, it is pushed again later", "key", d.GetKeyName(), "error", err)
+		}
+	}
+}
+
+// DeleteRemoteSave deletes the save stored under key from the sync server at endpoint,
+// so that a new slot of the same name does not pull it
+func DeleteRemoteSave(key, endpoint, token string) error {
+	d := newSyncDriver(NewStorageDriver(key), endpoint, token)
+	req, err := http.NewRequest(http.MethodDelete, d.url(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrSyncUnavailable, resp.Status)
+	}
+}
+
+func (d *SyncDriver) GetKeyName() string {
+	return d.local.GetKeyName()
+}
+
+func (d *SyncDriver) saveLocal(data []byte, savedAt time.Time) error {
+	if err := d.local.SaveData(data); err != nil {
+		return err
+	}
+	return d.meta.SaveData([]byte(savedAt.UTC().Format(time.RFC3339Nano)))
+}
+
+// localSavedAt returns the time the local save was written, or the zero time if it is unknown
+// (e.g. a save from before sync was enabled), so that any remote save wins over it
+func (d *SyncDriver) localSavedAt() time.Time {
+	data, err := d.meta.LoadData()
+	if err != nil {
+		return time.Time{}
+	}
+	savedAt, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}
+	}
+	return savedAt
+}
+
+func (d *SyncDriver) url() string {
+	return d.endpoint + SyncSavesPath + url.PathEscape(path.Base(d.local.GetKeyName()))
+}
+
+// pull fetches the remote save. A missing remote save returns nil data.
+func (d *SyncDriver) pull() ([]byte, time.Time, error) {
+	req, err := http.NewRequest(http.MethodGet, d.url(), nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resp, err := d.do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return readSave(resp)
+	case http.StatusNotFound:
+		return nil, time.Time{}, nil
+	default:
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrSyncUnavailable, resp.Status)
+	}
+}
+
+// push uploads the save. The server refuses it with ErrSyncConflict if it has a newer save.
+func (d *SyncDriver) push(data []byte, savedAt time.Time) error {
+	req, err := http.NewRequest(http.MethodPut, d.url(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(SavedAtHeader, savedAt.UTC().Format(time.RFC3339Nano))
+	resp, err := d.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return ErrSyncConflict
+	default:
+		return fmt.Errorf("%w: %s", ErrSyncUnavailable, resp.Status)
+	}
+}
+
+func (d *SyncDriver) do(req *http.Request) (*http.Response, error) {
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSyncUnavailable, err)
+	}
+	return resp, nil
+}
+
+func readSave(resp *http.Response) ([]byte, time.Time, error) {
+	savedAt, err := time.Parse(time.RFC3339Nano, resp.Header.Get(SavedAtHeader))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid %s header: %w", SavedAtHeader, err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrSyncUnavailable, err)
+	}
+	if len(data) == 0 {
+		return nil, time.Time{}, fmt.Errorf("%w: empty save", ErrSyncUnavailable)
+	}
+	return data, savedAt, nil
+}
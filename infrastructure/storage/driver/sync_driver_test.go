@@ -0,0 +1,105 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SyncDriver", func() {
+	var key string
+
+	BeforeEach(func() {
+		key = filepath.Join(GinkgoT().TempDir(), "game_state.json")
+	})
+
+	Context("when the server is unreachable", func() {
+		var syncDriver StorageDriver
+
+		BeforeEach(func() {
+			syncDriver = NewSyncDriver(NewStorageDriver(key), "http://127.0.0.1:1", "")
+		})
+
+		It("should save and load locally", func() {
+			Expect(syncDriver.SaveData([]byte("local"))).To(Succeed())
+
+			data, err := syncDriver.LoadData()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("local"))
+			Expect(syncDriver.GetKeyName()).To(Equal(key))
+		})
+
+		It("should report a missing save like the local driver", func() {
+			_, err := syncDriver.LoadData()
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+	})
+
+	Context("when the server fails", func() {
+		It("should use the local save", func() {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "down", http.StatusServiceUnavailable)
+			}))
+			defer ts.Close()
+			syncDriver := NewSyncDriver(NewStorageDriver(key), ts.URL, "")
+			Expect(syncDriver.SaveData([]byte("local"))).To(Succeed())
+
+			data, err := syncDriver.LoadData()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("local"))
+		})
+	})
+
+	It("should send the token and the base name of the key", func() {
+		var path, auth string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, auth = r.URL.Path, r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		_, _ = NewSyncDriver(NewStorageDriver(key), ts.URL+"/", "secret").LoadData()
+		Expect(path).To(Equal("/saves/game_state.json"))
+		Expect(auth).To(Equal("Bearer secret"))
+	})
+
+	It("should push in the background and only the newest of the waiting saves", func() {
+		release := make(chan struct{})
+		requested := make(chan struct{}, 3)
+		var mu sync.Mutex
+		pushed := []string{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested <- struct{}{}
+			<-release
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			pushed = append(pushed, string(body))
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		syncDriver := NewSyncDriver(NewStorageDriver(key), ts.URL, "")
+
+		// The server does not answer, but the saves return at once
+		Expect(syncDriver.SaveData([]byte("first"))).To(Succeed())
+		Eventually(requested).Should(Receive())
+		Expect(syncDriver.SaveData([]byte("second"))).To(Succeed())
+		Expect(syncDriver.SaveData([]byte("third"))).To(Succeed())
+		close(release)
+
+		Flush(syncDriver)
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(pushed).To(HaveLen(2))
+		Expect(pushed[1]).To(Equal("third"))
+	})
+})
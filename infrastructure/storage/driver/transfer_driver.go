@@ -0,0 +1,8 @@
+package driver
+
+// TransferDriver moves an exported save string in and out of the game
+type TransferDriver interface {
+	Export(text string) error
+	Import() (string, error)
+	Describe() string
+}
@@ -0,0 +1,42 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmdkuk/clicker/config"
+)
+
+// NewTransferDriver returns a driver that exchanges the save string through a text file next to the save
+func NewTransferDriver(key string) TransferDriver {
+	if key == "" {
+		key = config.DefaultSaveKey
+	}
+	return &DefaultTransferDriver{
+		path: strings.TrimSuffix(key, filepath.Ext(key)) + ".export.txt",
+	}
+}
+
+type DefaultTransferDriver struct {
+	path string
+}
+
+func (t *DefaultTransferDriver) Export(text string) error {
+	return os.WriteFile(t.path, []byte(text+"\n"), 0644)
+}
+
+func (t *DefaultTransferDriver) Import() (string, error) {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (t *DefaultTransferDriver) Describe() string {
+	return t.path
+}
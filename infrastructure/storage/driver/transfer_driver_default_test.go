@@ -0,0 +1,39 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package driver
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransferDriverDefault", func() {
+	var (
+		transferDriver TransferDriver
+		dir            string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		transferDriver = NewTransferDriver(filepath.Join(dir, "transfer_test.json"))
+	})
+
+	It("should write the export next to the save", func() {
+		Expect(transferDriver.Describe()).To(Equal(filepath.Join(dir, "transfer_test.export.txt")))
+	})
+
+	It("should import what was exported", func() {
+		Expect(transferDriver.Export("clicker:abc")).To(Succeed())
+		text, err := transferDriver.Import()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(Equal("clicker:abc\n"))
+	})
+
+	It("should return an error if nothing was exported", func() {
+		_, err := transferDriver.Import()
+		Expect(err).To(HaveOccurred())
+	})
+})
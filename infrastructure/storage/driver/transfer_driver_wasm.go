@@ -0,0 +1,43 @@
+//go:build js && wasm
+// +build js,wasm
+
+package driver
+
+import (
+	"errors"
+	"syscall/js"
+)
+
+// NewTransferDriver returns a driver that exchanges the save string through a browser prompt,
+// since localStorage cannot be shared between browsers
+func NewTransferDriver(key string) TransferDriver {
+	return &TransferWasm{}
+}
+
+type TransferWasm struct{}
+
+func (t *TransferWasm) Export(text string) error {
+	prompt := js.Global().Get("prompt")
+	if prompt.IsUndefined() {
+		return errors.New("prompt is not available")
+	}
+	// The prompt shows the string in a selectable text field so that it can be copied
+	prompt.Invoke("Copy this string to back up your save:", text)
+	return nil
+}
+
+func (t *TransferWasm) Import() (string, error) {
+	prompt := js.Global().Get("prompt")
+	if prompt.IsUndefined() {
+		return "", errors.New("prompt is not available")
+	}
+	text := prompt.Invoke("Paste an exported save string:")
+	if text.IsNull() || text.IsUndefined() {
+		return "", errors.New("import cancelled")
+	}
+	return text.String(), nil
+}
+
+func (t *TransferWasm) Describe() string {
+	return "the browser prompt"
+}
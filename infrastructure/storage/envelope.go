@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// envelopeFormat identifies a save wrapped in an integrity envelope
+const envelopeFormat = "clicker-save"
+
+// firstEnvelopeVersion is the first save version written in an envelope.
+// A save of this version or later without one had its envelope removed.
+const firstEnvelopeVersion = 4
+
+// integrityKey signs the payload. It ships with the game, so the signature makes
+// casual edits evident but is not meant to stop a determined player.
+var integrityKey = []byte("clicker-save-integrity-v1")
+
+var ErrCorruptedSave = errors.New("save is corrupted")
+
+// envelope wraps the serialized Save with its version and integrity data
+type envelope struct {
+	Format    string          `json:"format"`
+	Version   int             `json:"version"`
+	Payload   json.RawMessage `json:"payload"`
+	Checksum  string          `json:"checksum"`  // SHA-256 of the payload, detects bad writes
+	Signature string          `json:"signature"` // HMAC-SHA256 of the payload, detects edits
+}
+
+// Integrity describes how trustworthy a loaded save is
+type Integrity int
+
+const (
+	IntegrityValid     Integrity = iota // The envelope is intact
+	IntegrityLegacy                     // Written before saves had an envelope
+	IntegrityEdited                     // Well-formed, but the payload does not match its checksum or signature
+	IntegrityCorrupted                  // Not even well-formed, e.g. after a bad write
+)
+
+func (i Integrity) String() string {
+	switch i {
+	case IntegrityValid:
+		return "valid"
+	case IntegrityLegacy:
+		return "legacy"
+	case IntegrityEdited:
+		return "edited"
+	case IntegrityCorrupted:
+		return "corrupted"
+	default:
+		return fmt.Sprintf("Integrity(%d)", int(i))
+	}
+}
+
+// wrapEnvelope wraps the serialized save in an integrity envelope
+func wrapEnvelope(payload []byte) ([]byte, error) {
+	return json.Marshal(envelope{
+		Format:    envelopeFormat,
+		Version:   CurrentSaveVersion,
+		Payload:   payload,
+		Checksum:  checksum(payload),
+		Signature: signature(payload),
+	})
+}
+
+// unwrapEnvelope returns the serialized save and how trustworthy it is.
+// A bad write truncates or garbles the data so that it is no longer well-formed, while a payload
+// that is still an object but does not match its checksum or signature was edited by hand.
+// Saves without an envelope are returned as they are, and flagged as edited if their version
+// was already written in an envelope.
+// An envelope from a newer version is not inspected and reported as ErrNewerSaveVersion.
+func unwrapEnvelope(data []byte) ([]byte, Integrity, error) {
+	probe := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return data, IntegrityCorrupted, nil
+	}
+	if _, ok := probe["payload"]; !ok {
+		if version, err := detectSaveVersion(probe); err == nil && version >= firstEnvelopeVersion {
+			return data, IntegrityEdited, nil
+		}
+		return data, IntegrityLegacy, nil
+	}
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil || e.Format != envelopeFormat {
+		return data, IntegrityCorrupted, nil
+	}
+	if e.Version > CurrentSaveVersion {
+		return nil, IntegrityValid, fmt.Errorf("%w: version %d, supported up to %d", ErrNewerSaveVersion, e.Version, CurrentSaveVersion)
+	}
+	payload := map[string]json.RawMessage{}
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return e.Payload, IntegrityCorrupted, nil
+	}
+	if e.Checksum != checksum(e.Payload) || !hmac.Equal([]byte(e.Signature), []byte(signature(e.Payload))) {
+		return e.Payload, IntegrityEdited, nil
+	}
+	return e.Payload, IntegrityValid, nil
+}
+
+func checksum(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func signature(payload []byte) string {
+	mac := hmac.New(sha256.New, integrityKey)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// decodeSaved unwraps data written by SaveGameState and decodes the save
+func decodeSaved(data []byte) Save {
+	payload, integrity, err := unwrapEnvelope(data)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(integrity).To(Equal(IntegrityValid))
+	var save Save
+	Expect(json.Unmarshal(payload, &save)).To(Succeed())
+	return save
+}
+
+// editEnvelope replaces old with new in the payload and recomputes the checksum,
+// as a player who edits a save by hand would, but cannot sign it without the key
+func editEnvelope(data []byte, old, new string) []byte {
+	e := envelope{}
+	Expect(json.Unmarshal(data, &e)).To(Succeed())
+	e.Payload = json.RawMessage(strings.Replace(string(e.Payload), old, new, 1))
+	e.Checksum = checksum(e.Payload)
+	edited, err := json.Marshal(e)
+	Expect(err).NotTo(HaveOccurred())
+	return edited
+}
+
+var _ = Describe("Envelope", func() {
+	var data []byte
+
+	BeforeEach(func() {
+		var err error
+		data, err = wrapEnvelope([]byte(`{"version":4,"money":10}`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("unwrapEnvelope",
+		func(modify func(string) string, expected Integrity) {
+			_, integrity, err := unwrapEnvelope([]byte(modify(string(data))))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(integrity).To(Equal(expected))
+		},
+		Entry("intact", func(s string) string { return s }, IntegrityValid),
+		Entry("payload that does not match the checksum", func(s string) string {
+			return strings.Replace(s, `"money":10`, `"money":99999`, 1)
+		}, IntegrityEdited),
+		Entry("recomputed checksum without the key", func(s string) string {
+			return string(editEnvelope([]byte(s), `"money":10`, `"money":99999`))
+		}, IntegrityEdited),
+		Entry("checksum intact but signature replaced", func(s string) string {
+			e := envelope{}
+			Expect(json.Unmarshal([]byte(s), &e)).To(Succeed())
+			return strings.Replace(s, e.Signature, strings.Repeat("0", len(e.Signature)), 1)
+		}, IntegrityEdited),
+		Entry("truncated by a bad write", func(s string) string { return s[:len(s)/2] }, IntegrityCorrupted),
+		Entry("payload is not an object", func(s string) string {
+			return strings.Replace(s, `{"version":4,"money":10}`, `"garbage"`, 1)
+		}, IntegrityCorrupted),
+		Entry("save without envelope", func(string) string { return `{"money":10}` }, IntegrityLegacy),
+		Entry("save from before the envelope", func(string) string { return `{"version":3,"money":10}` }, IntegrityLegacy),
+		Entry("payload with its envelope removed", func(string) string { return `{"version":4,"money":10}` }, IntegrityEdited),
+	)
+
+	It("should reject an envelope from a newer version", func() {
+		_, _, err := unwrapEnvelope([]byte(`{"format":"clicker-save","version":99,"payload":{}}`))
+		Expect(err).To(MatchError(ErrNewerSaveVersion))
+	})
+
+	Describe("loading", func() {
+		var (
+			mockDriver  *driver.FaultDriver
+			store       *driver.MemoryStore
+			testStorage *DefaultStorage
+		)
+
+		BeforeEach(func() {
+			testStorage, mockDriver, store = newTestStorage(0)
+			gameState := state.NewGameState()
+			gameState.UpdateMoney(10)
+			Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+		})
+
+		It("should load an intact save without flagging it", func() {
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testStorage.LastIntegrity()).To(Equal(IntegrityValid))
+			Expect(gameState.IsTampered()).To(BeFalse())
+		})
+
+		It("should load an edited save and flag it", func() {
+			store.Set(testSaveKey, editEnvelope(store.Get(testSaveKey), `"money":10`, `"money":99999`))
+
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testStorage.LastIntegrity()).To(Equal(IntegrityEdited))
+			Expect(gameState.GetMoney()).To(Equal(99999.0))
+			Expect(gameState.IsTampered()).To(BeTrue())
+
+			// The flag survives saving and loading again
+			Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+			gameState, err = testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testStorage.LastIntegrity()).To(Equal(IntegrityValid))
+			Expect(gameState.IsTampered()).To(BeTrue())
+		})
+
+		It("should load a save that does not match its checksum and flag it", func() {
+			store.Set(testSaveKey, []byte(strings.Replace(string(store.Get(testSaveKey)), `"money":10`, `"money":99999`, 1)))
+
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testStorage.LastIntegrity()).To(Equal(IntegrityEdited))
+			Expect(gameState.GetMoney()).To(Equal(99999.0))
+			Expect(gameState.IsTampered()).To(BeTrue())
+		})
+
+		It("should load a save without its envelope and flag it", func() {
+			payload, _, err := unwrapEnvelope(store.Get(testSaveKey))
+			Expect(err).NotTo(HaveOccurred())
+			store.Set(testSaveKey, payload)
+
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testStorage.LastIntegrity()).To(Equal(IntegrityEdited))
+			Expect(gameState.IsTampered()).To(BeTrue())
+		})
+
+		It("should report a corrupted save", func() {
+			mockDriver.CorruptLoad = func(data []byte) []byte {
+				return data[:len(data)-10]
+			}
+
+			_, err := testStorage.LoadGameState()
+			Expect(err).To(MatchError(ErrCorruptedSave))
+			Expect(testStorage.LastIntegrity()).To(Equal(IntegrityCorrupted))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+)
+
+// ExportPrefix marks an exported save string so that random text is rejected early
+const ExportPrefix = "clicker:"
+
+const exportChecksumSize = 4
+
+var ErrInvalidExport = errors.New("invalid save string")
+
+// EncodeExport encodes the save as a compact text string.
+// The JSON is deflated, followed by its CRC32 checksum and encoded with URL safe base64.
+func EncodeExport(save Save) (string, error) {
+	data, err := json.Marshal(save)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal save data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", fmt.Errorf("failed to compress save data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", fmt.Errorf("failed to compress save data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress save data: %w", err)
+	}
+	buf.Write(binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(data)))
+
+	return ExportPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeExport decodes a string created by EncodeExport.
+// Older save versions are migrated and the result is validated before it is returned.
+func DecodeExport(text string) (Save, error) {
+	text = strings.Join(strings.Fields(text), "")
+	if !strings.HasPrefix(text, ExportPrefix) {
+		return Save{}, fmt.Errorf("%w: missing prefix", ErrInvalidExport)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(text, ExportPrefix))
+	if err != nil {
+		return Save{}, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	if len(raw) < exportChecksumSize {
+		return Save{}, fmt.Errorf("%w: too short", ErrInvalidExport)
+	}
+	compressed, checksum := raw[:len(raw)-exportChecksumSize], raw[len(raw)-exportChecksumSize:]
+
+	data, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return Save{}, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(checksum) {
+		return Save{}, fmt.Errorf("%w: checksum mismatch", ErrInvalidExport)
+	}
+
+	data, _, err = migrateSave(data)
+	if err != nil {
+		return Save{}, err
+	}
+	var save Save
+	if err := json.Unmarshal(data, &save); err != nil {
+		return Save{}, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	if err := save.Validation(); err != nil {
+		return Save{}, err
+	}
+	return save, nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/kmdkuk/clicker/domain/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	var save Save
+
+	BeforeEach(func() {
+		market := model.NewMarket(3)
+		save = Save{
+			Version:        CurrentSaveVersion,
+			Money:          42.5,
+			Buildings:      map[string]int{"cpu_miner": 3, "gpu_rig": 1},
+			BuildingLevels: map[string]int{"cpu_miner": 1, "gpu_rig": 0},
+			Upgradings: []upgrade{
+				{ID: "cpu_miner_0", IsPurchased: true},
+			},
+			ManualWork: 7,
+			Coins:      1.5,
+			Market:     &market,
+		}
+	})
+
+	It("should round trip a save", func() {
+		text, err := EncodeExport(save)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(HavePrefix(ExportPrefix))
+
+		decoded, err := DecodeExport(text)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(save))
+	})
+
+	It("should ignore whitespace around and inside the string", func() {
+		text, err := EncodeExport(save)
+		Expect(err).NotTo(HaveOccurred())
+
+		wrapped := "  " + text[:20] + "\n" + text[20:] + "\n"
+		decoded, err := DecodeExport(wrapped)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.Money).To(Equal(42.5))
+	})
+
+	It("should reject a string without the prefix", func() {
+		_, err := DecodeExport("hello")
+		Expect(errors.Is(err, ErrInvalidExport)).To(BeTrue())
+	})
+
+	It("should reject a string with a wrong checksum", func() {
+		text, err := EncodeExport(save)
+		Expect(err).NotTo(HaveOccurred())
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(text, ExportPrefix))
+		Expect(err).NotTo(HaveOccurred())
+		raw[len(raw)-1] ^= 0xff
+
+		_, err = DecodeExport(ExportPrefix + base64.RawURLEncoding.EncodeToString(raw))
+		Expect(errors.Is(err, ErrInvalidExport)).To(BeTrue())
+	})
+
+	It("should reject a save that fails validation", func() {
+		save.Money = -1
+		text, err := EncodeExport(save)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = DecodeExport(text)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Storage faults injected through driver.FaultDriver
+var _ = Describe("Faults", func() {
+	var (
+		mockDriver  *driver.FaultDriver
+		store       *driver.MemoryStore
+		testStorage *DefaultStorage
+	)
+
+	saveMoney := func(money float64) error {
+		gameState := state.NewGameState()
+		gameState.UpdateMoney(money)
+		return testStorage.SaveGameState(gameState)
+	}
+
+	BeforeEach(func() {
+		testStorage, mockDriver, store = newTestStorage(3)
+		Expect(saveMoney(10)).To(Succeed())
+	})
+
+	It("should restore the last backup after a crash during save", func() {
+		mockDriver.TruncateSave = 40
+		Expect(saveMoney(20)).To(MatchError(driver.ErrInjectedFault))
+		Expect(store.Get(testSaveKey)).To(HaveLen(40))
+		mockDriver.TruncateSave = 0
+
+		gameState, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gameState.GetMoney()).To(Equal(10.0))
+		Expect(testStorage.LastIntegrity()).To(Equal(IntegrityCorrupted))
+		Expect(testStorage.LastLoadReport().RestoredBackup).To(Equal(1))
+		// The truncated save is kept for inspection
+		Expect(store.Get(testStorage.corruptKey())).To(HaveLen(40))
+	})
+
+	It("should keep the previous save when a save fails", func() {
+		mockDriver.SaveError = errors.New("disk full")
+		Expect(saveMoney(20)).To(MatchError("disk full"))
+		mockDriver.SaveError = nil
+
+		gameState, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gameState.GetMoney()).To(Equal(10.0))
+		Expect(testStorage.LastLoadReport().Outcome).To(Equal(LoadClean))
+	})
+
+	It("should restore the last backup when the save is garbled on the disk", func() {
+		mockDriver.CorruptLoad = driver.FlipByte(-1)
+
+		gameState, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gameState.GetMoney()).To(Equal(10.0))
+		Expect(testStorage.LastIntegrity()).To(Equal(IntegrityCorrupted))
+	})
+
+	It("should keep partial JSON for inspection when there is no backup", func() {
+		testStorage, mockDriver, store = newTestStorage(0)
+		store.Set(testSaveKey, []byte(`{"version": 3, "money": 42, "manualWork": 7, "buildings": {`))
+
+		_, err := testStorage.LoadGameState()
+		Expect(err).To(HaveOccurred())
+		Expect(testStorage.LastLoadReport().Outcome).To(Equal(LoadFailed))
+
+		// The broken save is kept before the new game overwrites it
+		Expect(saveMoney(1)).To(Succeed())
+		Expect(string(store.Get(testStorage.corruptKey()))).To(ContainSubstring(`"money": 42`))
+	})
+
+	It("should start a new game when neither the save nor the backups can be read", func() {
+		for _, key := range []string{testSaveKey, testStorage.backupKey(1)} {
+			data := store.Get(key)
+			store.Set(key, data[:len(data)/2])
+		}
+
+		gameState, err := testStorage.LoadGameState()
+		Expect(err).To(MatchError(ErrCorruptedSave))
+		Expect(gameState.GetMoney()).To(BeZero())
+		Expect(testStorage.LastLoadReport().Outcome).To(Equal(LoadFailed))
+	})
+})
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ReadSave decodes a save file without recovering or fixing anything, for inspecting it.
+// Older versions are migrated, but the result is not validated.
+func ReadSave(data []byte) (Save, Integrity, error) {
+	payload, integrity, err := unwrapEnvelope(data)
+	if err != nil {
+		return Save{}, integrity, err
+	}
+	if integrity == IntegrityCorrupted {
+		return Save{}, integrity, ErrCorruptedSave
+	}
+	payload, _, err = migrateSave(payload)
+	if err != nil {
+		return Save{}, integrity, fmt.Errorf("failed to migrate save: %w", err)
+	}
+	var save Save
+	if err := json.Unmarshal(payload, &save); err != nil {
+		return Save{}, integrity, fmt.Errorf("failed to unmarshal save: %w", err)
+	}
+	return save, integrity, nil
+}
+
+// RepairSave decodes a save file the way LoadGameState does when no backup helps:
+// JSON that does not match the save is partially recovered and validation errors are fixed.
+// An edited save is repaired too and stays flagged as tampered.
+func RepairSave(data []byte) (Save, error) {
+	payload, integrity, err := unwrapEnvelope(data)
+	if err != nil {
+		return Save{}, err
+	}
+	payload, _, err = migrateSave(payload)
+	if err != nil {
+		return Save{}, fmt.Errorf("failed to migrate save: %w", err)
+	}
+
+	var save Save
+	if err := json.Unmarshal(payload, &save); err != nil {
+		if save, _, err = recoverPartialSave(payload); err != nil {
+			return Save{}, fmt.Errorf("cannot recover data: %w", err)
+		}
+	}
+	if validationErr := save.Validation(); validationErr != nil {
+		if save, err = fixInvalidSave(save, validationErr); err != nil {
+			return Save{}, fmt.Errorf("failed to fix invalid save: %w", err)
+		}
+	}
+	if integrity == IntegrityEdited {
+		save.Tampered = true
+	}
+	return save, nil
+}
+
+// SetUpgradePurchased marks the upgrade as purchased or not, adding it to the save if missing
+func (s *Save) SetUpgradePurchased(id string, isPurchased bool) {
+	for i := range s.Upgradings {
+		if s.Upgradings[i].ID == id {
+			s.Upgradings[i].IsPurchased = isPurchased
+			return
+		}
+	}
+	s.Upgradings = append(s.Upgradings, upgrade{ID: id, IsPurchased: isPurchased})
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inspect", func() {
+	var data []byte
+
+	BeforeEach(func() {
+		var err error
+		data, err = EncodeSave(Save{Version: CurrentSaveVersion, Money: 10, Buildings: map[string]int{"cpu_miner": 1, "gpu_rig": 2}})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("ReadSave", func() {
+		It("should read a save as SaveGameState writes it", func() {
+			save, integrity, err := ReadSave(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(integrity).To(Equal(IntegrityValid))
+			Expect(save.Money).To(Equal(10.0))
+			Expect(save.Buildings).To(Equal(map[string]int{"cpu_miner": 1, "gpu_rig": 2}))
+		})
+
+		It("should migrate an old save", func() {
+			legacy, err := os.ReadFile("testdata/save_v2.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			save, integrity, err := ReadSave(legacy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(integrity).To(Equal(IntegrityLegacy))
+			Expect(save.Version).To(Equal(CurrentSaveVersion))
+			Expect(save.Money).To(Equal(220.5))
+		})
+
+		It("should report an edited save without failing", func() {
+			edited := editEnvelope(data, `"money":10`, `"money":-5`)
+
+			save, integrity, err := ReadSave(edited)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(integrity).To(Equal(IntegrityEdited))
+			Expect(save.Money).To(Equal(-5.0))
+		})
+
+		It("should fail on a corrupted save", func() {
+			_, integrity, err := ReadSave(data[:len(data)/2])
+			Expect(err).To(MatchError(ErrCorruptedSave))
+			Expect(integrity).To(Equal(IntegrityCorrupted))
+		})
+	})
+
+	Describe("RepairSave", func() {
+		It("should fix validation errors and keep the edit flagged", func() {
+			edited := editEnvelope(data, `"money":10`, `"money":-5`)
+
+			save, err := RepairSave(edited)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(save.Validation()).To(Succeed())
+			Expect(save.Money).To(Equal(0.0))
+			Expect(save.Tampered).To(BeTrue())
+		})
+
+		It("should recover what it can from mistyped fields", func() {
+			save, err := RepairSave([]byte(`{"version":4,"money":42,"buildings":"broken"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(save.Money).To(Equal(42.0))
+		})
+
+		It("should not repair data that is not JSON", func() {
+			_, err := RepairSave([]byte(`{"money":`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("SetUpgradePurchased", func() {
+		It("should update or add the upgrade", func() {
+			save := Save{}
+			save.SetUpgradePurchased("cpu_miner_0", true)
+			save.SetUpgradePurchased("cpu_miner_0", false)
+			save.SetUpgradePurchased("cpu_miner_1", true)
+			Expect(save.Upgradings).To(HaveLen(2))
+			Expect(save.Upgradings[0].IsPurchased).To(BeFalse())
+			Expect(save.Upgradings[1].IsPurchased).To(BeTrue())
+		})
+	})
+})
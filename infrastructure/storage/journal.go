@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+)
+
+// journalFormat identifies the header of a journal
+const journalFormat = "clicker-journal"
+
+var errStaleJournal = errors.New("journal does not extend the snapshot")
+
+// journalHeader is the first line of a journal and names the snapshot the records apply to
+type journalHeader struct {
+	Format   string `json:"format"`
+	Snapshot string `json:"snapshot"` // Checksum of the snapshot as it was written
+}
+
+// journalRecord holds the changes of the fields of the save since the previous record
+type journalRecord struct {
+	Changes   []journalChange `json:"changes"`
+	Signature string          `json:"signature"` // HMAC-SHA256 of the changes, detects torn or edited records
+}
+
+// JournalStorage appends the changes of the save to a journal instead of rewriting the save every time.
+//
+// The save itself is a snapshot written by DefaultStorage, with its backups and recovery.
+// Each SaveGameState appends one line with the fields that changed, down to a single building count
+// or the prices added to the history, so saving after every action is cheap.
+// Every compactEvery records and on the final save the game state is written as a new snapshot
+// and the journal starts over.
+// On load the journal is replayed on the snapshot. A record cut short by a crash ends the replay,
+// and a journal written for another snapshot (e.g. when the game crashed during the compaction) is ignored.
+type JournalStorage struct {
+	snapshot     *DefaultStorage
+	journal      driver.StorageDriver
+	compactEvery int
+	last         any  // Decoded save as of the last record
+	records      int  // Number of records since the snapshot
+	ready        bool // The journal extends the current snapshot
+}
+
+// NewJournalStorage creates a journal storage that compacts the journal every compactEvery records
+func NewJournalStorage(storageDriver driver.StorageDriver, backupRetention, compactEvery int) Storage {
+	return NewJournalStorageWithBackupDriver(storageDriver, backupRetention, compactEvery, driver.NewStorageDriver)
+}
+
+// NewJournalStorageWithBackupDriver creates a journal storage that writes its journal, backups and
+// the broken save through the drivers returned by newBackupDriver
+func NewJournalStorageWithBackupDriver(storageDriver driver.StorageDriver, backupRetention, compactEvery int, newBackupDriver func(key string) driver.StorageDriver) Storage {
+	snapshot := NewDefaultStorageWithBackupDriver(storageDriver, backupRetention, newBackupDriver).(*DefaultStorage)
+	if compactEvery < 1 {
+		compactEvery = 1
+	}
+	return &JournalStorage{
+		snapshot:     snapshot,
+		journal:      newBackupDriver(snapshot.journalKey()),
+		compactEvery: compactEvery,
+	}
+}
+
+// SaveGameState appends the fields that changed since the last save, or writes a snapshot
+// when the journal is due for compaction
+func (s *JournalStorage) SaveGameState(state state.GameState) error {
+	tree, err := saveTree(ConverToSave(state))
+	if err != nil {
+		return err
+	}
+	if !s.ready || s.records >= s.compactEvery {
+		return s.compact(state)
+	}
+
+	changes := diffTree("", s.last, tree, nil)
+	if len(changes) == 0 {
+		return nil
+	}
+	line, err := encodeJournalLine(journalRecord{Changes: changes, Signature: signature(marshalChanges(changes))})
+	if err != nil {
+		return err
+	}
+	if err := driver.AppendData(s.journal, line); err != nil {
+		// Records after a torn one are not replayed, so write a snapshot next time instead
+		s.ready = false
+		return fmt.Errorf("failed to append to journal: %w", err)
+	}
+	s.last = tree
+	s.records++
+	return nil
+}
+
+// SaveFinalGameState compacts the journal into a new snapshot and waits until the driver has written it.
+// Only the snapshot is synced, so the sync server gets the records of the session this way.
+func (s *JournalStorage) SaveFinalGameState(state state.GameState) error {
+	err := s.compact(state)
+	driver.Flush(s.snapshot.storageDriver)
+	return err
+}
+
+// compact writes the game state as a new snapshot and starts the journal over
+func (s *JournalStorage) compact(state state.GameState) error {
+	if err := s.snapshot.SaveGameState(state); err != nil {
+		s.ready = false
+		return err
+	}
+	return s.startJournal(state)
+}
+
+// startJournal starts an empty journal for the snapshot just written for the game state
+func (s *JournalStorage) startJournal(state state.GameState) error {
+	s.ready = false
+	tree, err := saveTree(ConverToSave(state))
+	if err != nil {
+		return err
+	}
+	// The snapshot is encoded the same way SaveGameState wrote it
+	data, err := encodeGameState(state)
+	if err != nil {
+		return err
+	}
+	header, err := encodeJournalLine(journalHeader{Format: journalFormat, Snapshot: checksum(data)})
+	if err != nil {
+		return err
+	}
+	if err := s.journal.SaveData(header); err != nil {
+		return fmt.Errorf("failed to start journal: %w", err)
+	}
+	s.last = tree
+	s.records = 0
+	s.ready = true
+	return nil
+}
+
+// LoadGameState loads the snapshot and replays the journal on it.
+// The journal is only replayed on a snapshot that loaded cleanly; after a recovery the next save writes a snapshot.
+func (s *JournalStorage) LoadGameState() (state.GameState, error) {
+	s.ready = false
+	gameState, err := s.snapshot.LoadGameState()
+	if err != nil || s.snapshot.LastLoadReport().Outcome != LoadClean {
+		return gameState, err
+	}
+
+	records, err := s.readJournal()
+	if err != nil {
+		if !errors.Is(err, errStaleJournal) {
+			slog.Warn("Ignoring journal", "key", s.journal.GetKeyName(), "error", err)
+		}
+		return gameState, nil
+	}
+	tree, err := saveTree(ConverToSave(gameState))
+	if err != nil {
+		return gameState, nil
+	}
+	for _, changes := range records {
+		for _, change := range changes {
+			if tree, err = applyChange(tree, change); err != nil {
+				slog.Warn("Ignoring journal", "key", s.journal.GetKeyName(), "error", err)
+				return gameState, nil
+			}
+		}
+	}
+	var save Save
+	if err := json.Unmarshal(marshalTree(tree), &save); err != nil {
+		slog.Warn("Ignoring journal", "key", s.journal.GetKeyName(), "error", err)
+		return gameState, nil
+	}
+	if err := save.Validation(); err != nil {
+		slog.Warn("Ignoring journal", "key", s.journal.GetKeyName(), "error", err)
+		return gameState, nil
+	}
+	if err := save.ApplyToGameState(gameState); err != nil {
+		return gameState, fmt.Errorf("failed to replay journal: %w", err)
+	}
+	if len(records) > 0 {
+		slog.Info("Replayed journal", "records", len(records))
+	}
+	s.last = tree
+	s.records = len(records)
+	s.ready = true
+	return gameState, nil
+}
+
+// readJournal returns the changes of each record of the journal for the loaded snapshot.
+// Reading stops at the first record that is cut short or does not match its signature.
+func (s *JournalStorage) readJournal() ([][]journalChange, error) {
+	data, err := s.journal.LoadData()
+	if err != nil {
+		return nil, err
+	}
+	lines := bytes.Split(data, []byte("\n"))
+	var header journalHeader
+	if err := json.Unmarshal(lines[0], &header); err != nil || header.Format != journalFormat {
+		return nil, fmt.Errorf("invalid journal header")
+	}
+	if header.Snapshot != s.snapshot.loadedChecksum {
+		return nil, errStaleJournal
+	}
+
+	records := [][]journalChange{}
+	for i, line := range lines[1:] {
+		if len(line) == 0 {
+			continue
+		}
+		var record journalRecord
+		if err := json.Unmarshal(line, &record); err != nil ||
+			!hmac.Equal([]byte(record.Signature), []byte(signature(marshalChanges(record.Changes)))) {
+			slog.Warn("Dropping the rest of the journal from a broken record", "record", i+1, "dropped", len(lines)-1-i)
+			break
+		}
+		records = append(records, record.Changes)
+	}
+	return records, nil
+}
+
+func (s *JournalStorage) LastLoadReport() LoadReport {
+	return s.snapshot.LastLoadReport()
+}
+
+// ExportGameState encodes the game state as a portable text string
+func (s *JournalStorage) ExportGameState(state state.GameState) (string, error) {
+	return s.snapshot.ExportGameState(state)
+}
+
+// ImportGameState replaces the game state with an exported save string and writes it as a snapshot
+func (s *JournalStorage) ImportGameState(state state.GameState, text string) error {
+	if err := s.snapshot.ImportGameState(state, text); err != nil {
+		return err
+	}
+	return s.startJournal(state)
+}
+
+// RestoreBackupInto replaces the game state with the n-th backup and writes it as a snapshot
+func (s *JournalStorage) RestoreBackupInto(state state.GameState, n int) error {
+	if err := s.snapshot.RestoreBackupInto(state, n); err != nil {
+		return err
+	}
+	return s.startJournal(state)
+}
+
+func encodeJournalLine(v any) ([]byte, error) {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal journal: %w", err)
+	}
+	return append(line, '\n'), nil
+}
+
+// marshalChanges encodes the changes as they are signed. The values come from parsed JSON, so they always marshal.
+func marshalChanges(changes []journalChange) []byte {
+	data, _ := json.Marshal(changes)
+	return data
+}
+
+// journalKey returns the key the journal of the save is stored under
+func (s *DefaultStorage) journalKey() string {
+	return s.baseKey() + ".journal"
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JournalStorage", func() {
+	var (
+		store         *driver.MemoryStore
+		journalDriver *driver.FaultDriver
+		testStorage   Storage
+		gameState     state.GameState
+	)
+
+	journalKey := testSaveKey + ".journal"
+	newJournalStorage := func(compactEvery int) Storage {
+		return NewJournalStorageWithBackupDriver(store.NewStorageDriver(testSaveKey), 3, compactEvery, func(key string) driver.StorageDriver {
+			if key == journalKey {
+				return journalDriver
+			}
+			return store.NewStorageDriver(key)
+		})
+	}
+	journalLines := func() []string {
+		return strings.Split(strings.TrimSuffix(string(store.Get(journalKey)), "\n"), "\n")
+	}
+	earn := func(money float64) {
+		gameState.UpdateMoney(money)
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+	}
+	reload := func() state.GameState {
+		loaded, err := newJournalStorage(10).LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		return loaded
+	}
+
+	BeforeEach(func() {
+		store = driver.NewMemoryStore()
+		journalDriver = driver.NewFaultDriver(store.NewStorageDriver(journalKey))
+		testStorage = newJournalStorage(3)
+		gameState = state.NewGameState()
+		earn(10)
+	})
+
+	It("should write a snapshot and start the journal on the first save", func() {
+		Expect(decodeSaved(store.Get(testSaveKey)).Money).To(Equal(10.0))
+		Expect(journalLines()).To(HaveLen(1))
+		Expect(journalLines()[0]).To(ContainSubstring(journalFormat))
+	})
+
+	It("should append only the changed fields without rewriting the snapshot", func() {
+		snapshot := store.Get(testSaveKey)
+		earn(5)
+
+		Expect(store.Get(testSaveKey)).To(Equal(snapshot))
+		Expect(journalLines()).To(HaveLen(2))
+		Expect(journalLines()[1]).To(ContainSubstring(`{"op":"set","path":"/money","value":15}`))
+		Expect(journalLines()[1]).NotTo(ContainSubstring("buildings"))
+	})
+
+	It("should record a single building or upgrade instead of the whole list", func() {
+		Expect(gameState.SetBuildingCount(1, 3)).To(Succeed())
+		Expect(gameState.SetUpgradesIsPurchased(2, true)).To(Succeed())
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+
+		Expect(journalLines()[1]).To(ContainSubstring(`{"op":"set","path":"/buildings/gpu_rig","value":3}`))
+		Expect(journalLines()[1]).To(ContainSubstring(`{"op":"set","path":"/upgradings/2/is_purchased","value":true}`))
+		Expect(journalLines()[1]).NotTo(ContainSubstring("cpu_miner"))
+
+		loaded := reload()
+		Expect(loaded.GetBuildings()[1].Count).To(Equal(3))
+		Expect(loaded.GetUpgrades()[2].IsPurchased).To(BeTrue())
+	})
+
+	It("should record only the prices added to the history", func() {
+		market := *gameState.GetMarket()
+		market.History = append([]float64(nil), market.History...)
+		for range model.MarketHistorySize {
+			market.History = append(market.History, 2)
+		}
+		market.History = market.History[len(market.History)-model.MarketHistorySize:]
+		gameState.SetMarket(market)
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+
+		market.History = append(market.History[1:], 3)
+		gameState.SetMarket(market)
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+
+		Expect(journalLines()[2]).To(ContainSubstring(`{"op":"append","path":"/market/history","drop":1,"values":[3]}`))
+		Expect(reload().GetMarket().History).To(Equal(market.History))
+	})
+
+	It("should not append anything when nothing changed", func() {
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+		Expect(journalLines()).To(HaveLen(1))
+	})
+
+	It("should replay the journal on the snapshot when loading", func() {
+		earn(5)
+		Expect(gameState.SetBuildingCount(0, 2)).To(Succeed())
+		earn(1)
+
+		loaded := reload()
+		Expect(loaded.GetMoney()).To(Equal(16.0))
+		Expect(loaded.GetBuildings()[0].Count).To(Equal(2))
+	})
+
+	It("should compact the journal into a snapshot every few records", func() {
+		earn(1)
+		earn(1)
+		earn(1)
+		Expect(journalLines()).To(HaveLen(4))
+
+		earn(1)
+		Expect(journalLines()).To(HaveLen(1))
+		Expect(decodeSaved(store.Get(testSaveKey)).Money).To(Equal(14.0))
+		Expect(reload().GetMoney()).To(Equal(14.0))
+	})
+
+	It("should compact the journal into a snapshot on the final save", func() {
+		earn(1)
+		gameState.UpdateMoney(1)
+		Expect(testStorage.SaveFinalGameState(gameState)).To(Succeed())
+
+		Expect(journalLines()).To(HaveLen(1))
+		Expect(decodeSaved(store.Get(testSaveKey)).Money).To(Equal(12.0))
+		Expect(reload().GetMoney()).To(Equal(12.0))
+	})
+
+	It("should keep the records before one cut short by a crash", func() {
+		earn(5)
+		journalDriver.TruncateSave = 10
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+		gameState.UpdateMoney(5)
+		Expect(testStorage.SaveGameState(gameState)).To(MatchError(driver.ErrInjectedFault))
+		journalDriver.TruncateSave = 0
+
+		Expect(reload().GetMoney()).To(Equal(15.0))
+
+		// The next save writes a snapshot instead of appending after the broken record
+		earn(1)
+		Expect(journalLines()).To(HaveLen(1))
+		Expect(reload().GetMoney()).To(Equal(21.0))
+	})
+
+	It("should ignore a journal left over from a crash during compaction", func() {
+		earn(1)
+		earn(1)
+		earn(1)
+		oldJournal := store.Get(journalKey)
+		earn(1)
+		// The snapshot was written but the game crashed before the journal started over
+		store.Set(journalKey, oldJournal)
+
+		Expect(reload().GetMoney()).To(Equal(14.0))
+	})
+
+	It("should ignore a journal that was edited", func() {
+		earn(5)
+		store.Set(journalKey, bytes.Replace(store.Get(journalKey), []byte(`"value":15`), []byte(`"value":99999`), 1))
+
+		Expect(reload().GetMoney()).To(Equal(10.0))
+	})
+
+	It("should start the journal over after an import", func() {
+		earn(5)
+		text, err := testStorage.ExportGameState(gameState)
+		Expect(err).NotTo(HaveOccurred())
+		earn(5)
+
+		Expect(testStorage.ImportGameState(gameState, text)).To(Succeed())
+		Expect(journalLines()).To(HaveLen(1))
+		Expect(reload().GetMoney()).To(Equal(15.0))
+	})
+})
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+)
+
+// MigrateLegacySaves moves the saves that older versions wrote to legacyDir (the working directory) into dir.
+// Each slot is moved together with its backups. A slot that already has a save in dir is left untouched,
+// so a save made after the first migration is never overwritten. Returns the moved slots.
+func MigrateLegacySaves(legacyDir, dir string) ([]string, error) {
+	if sameDir(legacyDir, dir) {
+		return nil, nil
+	}
+	legacy := driver.NewKeyManager(legacyDir)
+	legacyKeys, err := legacy.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legacy saves: %w", err)
+	}
+	keys, err := driver.NewKeyManager(dir).ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saves: %w", err)
+	}
+	existing := map[string]bool{}
+	for _, key := range keys {
+		existing[filepath.Base(key)] = true
+	}
+
+	// セーブ本体を最後に移動して、途中で失敗しても次の起動でやり直せるようにする
+	sort.SliceStable(legacyKeys, func(i, j int) bool {
+		return strings.HasSuffix(legacyKeys[i], ".bak") && !strings.HasSuffix(legacyKeys[j], ".bak")
+	})
+	moved := []string{}
+	for _, key := range legacyKeys {
+		name := filepath.Base(key)
+		saveKey, ok := legacySaveKey(name)
+		if !ok || existing[saveKey] {
+			continue
+		}
+		data, err := driver.NewStorageDriver(key).LoadData()
+		if err != nil {
+			return moved, fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		if err := driver.NewStorageDriver(filepath.Join(dir, name)).SaveData(data); err != nil {
+			return moved, fmt.Errorf("failed to move %s: %w", key, err)
+		}
+		if err := legacy.DeleteKey(key); err != nil {
+			return moved, fmt.Errorf("failed to remove %s: %w", key, err)
+		}
+		if name == saveKey {
+			slot, _ := config.SlotFromSaveKey(saveKey)
+			moved = append(moved, slot)
+		}
+	}
+	return moved, nil
+}
+
+// legacySaveKey returns the save file the file belongs to, for the save itself and its backups
+func legacySaveKey(name string) (string, bool) {
+	if strings.HasSuffix(name, ".bak") {
+		// <save>.<n>.bak or <save>.corrupt.bak
+		name = strings.TrimSuffix(name, ".bak")
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if _, ok := config.SlotFromSaveKey(name); !ok {
+		return "", false
+	}
+	return name, true
+}
+
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
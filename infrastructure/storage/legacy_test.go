@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MigrateLegacySaves", func() {
+	var legacyDir, dir string
+
+	saveMoney := func(key string, money float64) {
+		gameState := state.NewGameState()
+		gameState.UpdateMoney(money)
+		Expect(NewDefaultStorage(driver.NewStorageDriver(key)).SaveGameState(gameState)).To(Succeed())
+	}
+	loadMoney := func(key string) float64 {
+		gameState, err := NewDefaultStorage(driver.NewStorageDriver(key)).LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		return gameState.GetMoney()
+	}
+
+	BeforeEach(func() {
+		legacyDir = GinkgoT().TempDir()
+		dir = GinkgoT().TempDir()
+	})
+
+	It("should move the saves and their backups", func() {
+		saveMoney(filepath.Join(legacyDir, "game_state.json"), 10)
+		saveMoney(filepath.Join(legacyDir, "game_state.balance.json"), 20)
+		Expect(os.WriteFile(filepath.Join(legacyDir, "notes.txt"), []byte("keep"), 0644)).To(Succeed())
+
+		moved, err := MigrateLegacySaves(legacyDir, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(moved).To(ConsistOf("default", "balance"))
+
+		Expect(loadMoney(filepath.Join(dir, "game_state.json"))).To(Equal(10.0))
+		Expect(loadMoney(filepath.Join(dir, "game_state.balance.json"))).To(Equal(20.0))
+		Expect(filepath.Join(dir, "game_state.json.1.bak")).To(BeAnExistingFile())
+
+		entries, err := os.ReadDir(legacyDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name()).To(Equal("notes.txt"))
+	})
+
+	It("should not overwrite a save in the new directory", func() {
+		saveMoney(filepath.Join(legacyDir, "game_state.json"), 10)
+		saveMoney(filepath.Join(dir, "game_state.json"), 30)
+
+		moved, err := MigrateLegacySaves(legacyDir, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(moved).To(BeEmpty())
+		Expect(loadMoney(filepath.Join(dir, "game_state.json"))).To(Equal(30.0))
+		Expect(filepath.Join(legacyDir, "game_state.json")).To(BeAnExistingFile())
+	})
+
+	It("should do nothing when the directories are the same", func() {
+		saveMoney(filepath.Join(dir, "game_state.json"), 10)
+
+		moved, err := MigrateLegacySaves(dir, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(moved).To(BeEmpty())
+		Expect(loadMoney(filepath.Join(dir, "game_state.json"))).To(Equal(10.0))
+	})
+})
+
+var _ = DescribeTable("legacySaveKey",
+	func(name, expected string, ok bool) {
+		key, found := legacySaveKey(name)
+		Expect(found).To(Equal(ok))
+		Expect(key).To(Equal(expected))
+	},
+	Entry("default save", "game_state.json", "game_state.json", true),
+	Entry("slot save", "game_state.balance.json", "game_state.balance.json", true),
+	Entry("backup", "game_state.json.2.bak", "game_state.json", true),
+	Entry("corrupt backup", "game_state.balance.json.corrupt.bak", "game_state.balance.json", true),
+	Entry("export", "game_state.export.txt", "", false),
+	Entry("unrelated file", "go.mod", "", false),
+)
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+)
+
+// LoadOutcome tells how the last LoadGameState went
+type LoadOutcome int
+
+const (
+	LoadClean     LoadOutcome = iota // The save was loaded as it was saved
+	LoadNew                          // There was no save yet and a new game was started
+	LoadRecovered                    // The save was broken and was restored from a backup or fixed
+	LoadFailed                       // Nothing could be recovered and a new game was started
+)
+
+// LoadReport describes the last LoadGameState so that the player can be told what happened to the save
+type LoadReport struct {
+	Outcome        LoadOutcome
+	Problem        error           // Why the save could not be loaded as it was saved
+	RestoredBackup int             // Backup the game was restored from, 0 if it was not
+	Fixed          bool            // Invalid values of the save were reset and the rest was kept
+	Recovered      []string        // Parts recovered from a damaged save, nil if it was not damaged
+	UnknownContent []string        // Buildings and upgrades of the save the game does not know and left out
+	BrokenSaveKey  string          // Where the broken save is kept, empty if it could not be read
+	Backups        []BackupSummary // Other backups the player can restore instead
+}
+
+// BackupSummary describes a backup the player can choose to restore
+type BackupSummary struct {
+	Number int
+	Money  float64
+}
+
+// LastLoadReport returns the report of the last LoadGameState
+func (s *DefaultStorage) LastLoadReport() LoadReport {
+	return s.report
+}
+
+// RestoreBackupInto replaces the game state with the n-th backup.
+// The current state is backed up first, so the restore can be undone by restoring again.
+func (s *DefaultStorage) RestoreBackupInto(state state.GameState, n int) error {
+	save, err := s.loadBackupSave(n)
+	if errors.Is(err, errBackupMissing) {
+		return fmt.Errorf("backup %d: %w", n, ErrNoValidBackup)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load backup %d: %w", n, err)
+	}
+
+	current, err := encodeGameState(state)
+	if err != nil {
+		return err
+	}
+	if err := s.rotateBackups(current); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if err := save.ApplyToGameState(state); err != nil {
+		return fmt.Errorf("failed to apply backup %d: %w", n, err)
+	}
+	return s.SaveGameState(state)
+}
+
+// finishReport completes the report once LoadGameState has decided on the game state
+func (s *DefaultStorage) finishReport(gameState state.GameState) {
+	switch {
+	case s.report.Problem == nil:
+		s.report.Outcome = LoadClean
+		return
+	case s.report.RestoredBackup > 0 || s.report.Fixed || s.report.Recovered != nil:
+		s.report.Outcome = LoadRecovered
+	case errors.Is(s.report.Problem, fs.ErrNotExist):
+		s.report.Outcome = LoadNew
+		return
+	default:
+		s.report.Outcome = LoadFailed
+	}
+	s.report.Backups = s.restorableBackups(gameState)
+}
+
+// restorableBackups lists the valid backups, leaving out the ones identical to the current game state
+func (s *DefaultStorage) restorableBackups(current state.GameState) []BackupSummary {
+	currentData, _ := encodeGameState(current)
+	var backups []BackupSummary
+	for i := 1; i <= s.backupRetention; i++ {
+		save, err := s.loadBackupSave(i)
+		if err != nil {
+			continue
+		}
+		if data, err := s.newBackupDriver(s.backupKey(i)).LoadData(); err == nil && bytes.Equal(data, currentData) {
+			continue
+		}
+		backups = append(backups, BackupSummary{Number: i, Money: save.Money})
+	}
+	return backups
+}
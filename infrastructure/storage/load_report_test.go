@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"os"
+
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadReport", func() {
+	var (
+		mockDriver  *driver.FaultDriver
+		store       *driver.MemoryStore
+		testStorage *DefaultStorage
+	)
+
+	saveMoney := func(money float64) {
+		gameState := state.NewGameState()
+		gameState.UpdateMoney(money)
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		testStorage, mockDriver, store = newTestStorage(3)
+	})
+
+	It("should report a clean load", func() {
+		saveMoney(10)
+
+		_, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testStorage.LastLoadReport()).To(Equal(LoadReport{Outcome: LoadClean}))
+	})
+
+	It("should report a new game when there is no save", func() {
+		mockDriver.LoadError = os.ErrNotExist
+
+		_, err := testStorage.LoadGameState()
+		Expect(err).To(HaveOccurred())
+		Expect(testStorage.LastLoadReport().Outcome).To(Equal(LoadNew))
+	})
+
+	It("should not keep a broken save on the first save of a new game", func() {
+		mockDriver.LoadError = os.ErrNotExist
+		_, err := testStorage.LoadGameState()
+		Expect(err).To(HaveOccurred())
+		Expect(testStorage.LastLoadReport().BrokenSaveKey).To(BeEmpty())
+		mockDriver.LoadError = nil
+
+		loads := mockDriver.Loads
+		saveMoney(10)
+		Expect(mockDriver.Loads).To(Equal(loads))
+		Expect(store.Get(testStorage.corruptKey())).To(BeNil())
+	})
+
+	It("should report the backup the save was restored from and the older backups", func() {
+		saveMoney(10)
+		saveMoney(20)
+		store.Set(testSaveKey, []byte(`not json`))
+
+		_, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		report := testStorage.LastLoadReport()
+		Expect(report.Outcome).To(Equal(LoadRecovered))
+		Expect(report.Problem).To(HaveOccurred())
+		Expect(report.RestoredBackup).To(Equal(1))
+		Expect(report.BrokenSaveKey).To(Equal(testStorage.corruptKey()))
+		Expect(store.Get(report.BrokenSaveKey)).To(Equal([]byte(`not json`)))
+		// Backups identical to the restored game are left out
+		Expect(report.Backups).To(Equal([]BackupSummary{{Number: 3, Money: 10}}))
+	})
+
+	It("should report content the game does not know and keep the original save", func() {
+		original := []byte(`{"version": 5, "money": 5, "buildings": {"cpu_miner": 2, "removed_miner": 3}, "upgradings": [{"id": "removed_miner_0", "is_purchased": true}]}`)
+		store.Set(testSaveKey, original)
+
+		gameState, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gameState.GetBuildings()[0].Count).To(Equal(2))
+		report := testStorage.LastLoadReport()
+		Expect(report.Outcome).To(Equal(LoadClean))
+		Expect(report.UnknownContent).To(Equal([]string{"building removed_miner", "upgrade removed_miner_0"}))
+		Expect(report.BrokenSaveKey).To(Equal(testStorage.corruptKey()))
+
+		Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+		Expect(store.Get(report.BrokenSaveKey)).To(Equal(original))
+	})
+
+	It("should report a fixed save", func() {
+		store.Set(testSaveKey, []byte(`{"version": 3, "money": -5}`))
+
+		_, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		report := testStorage.LastLoadReport()
+		Expect(report.Outcome).To(Equal(LoadRecovered))
+		Expect(report.Fixed).To(BeTrue())
+		Expect(report.RestoredBackup).To(BeZero())
+	})
+
+	It("should report the parts recovered from a damaged save", func() {
+		store.Set(testSaveKey, []byte(`{"version": 3, "money": 5, "coins": 2, "buildings": "broken"}`))
+
+		gameState, err := testStorage.LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gameState.GetMoney()).To(Equal(5.0))
+		report := testStorage.LastLoadReport()
+		Expect(report.Outcome).To(Equal(LoadRecovered))
+		Expect(report.Recovered).To(Equal([]string{"money", "coins"}))
+	})
+
+	It("should report a failure when nothing could be recovered", func() {
+		store.Set(testSaveKey, []byte(`not json`))
+
+		_, err := testStorage.LoadGameState()
+		Expect(err).To(HaveOccurred())
+		report := testStorage.LastLoadReport()
+		Expect(report.Outcome).To(Equal(LoadFailed))
+		Expect(report.BrokenSaveKey).To(Equal(testStorage.corruptKey()))
+		Expect(report.Backups).To(BeEmpty())
+	})
+
+	Describe("RestoreBackupInto", func() {
+		It("should replace the game state with the backup and back up the current game", func() {
+			saveMoney(10)
+			saveMoney(20)
+			gameState := state.NewGameState()
+			gameState.UpdateMoney(30)
+
+			Expect(testStorage.RestoreBackupInto(gameState, 2)).To(Succeed())
+			Expect(gameState.GetMoney()).To(Equal(10.0))
+			Expect(decodeSaved(store.Get(testSaveKey)).Money).To(Equal(10.0))
+
+			restored, err := testStorage.loadBackup(2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.GetMoney()).To(Equal(30.0))
+		})
+
+		It("should fail for a missing backup", func() {
+			Expect(testStorage.RestoreBackupInto(state.NewGameState(), 1)).NotTo(Succeed())
+		})
+	})
+})
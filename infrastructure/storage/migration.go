@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CurrentSaveVersion is the version written by SaveGameState.
+// Bump it together with a new entry in migrations whenever the save format changes.
+const CurrentSaveVersion = 5
+
+// ErrNewerSaveVersion is returned when the save was written by a newer version of the game.
+// Such a save must not be overwritten, so callers should stop instead of starting a new game.
+var ErrNewerSaveVersion = errors.New("save was written by a newer version of the game")
+
+// saveDocument is a save in any version, decoded only at the top level
+type saveDocument map[string]json.RawMessage
+
+// migration upgrades a save document by exactly one version
+type migration func(doc saveDocument) error
+
+// migrations[i] upgrades a save from version i+1 to version i+2
+var migrations = []migration{
+	migrateV1ToV2,
+	migrateV2ToV3,
+	migrateV3ToV4,
+	migrateV4ToV5,
+}
+
+// v1 saves used the Go field names as keys
+var v1Keys = map[string]string{
+	"Money":      "money",
+	"Buildings":  "buildings",
+	"Upgradings": "upgradings",
+	"ManualWork": "manual_work",
+}
+
+// migrateV1ToV2 renames the capitalised keys of v1 to snake_case
+func migrateV1ToV2(doc saveDocument) error {
+	for oldKey, newKey := range v1Keys {
+		value, ok := doc[oldKey]
+		if !ok {
+			continue
+		}
+		delete(doc, oldKey)
+		if _, exists := doc[newKey]; !exists {
+			doc[newKey] = value
+		}
+	}
+	return nil
+}
+
+// migrateV2ToV3 only introduces the version field.
+// building_levels, coins and market were added as optional fields, so their zero values are valid.
+func migrateV2ToV3(doc saveDocument) error {
+	return nil
+}
+
+// migrateV3ToV4 does not change the payload.
+// v4 saves are wrapped in an integrity envelope and gained the optional tampered flag.
+func migrateV3ToV4(doc saveDocument) error {
+	return nil
+}
+
+// v4BuildingIDs are the IDs of the buildings in the order v4 and older saves stored them.
+// Keep the list as it is even when game/level changes; content changes go through migrateContent.
+var v4BuildingIDs = []string{
+	"cpu_miner",
+	"gpu_rig",
+	"asic_miner",
+	"mining_farm",
+	"staking_pool",
+	"dex_platform",
+	"layer2_network",
+	"blockchain_validator",
+	"quantum_mining_cluster",
+	"ai_trading_algorithm",
+}
+
+// migrateV4ToV5 keys buildings by their ID instead of their position,
+// and renames the building upgrades from "<index>_<n>" to "<building>_<n>".
+// Positions no building ever had are kept as "index_<n>" so that they are reported as unknown.
+func migrateV4ToV5(doc saveDocument) error {
+	for _, field := range []string{"buildings", "building_levels"} {
+		raw, ok := doc[field]
+		if !ok {
+			continue
+		}
+		var values []int
+		if err := json.Unmarshal(raw, &values); err != nil {
+			// Leave it to the partial recovery
+			continue
+		}
+		keyed := make(map[string]int, len(values))
+		for i, value := range values {
+			keyed[v4BuildingID(i)] = value
+		}
+		doc[field], _ = json.Marshal(keyed)
+	}
+
+	var upgradings []upgrade
+	if err := json.Unmarshal(doc["upgradings"], &upgradings); err != nil || upgradings == nil {
+		return nil
+	}
+	for i, u := range upgradings {
+		upgradings[i].ID = v4UpgradeID(u.ID)
+	}
+	doc["upgradings"], _ = json.Marshal(upgradings)
+	return nil
+}
+
+func v4BuildingID(i int) string {
+	if i < len(v4BuildingIDs) {
+		return v4BuildingIDs[i]
+	}
+	return fmt.Sprintf("index_%d", i)
+}
+
+// v4UpgradeID returns the v5 ID of a v4 upgrade ID, leaving IDs that were not positional as they are
+func v4UpgradeID(id string) string {
+	prefix := ""
+	rest := id
+	if after, ok := strings.CutPrefix(id, "efficiency_"); ok {
+		prefix, rest = "efficiency_", after
+	}
+	index, n, ok := strings.Cut(rest, "_")
+	if !ok || !isNumber(index) || !isNumber(n) {
+		return id
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return id
+	}
+	return prefix + v4BuildingID(i) + "_" + n
+}
+
+// detectSaveVersion returns the version of the document.
+// Saves written before versioning have no version field (or 0) and are told apart by their keys.
+func detectSaveVersion(doc saveDocument) (int, error) {
+	if raw, ok := doc["version"]; ok {
+		var version int
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return 0, fmt.Errorf("invalid save version: %w", err)
+		}
+		if version < 0 {
+			return 0, fmt.Errorf("invalid save version: %d", version)
+		}
+		if version > 0 {
+			return version, nil
+		}
+	}
+	for key := range v1Keys {
+		if _, ok := doc[key]; ok {
+			return 1, nil
+		}
+	}
+	return 2, nil
+}
+
+// migrateSave upgrades the save data to CurrentSaveVersion and returns it with the original version.
+// Renamed and removed content is migrated as well.
+func migrateSave(data []byte) ([]byte, int, error) {
+	doc := saveDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode save: %w", err)
+	}
+	version, err := detectSaveVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > CurrentSaveVersion {
+		return nil, version, fmt.Errorf("%w: version %d, supported up to %d", ErrNewerSaveVersion, version, CurrentSaveVersion)
+	}
+	for v := version; v < CurrentSaveVersion; v++ {
+		if err := migrations[v-1](doc); err != nil {
+			return nil, version, fmt.Errorf("failed to migrate save from version %d to %d: %w", v, v+1, err)
+		}
+	}
+	migrateContent(doc)
+	doc["version"] = json.RawMessage(fmt.Sprint(CurrentSaveVersion))
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, version, fmt.Errorf("failed to encode migrated save: %w", err)
+	}
+	return migrated, version, nil
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func loadGolden(name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
+
+var _ = Describe("Migration", func() {
+	It("should have a migration for every older version", func() {
+		Expect(migrations).To(HaveLen(CurrentSaveVersion - 1))
+	})
+
+	DescribeTable("detectSaveVersion",
+		func(data string, expected int) {
+			doc := saveDocument{}
+			Expect(json.Unmarshal([]byte(data), &doc)).To(Succeed())
+			version, err := detectSaveVersion(doc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(expected))
+		},
+		Entry("capitalised keys", `{"Money": 1}`, 1),
+		Entry("snake_case keys", `{"money": 1}`, 2),
+		Entry("version zero", `{"version": 0, "money": 1}`, 2),
+		Entry("explicit version", `{"version": 3, "money": 1}`, 3),
+	)
+
+	It("should reject an invalid version", func() {
+		_, _, err := migrateSave([]byte(`{"version": "three"}`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject data that is not an object", func() {
+		_, _, err := migrateSave([]byte(`[1, 2, 3]`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail on saves from a newer version", func() {
+		_, version, err := migrateSave(loadGolden("save_future.json"))
+		Expect(errors.Is(err, ErrNewerSaveVersion)).To(BeTrue())
+		Expect(version).To(Equal(99))
+	})
+
+	Describe("migrateV4ToV5", func() {
+		It("should key buildings by the v4 order and keep positions no building had", func() {
+			doc := saveDocument{}
+			Expect(json.Unmarshal([]byte(`{"buildings":[1,2,0,0,0,0,0,0,0,0,7],"building_levels":[1]}`), &doc)).To(Succeed())
+			Expect(migrateV4ToV5(doc)).To(Succeed())
+			Expect(string(doc["buildings"])).To(ContainSubstring(`"cpu_miner":1`))
+			Expect(string(doc["buildings"])).To(ContainSubstring(`"gpu_rig":2`))
+			Expect(string(doc["buildings"])).To(ContainSubstring(`"index_10":7`))
+			Expect(string(doc["building_levels"])).To(Equal(`{"cpu_miner":1}`))
+		})
+
+		DescribeTable("upgrade IDs",
+			func(id, expected string) {
+				Expect(v4UpgradeID(id)).To(Equal(expected))
+			},
+			Entry("building upgrade", "3_14", "mining_farm_14"),
+			Entry("efficiency upgrade", "efficiency_9_2", "efficiency_ai_trading_algorithm_2"),
+			Entry("manual work upgrade", "manual_work_4", "manual_work_4"),
+			Entry("unknown building", "12_0", "index_12_0"),
+		)
+	})
+
+	Describe("content migrations", func() {
+		BeforeEach(func() {
+			buildingRenames["old_miner"] = "cpu_miner"
+			buildingRenames["retired_rig"] = ""
+			upgradeRenames["manual_work_old"] = "manual_work_1"
+			DeferCleanup(func() {
+				delete(buildingRenames, "old_miner")
+				delete(buildingRenames, "retired_rig")
+				delete(upgradeRenames, "manual_work_old")
+			})
+		})
+
+		It("should rename and drop content after the tables", func() {
+			data := []byte(`{"version":5,"buildings":{"old_miner":4,"retired_rig":2,"gpu_rig":1},"building_levels":{"old_miner":1},` +
+				`"upgradings":[{"id":"old_miner_0","is_purchased":true},{"id":"efficiency_retired_rig_1","is_purchased":true},{"id":"manual_work_old","is_purchased":true}]}`)
+			migrated, _, err := migrateSave(data)
+			Expect(err).NotTo(HaveOccurred())
+
+			var save Save
+			Expect(json.Unmarshal(migrated, &save)).To(Succeed())
+			Expect(save.Buildings).To(Equal(map[string]int{"cpu_miner": 4, "gpu_rig": 1}))
+			Expect(save.BuildingLevels).To(Equal(map[string]int{"cpu_miner": 1}))
+			Expect(save.Upgradings).To(Equal([]upgrade{
+				{ID: "cpu_miner_0", IsPurchased: true},
+				{ID: "manual_work_1", IsPurchased: true},
+			}))
+			Expect(save.UnknownContent()).To(BeEmpty())
+		})
+
+		It("should keep the current entry when the new ID is already in the save", func() {
+			data := []byte(`{"version":5,"buildings":{"old_miner":4,"cpu_miner":6}}`)
+			migrated, _, err := migrateSave(data)
+			Expect(err).NotTo(HaveOccurred())
+
+			var save Save
+			Expect(json.Unmarshal(migrated, &save)).To(Succeed())
+			Expect(save.Buildings).To(Equal(map[string]int{"cpu_miner": 6}))
+		})
+	})
+
+	Describe("golden files", func() {
+		var (
+			mockDriver  *driver.FaultDriver
+			store       *driver.MemoryStore
+			testStorage Storage
+		)
+
+		BeforeEach(func() {
+			testStorage, mockDriver, store = newTestStorage(config.DefaultBackupRetention)
+		})
+
+		It("should load a v1 save", func() {
+			store.Set(testSaveKey, loadGolden("save_v1.json"))
+			gameState, err := testStorage.LoadGameState()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gameState.GetMoney()).To(Equal(120.5))
+			Expect(gameState.GetBuildings()[0].Count).To(Equal(4))
+			Expect(gameState.GetBuildings()[1].Count).To(Equal(2))
+			Expect(gameState.GetUpgrades()[0].IsPurchased).To(BeTrue())
+			Expect(gameState.GetUpgrades()[1].IsPurchased).To(BeFalse())
+			Expect(gameState.GetManualWork().Count).To(Equal(33))
+		})
+
+		It("should load a v2 save", func() {
+			store.Set(testSaveKey, loadGolden("save_v2.json"))
+			gameState, err := testStorage.LoadGameState()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gameState.GetMoney()).To(Equal(220.5))
+			Expect(gameState.GetBuildings()[2].Count).To(Equal(1))
+			Expect(gameState.GetUpgrades()[0].IsPurchased).To(BeTrue())
+			Expect(gameState.GetManualWork().Count).To(Equal(44))
+			Expect(gameState.GetCoins()).To(Equal(0.0))
+		})
+
+		It("should load a v3 save", func() {
+			store.Set(testSaveKey, loadGolden("save_v3.json"))
+			gameState, err := testStorage.LoadGameState()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gameState.GetMoney()).To(Equal(320.5))
+			Expect(gameState.GetBuildings()[0].Count).To(Equal(8))
+			Expect(gameState.GetBuildings()[0].Level).To(Equal(1))
+			Expect(gameState.GetManualWork().Count).To(Equal(55))
+			Expect(gameState.GetCoins()).To(Equal(1.25))
+			Expect(gameState.GetMarket().Price).To(Equal(1.5))
+			Expect(gameState.GetMarket().History).To(Equal([]float64{1, 1.2, 1.5}))
+		})
+
+		It("should load a v4 save and key its buildings and upgrades by ID", func() {
+			store.Set(testSaveKey, loadGolden("save_v4.json"))
+			gameState, err := testStorage.LoadGameState()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testStorage.LastLoadReport().Outcome).To(Equal(LoadClean))
+			Expect(gameState.GetMoney()).To(Equal(420.5))
+			Expect(gameState.GetBuildings()[0].Count).To(Equal(9))
+			Expect(gameState.GetBuildings()[0].Level).To(Equal(2))
+			Expect(gameState.GetBuildings()[2].Count).To(Equal(3))
+
+			purchased := []string{}
+			for _, u := range gameState.GetUpgrades() {
+				if u.IsPurchased {
+					purchased = append(purchased, u.ID)
+				}
+			}
+			Expect(purchased).To(ConsistOf("cpu_miner_0", "gpu_rig_2", "efficiency_asic_miner_0", "manual_work_0"))
+		})
+
+		It("should write the current version", func() {
+			store.Set(testSaveKey, loadGolden("save_v1.json"))
+			gameState, err := testStorage.LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testStorage.SaveGameState(gameState)).To(Succeed())
+			save := decodeSaved(store.Get(testSaveKey))
+			Expect(save.Version).To(Equal(CurrentSaveVersion))
+			Expect(save.Money).To(Equal(120.5))
+		})
+
+		It("should fail loudly on a save from a newer version", func() {
+			store.Set(testSaveKey, loadGolden("save_future.json"))
+			_, err := testStorage.LoadGameState()
+
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, ErrNewerSaveVersion)).To(BeTrue())
+			Expect(mockDriver.Saves).To(BeZero())
+		})
+	})
+})
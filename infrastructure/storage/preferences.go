@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+)
+
+// CurrentPreferencesVersion is the version of the preferences format written by this build
+const CurrentPreferencesVersion = 1
+
+// PreferencesStorage stores the preferences of the player apart from the game progress
+type PreferencesStorage interface {
+	LoadPreferences() (model.Preferences, error)
+	SavePreferences(preferences model.Preferences) error
+}
+
+type DefaultPreferencesStorage struct {
+	driver driver.StorageDriver
+}
+
+func NewPreferencesStorage(driver driver.StorageDriver) PreferencesStorage {
+	return &DefaultPreferencesStorage{
+		driver: driver,
+	}
+}
+
+// preferencesDocument is the stored form of model.Preferences
+type preferencesDocument struct {
+	Version          int    `json:"version"`
+	AutoSaveSeconds  int    `json:"autosave_seconds"`
+	NumberFormat     string `json:"number_format"`
+	Theme            string `json:"theme,omitempty"`
+	SoundVolume      int    `json:"sound_volume"`
+	ConfirmPurchases bool   `json:"confirm_purchases"`
+	DebugOverlay     bool   `json:"debug_overlay"`
+	// Action names to key names, left out while the default keys are used
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"`
+}
+
+// LoadPreferences returns the stored preferences, or the defaults if none are stored yet.
+// Values out of range are replaced with the defaults. On an error the defaults are returned with it.
+func (s *DefaultPreferencesStorage) LoadPreferences() (model.Preferences, error) {
+	data, err := s.driver.LoadData()
+	if errors.Is(err, fs.ErrNotExist) {
+		return model.DefaultPreferences(), nil
+	}
+	if err != nil {
+		return model.DefaultPreferences(), fmt.Errorf("failed to load preferences: %w", err)
+	}
+
+	// Fields missing from the document keep their defaults
+	defaults := model.DefaultPreferences()
+	doc := preferencesDocument{
+		AutoSaveSeconds: int(defaults.AutoSaveInterval / time.Second),
+		NumberFormat:    string(defaults.NumberFormat),
+		SoundVolume:     defaults.SoundVolume,
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return defaults, fmt.Errorf("failed to decode preferences: %w", err)
+	}
+	if doc.Version > CurrentPreferencesVersion {
+		slog.Warn("Preferences were written by a newer version, reading the known settings", "version", doc.Version)
+	}
+
+	preferences := model.Preferences{
+		AutoSaveInterval: time.Duration(doc.AutoSaveSeconds) * time.Second,
+		NumberFormat:     model.NumberFormat(doc.NumberFormat),
+		Theme:            doc.Theme,
+		SoundVolume:      doc.SoundVolume,
+		ConfirmPurchases: doc.ConfirmPurchases,
+		DebugOverlay:     doc.DebugOverlay,
+		KeyBindings:      doc.KeyBindings,
+	}
+	if replaced := preferences.Normalize(); replaced > 0 {
+		slog.Warn("Replaced invalid preferences with the defaults", "count", replaced)
+	}
+	return preferences, nil
+}
+
+func (s *DefaultPreferencesStorage) SavePreferences(preferences model.Preferences) error {
+	data, err := json.MarshalIndent(preferencesDocument{
+		Version:          CurrentPreferencesVersion,
+		AutoSaveSeconds:  int(preferences.AutoSaveInterval / time.Second),
+		NumberFormat:     string(preferences.NumberFormat),
+		Theme:            preferences.Theme,
+		SoundVolume:      preferences.SoundVolume,
+		ConfirmPurchases: preferences.ConfirmPurchases,
+		DebugOverlay:     preferences.DebugOverlay,
+		KeyBindings:      preferences.KeyBindings,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	if err := s.driver.SaveData(data); err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
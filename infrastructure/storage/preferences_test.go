@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreferencesStorage", func() {
+	var (
+		store       *driver.MemoryStore
+		preferences PreferencesStorage
+	)
+
+	BeforeEach(func() {
+		store = driver.NewMemoryStore()
+		preferences = NewPreferencesStorage(store.NewStorageDriver(config.PreferencesKey))
+	})
+
+	It("should return the defaults if nothing is stored yet", func() {
+		p, err := preferences.LoadPreferences()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p).To(Equal(model.DefaultPreferences()))
+	})
+
+	It("should load the saved preferences", func() {
+		saved := model.Preferences{
+			AutoSaveInterval: time.Minute,
+			NumberFormat:     model.NumberFormatScientific,
+			Theme:            "Light",
+			SoundVolume:      40,
+			ConfirmPurchases: true,
+			DebugOverlay:     true,
+			KeyBindings:      map[string][]string{"up": {"ArrowUp"}, "save": {"F5"}},
+		}
+		Expect(preferences.SavePreferences(saved)).To(Succeed())
+
+		p, err := preferences.LoadPreferences()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p).To(Equal(saved))
+	})
+
+	It("should keep the defaults for missing fields and replace invalid ones", func() {
+		store.Set(config.PreferencesKey, []byte(`{"version": 1, "number_format": "roman", "confirm_purchases": true}`))
+
+		p, err := preferences.LoadPreferences()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.AutoSaveInterval).To(Equal(config.DefaultAutoSaveInterval))
+		Expect(p.NumberFormat).To(Equal(model.NumberFormatShort))
+		Expect(p.SoundVolume).To(Equal(model.MaxSoundVolume))
+		Expect(p.ConfirmPurchases).To(BeTrue())
+	})
+
+	It("should return the defaults with an error for broken preferences", func() {
+		store.Set(config.PreferencesKey, []byte(`{"version": 1,`))
+
+		p, err := preferences.LoadPreferences()
+		Expect(err).To(HaveOccurred())
+		Expect(p).To(Equal(model.DefaultPreferences()))
+	})
+
+	It("should report a failed save", func() {
+		failing := driver.NewFaultDriver(store.NewStorageDriver(config.PreferencesKey))
+		failing.SaveError = errors.New("disk full")
+		Expect(NewPreferencesStorage(failing).SavePreferences(model.DefaultPreferences())).To(MatchError(ContainSubstring("disk full")))
+	})
+})
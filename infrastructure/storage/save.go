@@ -3,22 +3,21 @@ package storage
 import (
 	"fmt"
 
+	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/game/level"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 )
 
-type oldSave struct {
-	Money      float64   `json:"Money"`
-	Buildings  []int     `json:"Buildings"`
-	Upgradings []upgrade `json:"Upgradings"`
-	ManualWork int       `json:"ManualWork"`
-}
-
 type Save struct {
-	Money      float64   `json:"money"`
-	Buildings  []int     `json:"buildings"`
-	Upgradings []upgrade `json:"upgradings"`
-	ManualWork int       `json:"manual_work"`
+	Version        int            `json:"version"`
+	Money          float64        `json:"money"`
+	Buildings      map[string]int `json:"buildings"`       // Count of each building, keyed by Building.Key
+	BuildingLevels map[string]int `json:"building_levels"` // Tier of each building, keyed like Buildings
+	Upgradings     []upgrade      `json:"upgradings"`
+	ManualWork     int            `json:"manual_work"`
+	Coins          float64        `json:"coins"`
+	Market         *model.Market  `json:"market,omitempty"`
+	Tampered       bool           `json:"tampered"` // The save was edited outside the game at some point
 }
 
 type upgrade struct {
@@ -27,11 +26,13 @@ type upgrade struct {
 }
 
 func ConverToSave(gameState state.GameState) Save {
-	buildings := make([]int, len(gameState.GetBuildings()))
+	buildings := make(map[string]int, len(gameState.GetBuildings()))
+	buildingLevels := make(map[string]int, len(gameState.GetBuildings()))
 	upgradings := make([]upgrade, len(gameState.GetUpgrades()))
 
-	for i, b := range gameState.GetBuildings() {
-		buildings[i] = b.Count
+	for _, b := range gameState.GetBuildings() {
+		buildings[b.Key] = b.Count
+		buildingLevels[b.Key] = b.Level
 	}
 
 	for i, u := range gameState.GetUpgrades() {
@@ -39,39 +40,82 @@ func ConverToSave(gameState state.GameState) Save {
 		upgradings[i].IsPurchased = u.IsPurchased
 	}
 
+	var market *model.Market
+	if m := gameState.GetMarket(); m != nil {
+		copied := *m
+		copied.History = append([]float64(nil), m.History...)
+		market = &copied
+	}
+
 	return Save{
-		Money:      gameState.GetMoney(),
-		Buildings:  buildings,
-		Upgradings: upgradings,
-		ManualWork: gameState.GetManualWork().Count,
+		Version:        CurrentSaveVersion,
+		Money:          gameState.GetMoney(),
+		Buildings:      buildings,
+		BuildingLevels: buildingLevels,
+		Upgradings:     upgradings,
+		ManualWork:     gameState.GetManualWork().Count,
+		Coins:          gameState.GetCoins(),
+		Market:         market,
+		Tampered:       gameState.IsTampered(),
 	}
 }
 
 func (s *Save) ConvertToGameState() (state.GameState, error) {
 	gameState := state.NewGameState()
-	gameState.UpdateMoney(s.Money)
+	return gameState, s.ApplyToGameState(gameState)
+}
+
+// ApplyToGameState overwrites the given game state with the save.
+// Buildings and upgrades missing from the save are reset, and the ones the game
+// does not know are left out (see UnknownContent).
+func (s *Save) ApplyToGameState(gameState state.GameState) error {
+	gameState.UpdateMoney(s.Money - gameState.GetMoney())
+	gameState.UpdateCoins(s.Coins - gameState.GetCoins())
+	gameState.SetTampered(s.Tampered)
+	if s.Market != nil {
+		gameState.SetMarket(*s.Market)
+	}
 	if err := gameState.SetManualWorkCount(s.ManualWork); err != nil {
-		return gameState, err
+		return err
 	}
-	for i, b := range s.Buildings {
-		if err := gameState.SetBuildingCount(i, b); err != nil {
-			return gameState, err
+	for i, b := range gameState.GetBuildings() {
+		if err := gameState.SetBuildingCount(i, s.Buildings[b.Key]); err != nil {
+			return err
+		}
+		if err := gameState.SetBuildingLevel(i, s.BuildingLevels[b.Key]); err != nil {
+			return err
+		}
+	}
+	known := map[string]bool{}
+	for i, u := range gameState.GetUpgrades() {
+		known[u.ID] = true
+		if err := gameState.SetUpgradesIsPurchased(i, false); err != nil {
+			return err
 		}
 	}
 	for _, u := range s.Upgradings {
+		if !known[u.ID] {
+			continue
+		}
 		if err := gameState.SetUpgradesIsPurchasedWithID(u.ID, u.IsPurchased); err != nil {
-			return gameState, err
+			return err
 		}
 	}
-	return gameState, nil
+	return nil
 }
 
 func (s *Save) Validation() error {
 	if s.Money < 0 {
 		return fmt.Errorf("invalid money value: %f", s.Money)
 	}
-	if len(s.Buildings) > len(level.NewBuildings()) {
-		return fmt.Errorf("invalid buildings count: %d", len(s.Buildings))
+	// Unknown buildings are reported by UnknownContent instead of failing the save
+	for _, b := range level.NewBuildings() {
+		if c := s.Buildings[b.Key]; c < 0 {
+			return fmt.Errorf("invalid %s count: %d", b.Key, c)
+		}
+		if l := s.BuildingLevels[b.Key]; l < 0 || l > b.MaxLevel() {
+			return fmt.Errorf("invalid %s level: %d", b.Key, l)
+		}
 	}
 	if len(s.Upgradings) > len(level.NewUpgrades()) {
 		return fmt.Errorf("invalid upgradings count: %d", len(s.Upgradings))
@@ -79,5 +123,11 @@ func (s *Save) Validation() error {
 	if s.ManualWork < 0 {
 		return fmt.Errorf("invalid manual work count: %d", s.ManualWork)
 	}
+	if s.Coins < 0 {
+		return fmt.Errorf("invalid coins value: %f", s.Coins)
+	}
+	if s.Market != nil && s.Market.Price < 0 {
+		return fmt.Errorf("invalid market price: %f", s.Market.Price)
+	}
 	return nil
 }
@@ -12,10 +12,10 @@ var _ = Describe("Save", func() {
 	BeforeEach(func() {
 		save = Save{
 			Money:     100.0,
-			Buildings: []int{1, 2, 3},
+			Buildings: map[string]int{"cpu_miner": 1, "gpu_rig": 2, "asic_miner": 3},
 			Upgradings: []upgrade{
 				{
-					ID:          "0_0",
+					ID:          "cpu_miner_0",
 					IsPurchased: true,
 				},
 			},
@@ -33,16 +33,17 @@ var _ = Describe("Save", func() {
 			Expect(save.Validation()).To(HaveOccurred())
 		})
 
-		It("should return false if Buildings length is invalid", func() {
-			var buildings []int
-			for range level.NewBuildings() {
-				buildings = append(buildings, 0)
-			}
-			buildings = append(buildings, 0)
-			save.Buildings = buildings
+		It("should return false if a building count is negative", func() {
+			save.Buildings["gpu_rig"] = -1
 			Expect(save.Validation()).To(HaveOccurred())
 		})
 
+		It("should accept unknown buildings and report them instead", func() {
+			save.Buildings["removed_miner"] = 4
+			Expect(save.Validation()).ToNot(HaveOccurred())
+			Expect(save.UnknownContent()).To(Equal([]string{"building removed_miner"}))
+		})
+
 		It("should return false if Upgradings length is invalid", func() {
 			us := level.NewUpgrades()
 			var upgrades []upgrade
@@ -64,6 +65,16 @@ var _ = Describe("Save", func() {
 			save.ManualWork = -1
 			Expect(save.Validation()).To(HaveOccurred())
 		})
+
+		It("should return false if a building level has no tier", func() {
+			save.BuildingLevels = map[string]int{"gpu_rig": level.NewBuildings()[1].MaxLevel() + 1}
+			Expect(save.Validation()).To(HaveOccurred())
+		})
+
+		It("should return false if a building level is negative", func() {
+			save.BuildingLevels = map[string]int{"cpu_miner": -1}
+			Expect(save.Validation()).To(HaveOccurred())
+		})
 	})
 
 	Describe("ConvertToGameState", func() {
@@ -75,6 +86,15 @@ var _ = Describe("Save", func() {
 			// 他のフィールドも必要に応じて検証
 		})
 
+		It("should restore building levels", func() {
+			save.BuildingLevels = map[string]int{"cpu_miner": 2, "gpu_rig": 1}
+			gameState, err := save.ConvertToGameState()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gameState.GetBuildings()[0].Level).To(Equal(2))
+			Expect(gameState.GetBuildings()[1].Level).To(Equal(1))
+			Expect(gameState.GetBuildings()[2].Level).To(Equal(0))
+		})
+
 		It("should return an error if setting ManualWork fails", func() {
 			save.ManualWork = -1 // 無効な値を設定
 			_, err := save.ConvertToGameState()
@@ -82,23 +102,32 @@ var _ = Describe("Save", func() {
 		})
 
 		It("should return an error if setting Buildings fails", func() {
-			save.Buildings = []int{-1} // 無効な値を設定
+			save.Buildings = map[string]int{"cpu_miner": -1} // 無効な値を設定
 			_, err := save.ConvertToGameState()
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("should return an error if setting Upgradings fails", func() {
+		It("should apply buildings by their key", func() {
+			gameState, err := save.ConvertToGameState()
+			Expect(err).ToNot(HaveOccurred())
+			for _, b := range gameState.GetBuildings() {
+				Expect(b.Count).To(Equal(save.Buildings[b.Key]))
+			}
+		})
+
+		It("should leave out unknown buildings and upgrades", func() {
+			save.Buildings["removed_miner"] = 4
 			save.Upgradings = []upgrade{
 				{
-					ID:          "0_0",
+					ID:          "cpu_miner_0",
 					IsPurchased: true,
 				},
 				{
-					ID:          "0_1",
+					ID:          "cpu_miner_1",
 					IsPurchased: false,
 				},
 				{
-					ID:          "1_0",
+					ID:          "gpu_rig_0",
 					IsPurchased: true,
 				},
 				{
@@ -106,8 +135,17 @@ var _ = Describe("Save", func() {
 					IsPurchased: true,
 				},
 			}
-			_, err := save.ConvertToGameState()
-			Expect(err).To(HaveOccurred())
+			Expect(save.UnknownContent()).To(Equal([]string{"building removed_miner", "upgrade invalid_upgrade"}))
+
+			gameState, err := save.ConvertToGameState()
+			Expect(err).ToNot(HaveOccurred())
+			purchased := 0
+			for _, u := range gameState.GetUpgrades() {
+				if u.IsPurchased {
+					purchased++
+				}
+			}
+			Expect(purchased).To(Equal(2))
 		})
 	})
 })
@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+)
+
+var (
+	ErrInvalidSlotName = errors.New("invalid slot name")
+	ErrSlotExists      = errors.New("slot already exists")
+	ErrSlotNotFound    = errors.New("slot not found")
+)
+
+// SlotManager manages named save slots
+type SlotManager interface {
+	ListSlots() ([]string, error)
+	GetKeyName(slot string) string
+	CreateSlot(slot string) error
+	CopySlot(src, dst string) error
+	RenameSlot(src, dst string) error
+	DeleteSlot(slot string) error
+}
+
+type DefaultSlotManager struct {
+	keyManager   driver.KeyManager
+	newDriver    func(key string) driver.StorageDriver
+	deleteRemote func(key string) error // Deletes the save from the sync server, nil without one
+	dir          string
+}
+
+// NewSlotManager creates a slot manager for the saves stored in dir
+func NewSlotManager(keyManager driver.KeyManager, dir string) SlotManager {
+	return &DefaultSlotManager{
+		keyManager: keyManager,
+		newDriver:  driver.NewStorageDriver,
+		dir:        dir,
+	}
+}
+
+// NewSlotManagerWithSync creates a slot manager whose deleted and renamed slots are also deleted
+// from the sync server at endpoint
+func NewSlotManagerWithSync(keyManager driver.KeyManager, dir, endpoint, token string) SlotManager {
+	m := NewSlotManager(keyManager, dir).(*DefaultSlotManager)
+	m.deleteRemote = func(key string) error {
+		return driver.DeleteRemoteSave(key, endpoint, token)
+	}
+	return m
+}
+
+// ListSlots returns the existing slots sorted by name with the default slot first
+func (m *DefaultSlotManager) ListSlots() ([]string, error) {
+	keys, err := m.keyManager.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saves: %w", err)
+	}
+	slots := []string{}
+	for _, key := range keys {
+		if filepath.Dir(key) != filepath.Dir(m.GetKeyName(config.DefaultSlot)) {
+			continue
+		}
+		if slot, ok := config.SlotFromSaveKey(filepath.Base(key)); ok {
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if slots[i] == config.DefaultSlot || slots[j] == config.DefaultSlot {
+			return slots[i] == config.DefaultSlot
+		}
+		return slots[i] < slots[j]
+	})
+	return slots, nil
+}
+
+// GetKeyName returns the key the slot is stored under
+func (m *DefaultSlotManager) GetKeyName(slot string) string {
+	if m.dir == "" {
+		return config.SaveKeyForSlot(slot)
+	}
+	return filepath.Join(m.dir, config.SaveKeyForSlot(slot))
+}
+
+// CreateSlot creates a slot with a new game
+func (m *DefaultSlotManager) CreateSlot(slot string) error {
+	if err := m.checkNewSlot(slot); err != nil {
+		return err
+	}
+	return NewDefaultStorage(m.newDriver(m.GetKeyName(slot))).SaveGameState(state.NewGameState())
+}
+
+// CopySlot copies the save of src and its journal to the new slot dst
+func (m *DefaultSlotManager) CopySlot(src, dst string) error {
+	if err := m.copySave(src, dst); err != nil {
+		return err
+	}
+	// The journal holds the progress since the save was written
+	journal, err := m.newDriver(m.journalKey(src)).LoadData()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	return m.newDriver(m.journalKey(dst)).SaveData(journal)
+}
+
+// RenameSlot moves the save of src, its journal and its backups to the new slot dst.
+// The sync server only has src, which is deleted, and the game pushes dst when it first loads it.
+func (m *DefaultSlotManager) RenameSlot(src, dst string) error {
+	if err := m.copySave(src, dst); err != nil {
+		return err
+	}
+	if err := m.moveCompanions(src, dst); err != nil {
+		return err
+	}
+	return m.DeleteSlot(src)
+}
+
+// copySave copies the save of src to the new slot dst
+func (m *DefaultSlotManager) copySave(src, dst string) error {
+	if err := m.checkNewSlot(dst); err != nil {
+		return err
+	}
+	data, err := m.load(src)
+	if err != nil {
+		return err
+	}
+	return m.newDriver(m.GetKeyName(dst)).SaveData(data)
+}
+
+// DeleteSlot deletes the save of the slot, its journal and its backups, also from the sync server
+func (m *DefaultSlotManager) DeleteSlot(slot string) error {
+	exists, err := m.exists(slot)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrSlotNotFound, slot)
+	}
+	if err := m.keyManager.DeleteKey(m.GetKeyName(slot)); err != nil {
+		return err
+	}
+	if err := m.deleteCompanions(slot); err != nil {
+		return err
+	}
+	if m.deleteRemote != nil {
+		// The slot is gone here already, so it is only reported
+		if err := m.deleteRemote(m.GetKeyName(slot)); err != nil {
+			slog.Warn("Failed to delete save from the sync server", "slot", slot, "error", err)
+		}
+	}
+	return nil
+}
+
+// checkNewSlot checks that the slot can be created. A journal and backups left behind by an earlier
+// slot of the same name are deleted, so that the new slot cannot replay or restore them.
+func (m *DefaultSlotManager) checkNewSlot(slot string) error {
+	if !config.IsValidSlotName(slot) {
+		return fmt.Errorf("%w: %q", ErrInvalidSlotName, slot)
+	}
+	exists, err := m.exists(slot)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrSlotExists, slot)
+	}
+	return m.deleteCompanions(slot)
+}
+
+// journalKey returns the key of the journal of the slot, as JournalStorage names it
+func (m *DefaultSlotManager) journalKey(slot string) string {
+	return m.GetKeyName(slot) + ".journal"
+}
+
+// syncKey returns the key of the time the slot was last synced, as SyncDriver names it
+func (m *DefaultSlotManager) syncKey(slot string) string {
+	return m.GetKeyName(slot) + ".sync"
+}
+
+// companionKeys returns the keys stored along with the save of the slot: the journal, the time it was synced,
+// the rotating backups, the preserved broken save and the timestamped backups of older versions
+func (m *DefaultSlotManager) companionKeys(slot string) ([]string, error) {
+	keys, err := m.keyManager.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saves: %w", err)
+	}
+	prefix := m.GetKeyName(slot) + "."
+	companions := []string{}
+	for _, key := range keys {
+		if key == m.journalKey(slot) || key == m.syncKey(slot) || (strings.HasPrefix(key, prefix) && strings.HasSuffix(key, ".bak")) {
+			companions = append(companions, key)
+		}
+	}
+	return companions, nil
+}
+
+func (m *DefaultSlotManager) deleteCompanions(slot string) error {
+	keys, err := m.companionKeys(slot)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := m.keyManager.DeleteKey(key); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// moveCompanions moves the journal, the sync time and the backups of src to dst under the same names
+func (m *DefaultSlotManager) moveCompanions(src, dst string) error {
+	keys, err := m.companionKeys(src)
+	if err != nil {
+		return err
+	}
+	srcKey, dstKey := m.GetKeyName(src), m.GetKeyName(dst)
+	for _, key := range keys {
+		data, err := m.newDriver(key).LoadData()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		if err := m.newDriver(dstKey + strings.TrimPrefix(key, srcKey)).SaveData(data); err != nil {
+			return fmt.Errorf("failed to move %s: %w", key, err)
+		}
+		if err := m.keyManager.DeleteKey(key); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (m *DefaultSlotManager) exists(slot string) (bool, error) {
+	slots, err := m.ListSlots()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range slots {
+		if s == slot {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *DefaultSlotManager) load(slot string) ([]byte, error) {
+	exists, err := m.exists(slot)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrSlotNotFound, slot)
+	}
+	return m.newDriver(m.GetKeyName(slot)).LoadData()
+}
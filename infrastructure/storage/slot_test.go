@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SlotManager", func() {
+	var (
+		dir   string
+		slots SlotManager
+	)
+
+	saveMoney := func(slot string, money float64) {
+		gameState := state.NewGameState()
+		gameState.UpdateMoney(money)
+		Expect(NewDefaultStorage(driver.NewStorageDriver(slots.GetKeyName(slot))).SaveGameState(gameState)).To(Succeed())
+	}
+	loadMoney := func(slot string) float64 {
+		gameState, err := NewDefaultStorage(driver.NewStorageDriver(slots.GetKeyName(slot))).LoadGameState()
+		Expect(err).NotTo(HaveOccurred())
+		return gameState.GetMoney()
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		slots = NewSlotManager(driver.NewKeyManager(dir), dir)
+	})
+
+	It("should store the default slot in the default save key", func() {
+		Expect(slots.GetKeyName(config.DefaultSlot)).To(Equal(filepath.Join(dir, config.DefaultSaveKey)))
+		Expect(slots.GetKeyName("balance")).To(Equal(filepath.Join(dir, "game_state.balance.json")))
+	})
+
+	It("should list the slots with the default slot first", func() {
+		saveMoney("zeta", 1)
+		saveMoney(config.DefaultSlot, 1)
+		saveMoney("alpha", 1)
+		Expect(driver.NewStorageDriver(filepath.Join(dir, "game_state.json.20250101-000000.bak")).SaveData([]byte("{}"))).To(Succeed())
+
+		list, err := slots.ListSlots()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(Equal([]string{config.DefaultSlot, "alpha", "zeta"}))
+	})
+
+	It("should create a slot with a new game", func() {
+		Expect(slots.CreateSlot("balance")).To(Succeed())
+		Expect(loadMoney("balance")).To(Equal(0.0))
+		Expect(errors.Is(slots.CreateSlot("balance"), ErrSlotExists)).To(BeTrue())
+	})
+
+	It("should reject invalid slot names", func() {
+		Expect(errors.Is(slots.CreateSlot("../evil"), ErrInvalidSlotName)).To(BeTrue())
+		Expect(errors.Is(slots.CreateSlot(""), ErrInvalidSlotName)).To(BeTrue())
+	})
+
+	It("should copy a slot", func() {
+		saveMoney("real", 42)
+		Expect(slots.CopySlot("real", "balance")).To(Succeed())
+		Expect(loadMoney("balance")).To(Equal(42.0))
+		Expect(loadMoney("real")).To(Equal(42.0))
+	})
+
+	It("should rename a slot", func() {
+		saveMoney("real", 42)
+		Expect(slots.RenameSlot("real", "main")).To(Succeed())
+
+		list, err := slots.ListSlots()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(Equal([]string{"main"}))
+		Expect(loadMoney("main")).To(Equal(42.0))
+	})
+
+	It("should delete a slot", func() {
+		saveMoney("real", 42)
+		Expect(slots.DeleteSlot("real")).To(Succeed())
+
+		list, err := slots.ListSlots()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(BeEmpty())
+		Expect(errors.Is(slots.DeleteSlot("real"), ErrSlotNotFound)).To(BeTrue())
+	})
+
+	Context("with backups", func() {
+		backupKey := func(slot string, n int) string {
+			return fmt.Sprintf("%s.%d.bak", slots.GetKeyName(slot), n)
+		}
+		fileExists := func(key string) bool {
+			_, err := os.Stat(key)
+			return err == nil
+		}
+
+		BeforeEach(func() {
+			saveMoney("real", 1)
+			saveMoney("real", 42) // Each save is kept as the newest backup
+			Expect(fileExists(backupKey("real", 2))).To(BeTrue())
+		})
+
+		It("should delete the backups with the slot", func() {
+			Expect(slots.DeleteSlot("real")).To(Succeed())
+			Expect(fileExists(backupKey("real", 1))).To(BeFalse())
+			Expect(fileExists(backupKey("real", 2))).To(BeFalse())
+
+			// A new slot of the same name does not restore the old backups
+			Expect(slots.CreateSlot("real")).To(Succeed())
+			Expect(fileExists(backupKey("real", 2))).To(BeFalse())
+			restored, err := NewDefaultStorage(driver.NewStorageDriver(slots.GetKeyName("real"))).(*DefaultStorage).RestoreBackup()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.GetMoney()).To(Equal(0.0))
+		})
+
+		It("should move the backups with a renamed slot", func() {
+			Expect(slots.RenameSlot("real", "main")).To(Succeed())
+			Expect(fileExists(backupKey("real", 1))).To(BeFalse())
+			Expect(fileExists(backupKey("real", 2))).To(BeFalse())
+			Expect(fileExists(backupKey("main", 2))).To(BeTrue())
+
+			restored, err := NewDefaultStorage(driver.NewStorageDriver(slots.GetKeyName("main"))).(*DefaultStorage).RestoreBackup()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.GetMoney()).To(Equal(42.0))
+		})
+
+		It("should remove backups left behind by an earlier slot of the same name", func() {
+			Expect(driver.NewStorageDriver(backupKey("balance", 1)).SaveData([]byte("{}"))).To(Succeed())
+			Expect(slots.CopySlot("real", "balance")).To(Succeed())
+			Expect(fileExists(backupKey("balance", 1))).To(BeFalse())
+		})
+	})
+
+	Context("with a journal", func() {
+		journalKey := func(slot string) string {
+			return slots.GetKeyName(slot) + ".journal"
+		}
+		loadJournal := func(slot string) float64 {
+			gameState, err := NewJournalStorage(driver.NewStorageDriver(slots.GetKeyName(slot)), 3, 10).LoadGameState()
+			Expect(err).NotTo(HaveOccurred())
+			return gameState.GetMoney()
+		}
+
+		BeforeEach(func() {
+			journal := NewJournalStorage(driver.NewStorageDriver(slots.GetKeyName("real")), 3, 10)
+			gameState := state.NewGameState()
+			gameState.UpdateMoney(1)
+			Expect(journal.SaveGameState(gameState)).To(Succeed())
+			gameState.UpdateMoney(41) // Only in the journal
+			Expect(journal.SaveGameState(gameState)).To(Succeed())
+		})
+
+		It("should copy the journal with the slot", func() {
+			Expect(slots.CopySlot("real", "balance")).To(Succeed())
+			Expect(loadJournal("balance")).To(Equal(42.0))
+			Expect(loadJournal("real")).To(Equal(42.0))
+		})
+
+		It("should move the journal with a renamed slot", func() {
+			Expect(slots.RenameSlot("real", "main")).To(Succeed())
+			Expect(loadJournal("main")).To(Equal(42.0))
+			_, err := os.Stat(journalKey("real"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+
+		It("should delete the journal with the slot", func() {
+			Expect(slots.DeleteSlot("real")).To(Succeed())
+			_, err := os.Stat(journalKey("real"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Context("with sync", func() {
+		var deleted []string
+
+		syncKey := func(slot string) string {
+			return slots.GetKeyName(slot) + ".sync"
+		}
+
+		BeforeEach(func() {
+			deleted = nil
+			slots.(*DefaultSlotManager).deleteRemote = func(key string) error {
+				deleted = append(deleted, key)
+				return nil
+			}
+			saveMoney("real", 42)
+			Expect(driver.NewStorageDriver(syncKey("real")).SaveData([]byte("2025-01-01T00:00:00Z"))).To(Succeed())
+		})
+
+		It("should delete the sync time and the remote save with the slot", func() {
+			Expect(slots.DeleteSlot("real")).To(Succeed())
+			_, err := os.Stat(syncKey("real"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+			Expect(deleted).To(Equal([]string{slots.GetKeyName("real")}))
+		})
+
+		It("should move the sync time with a renamed slot and delete the old remote save", func() {
+			Expect(slots.RenameSlot("real", "main")).To(Succeed())
+			data, err := driver.NewStorageDriver(syncKey("main")).LoadData()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("2025-01-01T00:00:00Z"))
+			_, err = os.Stat(syncKey("real"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+			Expect(deleted).To(Equal([]string{slots.GetKeyName("real")}))
+		})
+
+		It("should not copy the sync time with a copied slot", func() {
+			Expect(slots.CopySlot("real", "balance")).To(Succeed())
+			_, err := os.Stat(syncKey("balance"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+			Expect(deleted).To(BeEmpty())
+		})
+
+		It("should delete the slot even if the sync server is unavailable", func() {
+			slots.(*DefaultSlotManager).deleteRemote = func(string) error {
+				return driver.ErrSyncUnavailable
+			}
+			Expect(slots.DeleteSlot("real")).To(Succeed())
+			list, err := slots.ListSlots()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(BeEmpty())
+		})
+	})
+
+	It("should fail to copy a missing slot", func() {
+		Expect(errors.Is(slots.CopySlot("missing", "copy"), ErrSlotNotFound)).To(BeTrue())
+	})
+})
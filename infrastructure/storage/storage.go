@@ -2,297 +2,427 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path/filepath"
-	"time"
+	"io/fs"
+	"log/slog"
 
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/game/level"
 	"github.com/kmdkuk/clicker/infrastructure/state"
 	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
 )
 
 type Storage interface {
 	SaveGameState(state state.GameState) error
+	// SaveFinalGameState saves the game state before the game ends and returns once the save is
+	// written everywhere it goes, e.g. pushed to the sync server
+	SaveFinalGameState(state state.GameState) error
 	LoadGameState() (state.GameState, error)
+	LastLoadReport() LoadReport
+	RestoreBackupInto(state state.GameState, n int) error
+	ExportGameState(state state.GameState) (string, error)
+	ImportGameState(state state.GameState, text string) error
 }
 
 type DefaultStorage struct {
 	storageDriver        driver.StorageDriver
+	newBackupDriver      func(key string) driver.StorageDriver
+	backupRetention      int
+	lastIntegrity        Integrity
 	haveOccuredLoadError bool
+	report               LoadReport
+	loadedChecksum       string // Checksum of the data read by the last LoadGameState
 }
 
-func NewDefaultStorage(driver driver.StorageDriver) Storage {
+func NewDefaultStorage(storageDriver driver.StorageDriver) Storage {
+	return NewDefaultStorageWithRetention(storageDriver, config.DefaultBackupRetention)
+}
+
+// NewDefaultStorageWithRetention creates a storage that keeps the given number of rotating backups
+func NewDefaultStorageWithRetention(storageDriver driver.StorageDriver, backupRetention int) Storage {
+	return NewDefaultStorageWithBackupDriver(storageDriver, backupRetention, driver.NewStorageDriver)
+}
+
+// NewDefaultStorageWithBackupDriver creates a storage that writes its backups and the broken save
+// through the drivers returned by newBackupDriver, e.g. to keep them in memory in tests
+func NewDefaultStorageWithBackupDriver(storageDriver driver.StorageDriver, backupRetention int, newBackupDriver func(key string) driver.StorageDriver) Storage {
 	return &DefaultStorage{
-		storageDriver: driver,
+		storageDriver:   storageDriver,
+		newBackupDriver: newBackupDriver,
+		backupRetention: backupRetention,
 	}
 }
 
-// SaveGameState encodes the game state to JSON and saves it
+// SaveGameState encodes the game state to JSON and saves it in an integrity envelope
 func (s *DefaultStorage) SaveGameState(state state.GameState) error {
-	// Convert to save format
-	save := ConverToSave(state)
-
-	// Marshal to JSON
-	data, err := json.Marshal(save)
+	data, err := encodeGameState(state)
 	if err != nil {
-		return fmt.Errorf("failed to marshal save data: %w", err)
+		return err
 	}
 
-	// Create a backup of the current save before writing new data
+	// Keep the save that could not be loaded cleanly before writing new data
 	if s.haveOccuredLoadError {
-		if err := s.createBackup(); err != nil {
+		if err := s.preserveBrokenSave(); err != nil {
 			// Log the error but continue with the save
-			fmt.Printf("Warning: Failed to create backup: %v\n", err)
+			slog.Warn("Failed to keep the broken save", "error", err)
 		}
 		s.haveOccuredLoadError = false
 	}
 
-	return s.storageDriver.SaveData(data)
+	if err := s.storageDriver.SaveData(data); err != nil {
+		return err
+	}
+	if err := s.rotateBackups(data); err != nil {
+		slog.Warn("Failed to rotate backups", "error", err)
+	}
+	return nil
+}
+
+// SaveFinalGameState saves the game state and waits until the driver has written it
+func (s *DefaultStorage) SaveFinalGameState(state state.GameState) error {
+	err := s.SaveGameState(state)
+	driver.Flush(s.storageDriver)
+	return err
 }
 
-// LoadGameState loads and decodes the game state, recovering partial data if possible
+// LoadGameState loads and decodes the game state, recovering partial data if possible.
+// A save edited outside the game still loads, but the game state is flagged as tampered.
+// What happened to the save is reported by LastLoadReport.
 func (s *DefaultStorage) LoadGameState() (state.GameState, error) {
 	s.haveOccuredLoadError = false
+	s.report = LoadReport{}
+	s.loadedChecksum = ""
+	gameState, err := s.loadGameState()
+	s.finishReport(gameState)
+	return gameState, err
+}
+
+func (s *DefaultStorage) loadGameState() (state.GameState, error) {
 	data, err := s.storageDriver.LoadData()
 	if err != nil {
-		s.haveOccuredLoadError = true
-		return &state.DefaultGameState{}, fmt.Errorf("failed to load data: %w", err)
+		s.reportProblem(err, false)
+		if errors.Is(err, fs.ErrNotExist) {
+			// 初回起動など、保存しておく壊れたセーブはない
+			s.haveOccuredLoadError = false
+		}
+		return s.restoreOrElse(func() (state.GameState, error) {
+			return &state.DefaultGameState{}, fmt.Errorf("failed to load data: %w", err)
+		})
 	}
+	s.loadedChecksum = checksum(data)
 
-	var oldSaveConverted Save
-	var oldSave oldSave
-	// Try to unmarshal old save format first
-	if err := json.Unmarshal(data, &oldSave); err != nil {
-		oldSave, err = s.recoverPartialOldSave(data)
-		if err != nil {
-			fmt.Printf("Error recovering partial save as old save: %v\n", err)
-		}
+	payload, integrity, err := unwrapEnvelope(data)
+	if err != nil {
+		// Neither restore nor overwrite a save written by a newer version
+		s.reportProblem(err, false)
+		return &state.DefaultGameState{}, err
+	}
+	s.lastIntegrity = integrity
+	switch integrity {
+	case IntegrityCorrupted:
+		slog.Warn("Save is corrupted, probably by a bad write", "key", s.storageDriver.GetKeyName())
+		s.reportProblem(ErrCorruptedSave, true)
+		return s.restoreOrElse(func() (state.GameState, error) {
+			return &state.DefaultGameState{}, ErrCorruptedSave
+		})
+	case IntegrityEdited:
+		slog.Warn("Save was edited outside the game, flagging it as tampered", "key", s.storageDriver.GetKeyName())
+	}
+
+	gameState, err := s.loadPayload(payload)
+	if err == nil && integrity == IntegrityEdited {
+		gameState.SetTampered(true)
+	}
+	return gameState, err
+}
+
+// reportProblem records why the save could not be loaded as it was saved.
+// A readable broken save is kept under corruptKey before the game overwrites it.
+func (s *DefaultStorage) reportProblem(err error, readable bool) {
+	s.haveOccuredLoadError = true
+	s.report.Problem = err
+	if readable {
+		s.report.BrokenSaveKey = s.corruptKey()
 	}
-	fmt.Printf("Marshaled old save: %+v\n", oldSave)
-	// Convert old save to new format
-	oldSaveConverted = Save{
-		Money:      oldSave.Money,
-		Buildings:  oldSave.Buildings,
-		Upgradings: make([]upgrade, len(oldSave.Upgradings)),
-		ManualWork: oldSave.ManualWork,
-	}
-	for i, u := range oldSave.Upgradings {
-		oldSaveConverted.Upgradings[i] = upgrade{
-			ID:          u.ID,
-			IsPurchased: u.IsPurchased,
-		}
+}
+
+// LastIntegrity returns the integrity of the save read by the last LoadGameState
+func (s *DefaultStorage) LastIntegrity() Integrity {
+	return s.lastIntegrity
+}
+
+// loadPayload decodes the serialized save, recovering partial data if possible
+func (s *DefaultStorage) loadPayload(data []byte) (state.GameState, error) {
+	data, version, err := migrateSave(data)
+	if errors.Is(err, ErrNewerSaveVersion) {
+		// Neither restore nor overwrite a save written by a newer version
+		s.reportProblem(err, false)
+		return &state.DefaultGameState{}, fmt.Errorf("failed to migrate save: %w", err)
+	}
+	if err != nil {
+		s.reportProblem(err, true)
+		return s.restoreOrElse(func() (state.GameState, error) {
+			return &state.DefaultGameState{}, fmt.Errorf("failed to migrate save: %w", err)
+		})
+	}
+	if version < CurrentSaveVersion {
+		slog.Info("Migrated save", "from", version, "to", CurrentSaveVersion)
 	}
 
 	var save Save
 	// Try standard unmarshaling first
 	if err := json.Unmarshal(data, &save); err != nil {
-		s.haveOccuredLoadError = true
-		fmt.Printf("failed to unmarshal: %v\n", err)
-		// If standard unmarshaling fails, try partial recovery
-		recoveredSave, recoverErr := s.recoverPartialSave(data)
-		if recoverErr != nil {
-			return &state.DefaultGameState{}, fmt.Errorf("cannot recover data: %w", recoverErr)
-		}
-		recoveredSave.merge(oldSaveConverted)
-		gameState, err := recoveredSave.ConvertToGameState()
-		if err != nil {
-			s.haveOccuredLoadError = true
-			return &state.DefaultGameState{}, fmt.Errorf("failed to convert recovered save: %w", err)
-		}
-		// Auto-save the fixed state
-		if err := s.SaveGameState(gameState); err != nil {
-			fmt.Printf("Warning: Failed to save fixed state: %v\n", err)
-		}
-		return gameState, nil
+		s.reportProblem(err, true)
+		slog.Warn("Failed to decode save", "error", err)
+		// If standard unmarshaling fails, restore the newest backup or try partial recovery
+		return s.restoreOrElse(func() (state.GameState, error) {
+			return s.recoverSave(data)
+		})
 	}
+	s.reportUnknownContent(save)
 
 	// Validate the save data
 	validationErr := save.Validation()
 	if validationErr == nil {
 		// Normal path - convert valid save to game state
-		save.merge(oldSaveConverted)
 		return save.ConvertToGameState()
 	}
+	s.reportProblem(validationErr, true)
+	slog.Warn("Save failed validation", "error", validationErr)
+	// If validation fails, restore the newest backup or try to fix what we can
+	return s.restoreOrElse(func() (state.GameState, error) {
+		return s.fixSave(save, validationErr)
+	})
+}
+
+// reportUnknownContent reports the content of the save that the game does not know.
+// The save loads without it, so the original is kept like a broken save before it is overwritten.
+func (s *DefaultStorage) reportUnknownContent(save Save) {
+	unknown := save.UnknownContent()
+	if len(unknown) == 0 {
+		return
+	}
+	slog.Warn("Save has content the game does not know, leaving it out", "content", unknown)
+	s.report.UnknownContent = unknown
 	s.haveOccuredLoadError = true
-	fmt.Printf("Validation error: %v\n", validationErr)
-	// If validation fails, try to recover what we can
-	fixedSave, fixErr := s.fixInvalidSave(save, validationErr)
+	s.report.BrokenSaveKey = s.corruptKey()
+}
+
+// recoverSave converts what can be recovered from corrupted JSON and saves it
+func (s *DefaultStorage) recoverSave(data []byte) (state.GameState, error) {
+	recoveredSave, recovered, recoverErr := recoverPartialSave(data)
+	if recoverErr != nil {
+		return &state.DefaultGameState{}, fmt.Errorf("cannot recover data: %w", recoverErr)
+	}
+	s.reportUnknownContent(recoveredSave)
+	gameState, err := recoveredSave.ConvertToGameState()
+	if err != nil {
+		s.haveOccuredLoadError = true
+		return &state.DefaultGameState{}, fmt.Errorf("failed to convert recovered save: %w", err)
+	}
+	s.report.Recovered = recovered
+	// Auto-save the fixed state
+	if err := s.SaveGameState(gameState); err != nil {
+		slog.Warn("Failed to save fixed state", "error", err)
+	}
+	return gameState, nil
+}
+
+// fixSave converts the save with its validation errors fixed and saves it
+func (s *DefaultStorage) fixSave(save Save, validationErr error) (state.GameState, error) {
+	fixedSave, fixErr := fixInvalidSave(save, validationErr)
 	if fixErr != nil {
 		s.haveOccuredLoadError = true
 		return &state.DefaultGameState{}, fmt.Errorf("failed to fix invalid save: %w", fixErr)
 	}
 
 	// Convert the fixed save to game state
-	fixedSave.merge(oldSaveConverted)
 	gameState, err := fixedSave.ConvertToGameState()
 	if err != nil {
 		s.haveOccuredLoadError = true
 		return &state.DefaultGameState{}, fmt.Errorf("failed to convert fixed save: %w", err)
 	}
+	s.report.Fixed = true
 
 	// Auto-save the fixed state
 	if err := s.SaveGameState(gameState); err != nil {
-		fmt.Printf("Warning: Failed to save fixed state: %v\n", err)
+		slog.Warn("Failed to save fixed state", "error", err)
 	}
 
 	return gameState, nil
 }
 
-// createBackup creates a backup of the current save file
-func (s *DefaultStorage) createBackup() error {
-	data, err := s.storageDriver.LoadData()
+// ExportGameState encodes the game state as a portable text string
+func (s *DefaultStorage) ExportGameState(state state.GameState) (string, error) {
+	return EncodeExport(ConverToSave(state))
+}
+
+// ImportGameState replaces the game state with an exported save string.
+// The string is validated and the current state is backed up before anything is replaced.
+func (s *DefaultStorage) ImportGameState(state state.GameState, text string) error {
+	save, err := DecodeExport(text)
+	if err != nil {
+		return fmt.Errorf("failed to decode save string: %w", err)
+	}
+	if unknown := save.UnknownContent(); len(unknown) > 0 {
+		slog.Warn("Imported save has content the game does not know, leaving it out", "content", unknown)
+	}
+	// Make sure the save applies cleanly before touching the current state
+	if _, err := save.ConvertToGameState(); err != nil {
+		return fmt.Errorf("failed to convert imported save: %w", err)
+	}
+
+	current, err := encodeGameState(state)
 	if err != nil {
 		return err
 	}
+	if err := s.rotateBackups(current); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
 
-	// Get base filename from driver
-	baseFilename := s.storageDriver.GetKeyName()
-	if baseFilename == "" {
-		baseFilename = "save.json"
+	if err := save.ApplyToGameState(state); err != nil {
+		return fmt.Errorf("failed to apply imported save: %w", err)
 	}
+	return s.SaveGameState(state)
+}
 
-	// Create backup filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	backupFilename := filepath.Join(
-		filepath.Dir(baseFilename),
-		fmt.Sprintf("%s.%s.bak",
-			filepath.Base(baseFilename),
-			timestamp,
-		),
-	)
+// encodeGameState serializes the game state wrapped in an integrity envelope
+func encodeGameState(state state.GameState) ([]byte, error) {
+	return EncodeSave(ConverToSave(state))
+}
 
-	// Create a backup driver
-	backupDriver := driver.NewStorageDriver(backupFilename)
-	return backupDriver.SaveData(data)
+// EncodeSave serializes the save wrapped in an integrity envelope, as SaveGameState writes it
+func EncodeSave(save Save) ([]byte, error) {
+	payload, err := json.Marshal(save)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal save data: %w", err)
+	}
+	data, err := wrapEnvelope(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal save envelope: %w", err)
+	}
+	return data, nil
 }
 
-// recoverPartialSave attempts to recover any valid parts from corrupted JSON
-func (s *DefaultStorage) recoverPartialSave(data []byte) (Save, error) {
+// preserveBrokenSave keeps a copy of the save that could not be loaded cleanly.
+// Only the latest one is kept so that repeated failures do not pile up files.
+func (s *DefaultStorage) preserveBrokenSave() error {
+	data, err := s.storageDriver.LoadData()
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return s.newBackupDriver(s.corruptKey()).SaveData(data)
+}
+
+// recoverPartialSave attempts to recover any valid parts from corrupted JSON.
+// The names of the recovered parts are returned, nil if nothing was recovered.
+func recoverPartialSave(data []byte) (Save, []string, error) {
 	// Create a default state to merge recovered data into
 	save := Save{}
+	var recovered []string
 	m := make(map[string]json.RawMessage)
 	if err := json.Unmarshal(data, &m); err != nil {
-		return save, fmt.Errorf("failed to unmarshal data for partial recovery: %w", err)
+		return save, nil, fmt.Errorf("failed to unmarshal data for partial recovery: %w", err)
 	}
 	// Try to extract money
 	var partialSave struct {
-		Money      *float64  `json:"money"`
-		Buildings  []int     `json:"buildings"`
-		Upgradings []upgrade `json:"upgradings"`
-		ManualWork int       `json:"manualWork"`
+		Money          *float64       `json:"money"`
+		Buildings      map[string]int `json:"buildings"`
+		BuildingLevels map[string]int `json:"building_levels"`
+		Upgradings     []upgrade      `json:"upgradings"`
+		ManualWork     int            `json:"manualWork"`
+		Coins          *float64       `json:"coins"`
+		Market         *model.Market  `json:"market"`
 		json.RawMessage
 	}
 	if err := unmarshalPartial(&partialSave.Money, m, "money"); err == nil && partialSave.Money != nil && *partialSave.Money > 0 {
 		save.Money = *partialSave.Money
-		fmt.Println("Partially recovered money from corrupted save: ", partialSave.Money)
+		recovered = append(recovered, "money")
+		slog.Debug("Partially recovered money", "money", *partialSave.Money)
 	}
 
 	if err := unmarshalPartial(&partialSave.Buildings, m, "buildings"); err == nil && partialSave.Buildings != nil {
-		// Process valid buildings
-		for i, building := range partialSave.Buildings {
-			if i < len(save.Buildings) {
-				// Only copy valid count values
-				if building >= 0 {
-					save.Buildings[i] = building
-					fmt.Println("Partially recovered buildings count from corrupted save [", i, "]: ", building)
-				}
-			}
-		}
-	}
-
-	// Try to extract upgrades
-	if err := unmarshalPartial(&partialSave.Upgradings, m, "upgradings"); err == nil && partialSave.Upgradings != nil {
-		// Process valid upgrades
-		for i := range partialSave.Upgradings {
-			for j := range save.Upgradings {
-				if partialSave.Upgradings[i].ID == save.Upgradings[j].ID {
-					save.Upgradings[j].IsPurchased = partialSave.Upgradings[i].IsPurchased
-					fmt.Println("Partially recovered upgradings from corrupted save [", save.Upgradings[j].ID, "]: ", save.Upgradings[j].IsPurchased)
-					break
-				}
+		save.Buildings = map[string]int{}
+		for key, building := range partialSave.Buildings {
+			// Only copy valid count values
+			if building >= 0 {
+				save.Buildings[key] = building
+				slog.Debug("Partially recovered building count", "building", key, "count", building)
 			}
 		}
-	}
-
-	// Try to extract manual work
-	if err := unmarshalPartial(&partialSave.ManualWork, m, "manualWork"); err == nil {
-		if partialSave.ManualWork >= 0 {
-			save.ManualWork = partialSave.ManualWork
-			fmt.Println("Partially recovered manual work from corrupted save: ", partialSave.ManualWork)
+		if len(save.Buildings) > 0 {
+			recovered = append(recovered, "buildings")
 		}
 	}
 
-	// Log recovery attempt
-	fmt.Println("Partially recovered game state from corrupted save")
-
-	return save, nil
-}
-
-func (s *DefaultStorage) recoverPartialOldSave(data []byte) (oldSave, error) {
-	// Create a default state to merge recovered data into
-	save := oldSave{}
-	m := make(map[string]json.RawMessage)
-	if err := json.Unmarshal(data, &m); err != nil {
-		return save, fmt.Errorf("failed to unmarshal data for partial recovery: %w", err)
-	}
-	// Try to extract money
-	var partialSave struct {
-		Money      *float64  `json:"Money"`
-		Buildings  []int     `json:"Buildings"`
-		Upgradings []upgrade `json:"Upgradings"`
-		ManualWork int       `json:"ManualWork"`
-		json.RawMessage
-	}
-	if err := unmarshalPartial(&partialSave.Money, m, "Money"); err == nil && partialSave.Money != nil && *partialSave.Money > 0 {
-		save.Money = *partialSave.Money
-		fmt.Println("Partially recovered money from corrupted save: ", partialSave.Money)
-	}
-
-	if err := unmarshalPartial(&partialSave.Buildings, m, "Buildings"); err == nil && partialSave.Buildings != nil {
-		// Process valid buildings
-		save.Buildings = make([]int, len(partialSave.Buildings))
-		for i, building := range partialSave.Buildings {
-			if i < len(save.Buildings) {
-				// Only copy valid count values
-				if building >= 0 {
-					save.Buildings[i] = building
-					fmt.Println("Partially recovered buildings count from corrupted save [", i, "]: ", building)
-				}
+	// Try to extract building levels
+	if err := unmarshalPartial(&partialSave.BuildingLevels, m, "building_levels"); err == nil && partialSave.BuildingLevels != nil {
+		recovered = append(recovered, "building levels")
+		save.BuildingLevels = map[string]int{}
+		for key, l := range partialSave.BuildingLevels {
+			if l < 0 {
+				l = 0
 			}
+			save.BuildingLevels[key] = l
+			slog.Debug("Partially recovered building level", "building", key, "level", l)
 		}
 	}
 
 	// Try to extract upgrades
-	if err := unmarshalPartial(&partialSave.Upgradings, m, "Upgradings"); err == nil && partialSave.Upgradings != nil {
+	if err := unmarshalPartial(&partialSave.Upgradings, m, "upgradings"); err == nil && partialSave.Upgradings != nil {
 		// Process valid upgrades
+		copied := false
 		for i := range partialSave.Upgradings {
 			for j := range save.Upgradings {
 				if partialSave.Upgradings[i].ID == save.Upgradings[j].ID {
 					save.Upgradings[j].IsPurchased = partialSave.Upgradings[i].IsPurchased
-					fmt.Println("Partially recovered upgradings from corrupted save [", save.Upgradings[j].ID, "]: ", save.Upgradings[j].IsPurchased)
+					copied = true
+					slog.Debug("Partially recovered upgrade", "upgrade", save.Upgradings[j].ID, "purchased", save.Upgradings[j].IsPurchased)
 					break
 				}
 			}
 		}
+		if copied {
+			recovered = append(recovered, "upgrades")
+		}
 	}
 
 	// Try to extract manual work
-	if err := unmarshalPartial(&partialSave.ManualWork, m, "ManualWork"); err == nil {
+	if err := unmarshalPartial(&partialSave.ManualWork, m, "manualWork"); err == nil {
 		if partialSave.ManualWork >= 0 {
 			save.ManualWork = partialSave.ManualWork
-			fmt.Println("Partially recovered manual work from corrupted save: ", partialSave.ManualWork)
+			recovered = append(recovered, "manual work")
+			slog.Debug("Partially recovered manual work", "count", partialSave.ManualWork)
 		}
 	}
 
+	// Try to extract coins and market
+	if err := unmarshalPartial(&partialSave.Coins, m, "coins"); err == nil && partialSave.Coins != nil && *partialSave.Coins > 0 {
+		save.Coins = *partialSave.Coins
+		recovered = append(recovered, "coins")
+		slog.Debug("Partially recovered coins", "coins", *partialSave.Coins)
+	}
+	if err := unmarshalPartial(&partialSave.Market, m, "market"); err == nil && partialSave.Market != nil && partialSave.Market.Price > 0 {
+		save.Market = partialSave.Market
+		recovered = append(recovered, "market")
+		slog.Debug("Partially recovered market", "price", partialSave.Market.Price)
+	}
+
 	// Log recovery attempt
-	fmt.Println("Partially recovered game state from corrupted save")
+	slog.Info("Partially recovered game state from corrupted save", "recovered", recovered)
 
-	return save, nil
+	return save, recovered, nil
 }
 
 // fixInvalidSave attempts to fix validation errors in the save data
-func (s *DefaultStorage) fixInvalidSave(save Save, validationErr error) (Save, error) {
+func fixInvalidSave(save Save, validationErr error) (Save, error) {
 	// Log the validation error
-	fmt.Printf("Fixing invalid save: %v\n", validationErr)
+	slog.Info("Fixing invalid save", "error", validationErr)
 
 	// Create default save to fill in missing pieces
 	defaultSave := ConverToSave(&state.DefaultGameState{})
@@ -306,14 +436,27 @@ func (s *DefaultStorage) fixInvalidSave(save Save, validationErr error) (Save, e
 	if save.Buildings == nil {
 		save.Buildings = defaultSave.Buildings
 	} else {
-		for i, building := range save.Buildings {
+		for key, building := range save.Buildings {
 			// Fix negative counts
 			if building < 0 {
-				save.Buildings[i] = 0
+				save.Buildings[key] = 0
 			}
 		}
 	}
 
+	// Fix building levels. Unknown buildings are left for UnknownContent to report
+	for _, b := range level.NewBuildings() {
+		l, ok := save.BuildingLevels[b.Key]
+		if !ok {
+			continue
+		}
+		if l < 0 {
+			save.BuildingLevels[b.Key] = 0
+		} else if l > b.MaxLevel() {
+			save.BuildingLevels[b.Key] = b.MaxLevel()
+		}
+	}
+
 	// Fix upgrades
 	if save.Upgradings == nil {
 		save.Upgradings = defaultSave.Upgradings
@@ -330,10 +473,18 @@ func (s *DefaultStorage) fixInvalidSave(save Save, validationErr error) (Save, e
 		save.ManualWork = defaultSave.ManualWork
 	}
 
+	// Fix coins and drop a broken market so that a fresh one is simulated
+	if save.Coins < 0 {
+		save.Coins = 0
+	}
+	if save.Market != nil && save.Market.Price < 0 {
+		save.Market = nil
+	}
+
 	// Validate the fixed save
 	if err := save.Validation(); err != nil {
 		// If we still have validation errors, log them but continue with what we have
-		fmt.Printf("Warning: Still have validation errors after fixing: %v\n", err)
+		slog.Warn("Save still fails validation after fixing", "error", err)
 	}
 
 	return save, nil
@@ -346,28 +497,3 @@ func unmarshalPartial(to interface{}, m map[string]json.RawMessage, s string) er
 	delete(m, s)
 	return nil
 }
-
-func (s *Save) merge(other Save) {
-	if s.Money < other.Money {
-		s.Money = other.Money
-	}
-	if s.ManualWork < other.ManualWork {
-		s.ManualWork = other.ManualWork
-	}
-	s.Buildings = append(s.Buildings, make([]int, len(other.Buildings)-len(s.Buildings))...)
-	for i, b := range s.Buildings {
-		if i < len(other.Buildings) && other.Buildings[i] > b {
-			s.Buildings[i] = other.Buildings[i]
-		}
-	}
-	s.Upgradings = append(s.Upgradings, make([]upgrade, len(other.Upgradings)-len(s.Upgradings))...)
-	for i := range s.Upgradings {
-		for j := range other.Upgradings {
-			if s.Upgradings[i].ID == other.Upgradings[j].ID {
-				if other.Upgradings[j].IsPurchased {
-					s.Upgradings[i].IsPurchased = other.Upgradings[j].IsPurchased
-				}
-			}
-		}
-	}
-}
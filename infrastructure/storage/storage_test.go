@@ -3,41 +3,26 @@ package storage
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/kmdkuk/clicker/config"
 	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/game/level"
+	"github.com/kmdkuk/clicker/infrastructure/state"
+	"github.com/kmdkuk/clicker/infrastructure/storage/driver"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
-// Mock implementation of StorageDriver
-type MockStorageDriver struct {
-	SaveDataCalled bool
-	LoadDataCalled bool
-	Data           []byte
-	SaveError      error
-	LoadError      error
-	Filename       string
-}
-
-func (m *MockStorageDriver) SaveData(data []byte) error {
-	m.SaveDataCalled = true
-	m.Data = data
-	return m.SaveError
-}
-
-func (m *MockStorageDriver) LoadData() ([]byte, error) {
-	m.LoadDataCalled = true
-	return m.Data, m.LoadError
-}
+const testSaveKey = "test_save.json"
 
-func (m *MockStorageDriver) GetKeyName() string {
-	return m.Filename
+// newTestStorage returns a storage keeping the save and its backups in a memory store.
+// The save driver is wrapped to inject faults.
+func newTestStorage(backupRetention int) (*DefaultStorage, *driver.FaultDriver, *driver.MemoryStore) {
+	store := driver.NewMemoryStore()
+	saveDriver := driver.NewFaultDriver(store.NewStorageDriver(testSaveKey))
+	return NewDefaultStorageWithBackupDriver(saveDriver, backupRetention, store.NewStorageDriver).(*DefaultStorage), saveDriver, store
 }
 
 // Mock implementation of GameState
@@ -46,6 +31,9 @@ type MockGameState struct {
 	Buildings  []model.Building
 	Upgrades   []model.Upgrade
 	ManualWork model.ManualWork
+	Coins      float64
+	Market     model.Market
+	Tampered   bool
 }
 
 func (m *MockGameState) GetMoney() float64 {
@@ -76,6 +64,14 @@ func (m *MockGameState) SetBuildingCount(index int, count int) error {
 	return nil
 }
 
+func (m *MockGameState) SetBuildingLevel(index int, level int) error {
+	if index < 0 || index >= len(m.Buildings) {
+		return errors.New("invalid building index")
+	}
+	m.Buildings[index].Level = level
+	return nil
+}
+
 func (m *MockGameState) SetUpgrades(upgrades []model.Upgrade) {
 	m.Upgrades = upgrades
 }
@@ -112,6 +108,43 @@ func (m *MockGameState) GetTotalGenerateRate() float64 {
 	return 0.0
 }
 
+func (m *MockGameState) GetCoins() float64 {
+	return m.Coins
+}
+
+func (m *MockGameState) UpdateCoins(amount float64) {
+	m.Coins += amount
+}
+
+func (m *MockGameState) GetMarket() *model.Market {
+	return &m.Market
+}
+
+func (m *MockGameState) SetMarket(market model.Market) {
+	m.Market = market
+}
+
+func (m *MockGameState) GetTotalUpkeep() float64 {
+	return 0.0
+}
+
+func (m *MockGameState) GetThrottle() float64 {
+	return 1.0
+}
+
+func (m *MockGameState) IsTampered() bool {
+	return m.Tampered
+}
+
+func (m *MockGameState) SetTampered(tampered bool) {
+	m.Tampered = tampered
+}
+
+func (m *MockGameState) Clone() state.GameState {
+	clone := *m
+	return &clone
+}
+
 func (m *MockGameState) GetBuildingCount(index int) (int, error) {
 	if index < 0 || index >= len(m.Buildings) {
 		return 0, errors.New("invalid building index")
@@ -121,21 +154,19 @@ func (m *MockGameState) GetBuildingCount(index int) (int, error) {
 
 var _ = Describe("DefaultStorage", func() {
 	var (
-		mockDriver  *MockStorageDriver
+		mockDriver  *driver.FaultDriver
+		store       *driver.MemoryStore
 		testStorage Storage
 		testState   *MockGameState
 	)
 
 	BeforeEach(func() {
-		mockDriver = &MockStorageDriver{
-			Filename: "test_save.json",
-		}
-		testStorage = NewDefaultStorage(mockDriver)
+		testStorage, mockDriver, store = newTestStorage(config.DefaultBackupRetention)
 		testState = &MockGameState{
 			Money: 100.0,
 			Buildings: []model.Building{
-				{ID: 0, Name: "Building 1", Count: 5, BaseCost: 10},
-				{ID: 1, Name: "Building 2", Count: 3, BaseCost: 50},
+				{ID: 0, Key: "building_1", Name: "Building 1", Count: 5, BaseCost: 10, Level: 1},
+				{ID: 1, Key: "building_2", Name: "Building 2", Count: 3, BaseCost: 50},
 			},
 			Upgrades: []model.Upgrade{
 				{Name: "Upgrade 1", IsPurchased: true, Cost: 20},
@@ -144,23 +175,8 @@ var _ = Describe("DefaultStorage", func() {
 			ManualWork: model.ManualWork{
 				Count: 10,
 			},
-		}
-	})
-
-	AfterEach(func() {
-		testDir := filepath.Dir(mockDriver.Filename)
-		baseFilename := filepath.Base(mockDriver.Filename)
-
-		if _, err := os.Stat(testDir); err == nil {
-			files, err := filepath.Glob(filepath.Join(testDir, baseFilename+".*.bak"))
-			if err == nil {
-				for _, file := range files {
-					err := os.Remove(file)
-					if err != nil {
-						fmt.Println("Error removing backup file:", err)
-					}
-				}
-			}
+			Coins:  2.5,
+			Market: model.NewMarket(1),
 		}
 	})
 
@@ -169,20 +185,22 @@ var _ = Describe("DefaultStorage", func() {
 			err := testStorage.SaveGameState(testState)
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(mockDriver.SaveDataCalled).To(BeTrue())
-			Expect(mockDriver.Data).NotTo(BeNil())
+			Expect(mockDriver.Saves).To(Equal(1))
+			Expect(store.Get(testSaveKey)).NotTo(BeNil())
 
 			// Verify the saved data contains the expected values
-			var save Save
-			err = json.Unmarshal(mockDriver.Data, &save)
-			Expect(err).NotTo(HaveOccurred())
+			save := decodeSaved(store.Get(testSaveKey))
 
 			Expect(save.Money).To(Equal(100.0))
 			Expect(save.Buildings).To(HaveLen(2))
-			Expect(save.Buildings[0]).To(Equal(5))
+			Expect(save.Buildings["building_1"]).To(Equal(5))
+			Expect(save.BuildingLevels).To(Equal(map[string]int{"building_1": 1, "building_2": 0}))
 			Expect(save.Upgradings).To(HaveLen(2))
 			Expect(save.Upgradings[0].IsPurchased).To(BeTrue())
 			Expect(save.ManualWork).To(Equal(10))
+			Expect(save.Coins).To(Equal(2.5))
+			Expect(save.Market).NotTo(BeNil())
+			Expect(save.Market.Price).To(Equal(model.CoinBasePrice))
 		})
 
 		It("should handle errors from SaveData", func() {
@@ -201,14 +219,14 @@ var _ = Describe("DefaultStorage", func() {
 				// Create valid save data
 				validSave := Save{
 					Money:     250.0,
-					Buildings: []int{7, 2},
+					Buildings: map[string]int{"cpu_miner": 7, "gpu_rig": 2},
 					Upgradings: []upgrade{
 						{
-							ID:          "0_0",
+							ID:          "cpu_miner_0",
 							IsPurchased: true,
 						},
 						{
-							ID:          "0_1",
+							ID:          "cpu_miner_1",
 							IsPurchased: false,
 						},
 					},
@@ -217,14 +235,14 @@ var _ = Describe("DefaultStorage", func() {
 
 				data, err := json.Marshal(validSave)
 				Expect(err).NotTo(HaveOccurred())
-				mockDriver.Data = data
+				store.Set(testSaveKey, data)
 			})
 
 			It("should load and convert save data to game state", func() {
 				gameState, err := testStorage.LoadGameState()
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(mockDriver.LoadDataCalled).To(BeTrue())
+				Expect(mockDriver.Loads).To(BeNumerically(">", 0))
 
 				Expect(gameState.GetMoney()).To(Equal(250.0))
 				Expect(gameState.GetBuildings()[0].Count).To(Equal(7))
@@ -235,10 +253,34 @@ var _ = Describe("DefaultStorage", func() {
 			})
 		})
 
+		Context("with market data", func() {
+			BeforeEach(func() {
+				market := model.NewMarket(7)
+				market.Advance(10)
+				data, err := json.Marshal(Save{
+					Money:     1.0,
+					Buildings: map[string]int{"cpu_miner": 1},
+					Coins:     3.5,
+					Market:    &market,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				store.Set(testSaveKey, data)
+			})
+
+			It("should restore coins and the price history", func() {
+				gameState, err := testStorage.LoadGameState()
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gameState.GetCoins()).To(Equal(3.5))
+				Expect(gameState.GetMarket().Ticks).To(Equal(10))
+				Expect(gameState.GetMarket().History).To(HaveLen(11))
+			})
+		})
+
 		Context("with corrupted JSON data", func() {
 			BeforeEach(func() {
 				// Create corrupted JSON data
-				mockDriver.Data = []byte(`{"money": 100.0, "buildings": [{"id": 0, "count": 5}, {"id": 1, "count": 3}], "upgradings": [{"id": 0, "isPurchased": false}], "manualWork": -10, "corrupted": true}`)
+				store.Set(testSaveKey, []byte(`{"money": 100.0, "buildings": [{"id": 0, "count": 5}, {"id": 1, "count": 3}], "upgradings": [{"id": 0, "isPurchased": false}], "manualWork": -10, "corrupted": true}`))
 			})
 
 			It("should attempt to recover partial state", func() {
@@ -246,7 +288,7 @@ var _ = Describe("DefaultStorage", func() {
 
 				// Even with errors, we should get a usable game state
 				Expect(err).NotTo(HaveOccurred())
-				Expect(mockDriver.LoadDataCalled).To(BeTrue())
+				Expect(mockDriver.Loads).To(BeNumerically(">", 0))
 
 				// Should recover the money value
 				Expect(gameState.GetMoney()).To(Equal(100.0))
@@ -258,22 +300,22 @@ var _ = Describe("DefaultStorage", func() {
 				// Create save data with validation errors
 				invalidSave := Save{
 					Money: -50.0, // Negative money
-					Buildings: []int{
-						-2, // Negative count
+					Buildings: map[string]int{
+						"cpu_miner": -2, // Negative count
 					},
 					ManualWork: -5, // Negative manual work{
 				}
 
 				data, err := json.Marshal(invalidSave)
 				Expect(err).NotTo(HaveOccurred())
-				mockDriver.Data = data
+				store.Set(testSaveKey, data)
 			})
 
 			It("should fix invalid values and return usable game state", func() {
 				gameState, err := testStorage.LoadGameState()
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(mockDriver.LoadDataCalled).To(BeTrue())
+				Expect(mockDriver.Loads).To(BeNumerically(">", 0))
 
 				// Money should be fixed to non-negative value
 				Expect(gameState.GetMoney()).To(BeNumerically(">=", 0))
@@ -289,6 +331,26 @@ var _ = Describe("DefaultStorage", func() {
 			})
 		})
 
+		Context("with building levels out of range", func() {
+			BeforeEach(func() {
+				data, err := json.Marshal(Save{
+					Buildings:      map[string]int{"cpu_miner": 3, "gpu_rig": 1},
+					BuildingLevels: map[string]int{"cpu_miner": 99, "gpu_rig": -1},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				store.Set(testSaveKey, data)
+			})
+
+			It("should clamp the levels to existing tiers", func() {
+				gameState, err := testStorage.LoadGameState()
+
+				Expect(err).NotTo(HaveOccurred())
+				buildings := gameState.GetBuildings()
+				Expect(buildings[0].Level).To(Equal(buildings[0].MaxLevel()))
+				Expect(buildings[1].Level).To(Equal(0))
+			})
+		})
+
 		Context("when LoadData fails", func() {
 			BeforeEach(func() {
 				mockDriver.LoadError = errors.New("load error")
@@ -306,6 +368,47 @@ var _ = Describe("DefaultStorage", func() {
 		})
 	})
 
+	Describe("ExportGameState and ImportGameState", func() {
+		It("should import an exported state over the current one", func() {
+			testState.Upgrades[0].ID = "0_0"
+			testState.Upgrades[1].ID = "0_1"
+			text, err := testStorage.ExportGameState(testState)
+			Expect(err).NotTo(HaveOccurred())
+
+			target := &MockGameState{
+				Money: 1.0,
+				Buildings: []model.Building{
+					{ID: 0, Key: "building_1", Name: "Building 1", Count: 99},
+					{ID: 1, Key: "building_2", Name: "Building 2", Count: 99, Level: 2},
+				},
+				Upgrades: []model.Upgrade{
+					{ID: "0_0", Name: "Upgrade 1"},
+					{ID: "0_1", Name: "Upgrade 2"},
+					{ID: "1_0", Name: "Upgrade 3", IsPurchased: true},
+				},
+			}
+
+			Expect(testStorage.ImportGameState(target, text)).To(Succeed())
+			Expect(target.Money).To(Equal(100.0))
+			Expect(target.Buildings[0].Count).To(Equal(5))
+			Expect(target.Buildings[1].Level).To(Equal(0))
+			Expect(target.Upgrades[0].IsPurchased).To(BeTrue())
+			Expect(target.Upgrades[2].IsPurchased).To(BeFalse())
+			Expect(target.ManualWork.Count).To(Equal(10))
+			Expect(target.Coins).To(Equal(2.5))
+			Expect(mockDriver.Saves).To(Equal(1))
+			Expect(store.Get(testStorage.(*DefaultStorage).backupKey(2))).NotTo(BeNil())
+		})
+
+		It("should not touch the state if the string is invalid", func() {
+			err := testStorage.ImportGameState(testState, "clicker:broken")
+
+			Expect(err).To(HaveOccurred())
+			Expect(testState.Money).To(Equal(100.0))
+			Expect(mockDriver.Saves).To(BeZero())
+		})
+	})
+
 	Describe("Recovery and backup functions", func() {
 		// These are mostly tested through the LoadGameState and SaveGameState tests,
 		// but we can add specific tests for edge cases
@@ -314,7 +417,7 @@ var _ = Describe("DefaultStorage", func() {
 			It("should extract valid fields from partially corrupted JSON", func() {
 				// This requires exposing recoverPartialState or testing through LoadGameState
 				data := []byte(`{"money": 123.45, "buildings": [{"id": 0, "count": 7}], "manualWork": 99, "corrupted": true}`)
-				mockDriver.Data = data
+				store.Set(testSaveKey, data)
 
 				gameState, err := testStorage.LoadGameState()
 
@@ -328,13 +431,13 @@ var _ = Describe("DefaultStorage", func() {
 			It("should replace invalid values with defaults", func() {
 				invalidSave := Save{
 					Money: -100.0,
-					Buildings: []int{
-						-5,
+					Buildings: map[string]int{
+						"cpu_miner": -5,
 					},
 				}
 
 				data, _ := json.Marshal(invalidSave)
-				mockDriver.Data = data
+				store.Set(testSaveKey, data)
 
 				gameState, err := testStorage.LoadGameState()
 
@@ -0,0 +1,109 @@
+package components
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	ChartTitleHeight = ItemHeight // タイトル行の高さ
+	ChartLineWidth   = 2          // 折れ線の太さ
+)
+
+// Chart draws a titled line chart, e.g. the coin price history
+type Chart struct {
+	style   *Style
+	Visible bool
+	x       int
+	y       int
+	width   int // 0 extends the chart to the right edge of the screen
+	height  int
+}
+
+func NewChart(style *Style, defaultVisible bool, x, y, height int) *Chart {
+	return &Chart{
+		style:   style,
+		Visible: defaultVisible,
+		x:       x,
+		y:       y,
+		height:  height,
+	}
+}
+
+// SetBounds moves and resizes the chart, e.g. when the window is resized
+func (c *Chart) SetBounds(x, y, width, height int) {
+	c.x = x
+	c.y = y
+	c.width = width
+	c.height = height
+}
+
+func (c *Chart) calcWidth(screenWidth int) float32 {
+	areaWidth := screenWidth - c.x
+	if c.width > 0 {
+		areaWidth = c.width
+	}
+	return float32(areaWidth - c.style.Px(ScrollbarWidth) - c.style.Px(ScrollbarMargin)*2)
+}
+
+func (c *Chart) Draw(screen *ebiten.Image, title string, values []float64) {
+	if !c.Visible {
+		return
+	}
+
+	width := c.calcWidth(screen.Bounds().Dx())
+
+	// 背景矩形を描画
+	vector.FillRect(screen, float32(c.x), float32(c.y), width, float32(c.height), c.style.Colors.NormalBg, false)
+
+	// タイトル描画
+	face := c.style.Face(c.style.TextSizes.Text)
+	txtOp := &text.DrawOptions{}
+	txtOp.PrimaryAlign = text.AlignStart
+	txtOp.SecondaryAlign = text.AlignCenter
+	txtOp.GeoM.Translate(float64(c.x+c.style.Px(c.style.Padding.ItemText)), float64(c.y+c.style.Px(ChartTitleHeight)/2))
+	txtOp.ColorScale.ScaleWithColor(c.style.Colors.NormalText)
+	text.Draw(screen, title, face, txtOp)
+
+	points := c.plot(values, width)
+	for i := 1; i < len(points); i++ {
+		vector.StrokeLine(screen, points[i-1][0], points[i-1][1], points[i][0], points[i][1], float32(c.style.Px(ChartLineWidth)), c.style.Colors.ChartLine, true)
+	}
+}
+
+// plot converts the values into screen coordinates inside the plot area below the title
+func (c *Chart) plot(values []float64, width float32) [][2]float32 {
+	if len(values) < 2 {
+		return nil
+	}
+
+	minValue, maxValue := values[0], values[0]
+	for _, v := range values {
+		if v < minValue {
+			minValue = v
+		}
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+	valueRange := maxValue - minValue
+	if valueRange == 0 {
+		valueRange = 1 // 値が一定の場合は中央に描画する
+		minValue -= 0.5
+	}
+
+	padding := float32(c.style.Px(c.style.Padding.ItemText))
+	left := float32(c.x) + padding
+	right := float32(c.x) + width - padding
+	top := float32(c.y + c.style.Px(ChartTitleHeight))
+	bottom := float32(c.y+c.height) - padding
+	stepX := (right - left) / float32(len(values)-1)
+
+	points := make([][2]float32, len(values))
+	for i, v := range values {
+		ratio := float32((v - minValue) / valueRange)
+		points[i] = [2]float32{left + stepX*float32(i), bottom - ratio*(bottom-top)}
+	}
+	return points
+}
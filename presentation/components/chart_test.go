@@ -0,0 +1,57 @@
+package components
+
+import (
+	"github.com/kmdkuk/clicker/presentation/theme"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chart", func() {
+	var (
+		chart      *Chart
+		mockScreen *ebiten.Image
+	)
+	style, err := NewStyle(theme.Dark())
+	Expect(err).NotTo(HaveOccurred())
+
+	BeforeEach(func() {
+		chart = NewChart(style, true, 10, 100, 200)
+		mockScreen = ebiten.NewImage(640, 480)
+	})
+
+	Describe("Draw", func() {
+		It("should not panic when drawing values", func() {
+			Expect(func() {
+				chart.Draw(mockScreen, "Price", []float64{1, 2, 1.5})
+			}).NotTo(Panic())
+		})
+
+		It("should not panic without values", func() {
+			Expect(func() {
+				chart.Draw(mockScreen, "Price", nil)
+			}).NotTo(Panic())
+		})
+	})
+
+	Describe("plot", func() {
+		It("should not plot less than two values", func() {
+			Expect(chart.plot([]float64{1}, 500)).To(BeEmpty())
+		})
+
+		It("should map the lowest and highest value to the plot area edges", func() {
+			points := chart.plot([]float64{1, 3, 2}, 500)
+			Expect(points).To(HaveLen(3))
+			Expect(points[0][0]).To(BeNumerically("==", 10+style.Padding.ItemText))
+			Expect(points[2][0]).To(BeNumerically("==", 10+500-style.Padding.ItemText))
+			Expect(points[0][1]).To(BeNumerically("==", 100+200-style.Padding.ItemText))
+			Expect(points[1][1]).To(BeNumerically("==", 100+ChartTitleHeight))
+		})
+
+		It("should draw constant values in the middle", func() {
+			points := chart.plot([]float64{2, 2}, 500)
+			Expect(points[0][1]).To(Equal(points[1][1]))
+		})
+	})
+})
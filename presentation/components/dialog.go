@@ -0,0 +1,170 @@
+package components
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// DialogLineSpacing is the line height relative to the text size of the dialog
+const DialogLineSpacing = 1.5
+
+// Dialog shows a message with choices and takes the input until one of them is chosen.
+// Unlike Popup it does not close until the player chooses.
+// The last option is the safe one, such as Cancel, No or Keep: the cursor starts on it and Cancel chooses it.
+type Dialog struct {
+	style   *Style
+	Message string   // 表示メッセージ (複数行可)
+	Options []string // 選択肢
+	Cursor  int      // 選択中の選択肢
+	Active  bool     // アクティブ状態
+	// Screen size of the last Draw, to find the option under the mouse
+	screenWidth  int
+	screenHeight int
+}
+
+func NewDialog(style *Style) *Dialog {
+	return &Dialog{
+		style: style,
+	}
+}
+
+func (d *Dialog) Show(message string, options []string) {
+	d.Message = message
+	d.Options = options
+	d.Cursor = max(len(options)-1, 0)
+	d.Active = true
+}
+
+func (d *Dialog) IsActive() bool {
+	return d.Active
+}
+
+// HandleInput moves the cursor over the choices and returns the chosen one.
+// A click chooses the option under the mouse and clicks outside the options are ignored.
+// Cancel chooses the last option.
+// The dialog closes when a choice is made.
+func (d *Dialog) HandleInput(keyType input.KeyType, isClicked, isMouseMoved bool, mouseX, mouseY int) (int, bool) {
+	if !d.IsActive() || len(d.Options) == 0 {
+		return 0, false
+	}
+
+	if isMouseMoved || isClicked {
+		if option := d.hoverOption(mouseX, mouseY); option != -1 {
+			d.Cursor = option
+			if isClicked {
+				return d.choose()
+			}
+		}
+	}
+
+	switch keyType {
+	case input.KeyTypeUp:
+		d.Cursor = (d.Cursor - 1 + len(d.Options)) % len(d.Options)
+	case input.KeyTypeDown:
+		d.Cursor = (d.Cursor + 1) % len(d.Options)
+	case input.KeyTypeDecision:
+		return d.choose()
+	case input.KeyTypeCancel:
+		d.Cursor = len(d.Options) - 1
+		return d.choose()
+	}
+	return 0, false
+}
+
+func (d *Dialog) choose() (int, bool) {
+	d.Active = false
+	return d.Cursor, true
+}
+
+// geometry returns the panel, the position of the first option and the line height for the screen size
+func (d *Dialog) geometry(screenWidth, screenHeight int) (x, y, width, height, optionsY, lineHeight float32) {
+	padding := float32(d.style.Px(d.style.Padding.Dialog))
+	x, y = padding, padding
+	width = float32(screenWidth) - padding*2
+	height = float32(screenHeight) - padding*2
+	lineHeight = float32(d.style.TextSizes.Dialog * DialogLineSpacing * d.style.Scale)
+	// メッセージと空行の下
+	optionsY = y + padding + lineHeight*float32(len(strings.Split(d.Message, "\n"))+1)
+	return
+}
+
+// hoverOption returns the option under the mouse, or -1 if none
+func (d *Dialog) hoverOption(mouseX, mouseY int) int {
+	if d.screenWidth == 0 {
+		return -1
+	}
+	x, _, width, _, optionsY, lineHeight := d.geometry(d.screenWidth, d.screenHeight)
+	mx, my := float32(mouseX), float32(mouseY)
+	if mx < x || mx >= x+width || my < optionsY {
+		return -1
+	}
+	option := int((my - optionsY) / lineHeight)
+	if option >= len(d.Options) {
+		return -1
+	}
+	return option
+}
+
+// GetOptionPosition returns the middle of the row of the option as last drawn (for testing)
+func (d *Dialog) GetOptionPosition(option int) (int, int) {
+	x, _, width, _, optionsY, lineHeight := d.geometry(d.screenWidth, d.screenHeight)
+	return int(x + width/2), int(optionsY + lineHeight*(float32(option)+0.5))
+}
+
+func (d *Dialog) Draw(screen *ebiten.Image) {
+	if !d.IsActive() {
+		return
+	}
+
+	d.screenWidth = screen.Bounds().Dx()
+	d.screenHeight = screen.Bounds().Dy()
+	x, y, width, height, optionsY, lineHeight := d.geometry(d.screenWidth, d.screenHeight)
+	lines := strings.Split(d.Message, "\n")
+	colors := d.style.Colors
+	padding := float32(d.style.Px(d.style.Padding.Dialog))
+	border := float32(d.style.Px(1))
+
+	// 画面の大部分を覆う背景
+	vector.FillRect(screen, x, y, width, height, colors.PopupBorder, false)
+	vector.FillRect(screen, x+border, y+border, width-border*2, height-border*2, colors.PopupBg, false)
+
+	face := d.style.Face(d.style.TextSizes.Dialog)
+	textY := float64(y + padding)
+	for _, line := range lines {
+		d.drawLine(screen, face, line, float64(x+padding), textY, colors.PopupText)
+		textY += float64(lineHeight)
+	}
+
+	textY = float64(optionsY)
+	for i, option := range d.Options {
+		textColor := colors.PopupText
+		prefix := "  "
+		if i == d.Cursor {
+			textColor = colors.DialogSelected
+			prefix = "> "
+		}
+		d.drawLine(screen, face, prefix+option, float64(x+padding), textY, textColor)
+		textY += float64(lineHeight)
+	}
+
+	hintText := "[Up/Down] Choose  [Enter] Confirm  [Esc] Cancel"
+	txtOp := &text.DrawOptions{}
+	txtOp.PrimaryAlign = text.AlignEnd
+	txtOp.SecondaryAlign = text.AlignEnd
+	txtOp.GeoM.Translate(float64(x+width-padding), float64(y+height-padding))
+	txtOp.ColorScale.ScaleWithColor(colors.Hint)
+	text.Draw(screen, hintText, face, txtOp)
+}
+
+func (d *Dialog) drawLine(screen *ebiten.Image, face *text.GoTextFace, line string, x, y float64, textColor color.Color) {
+	txtOp := &text.DrawOptions{}
+	txtOp.GeoM.Translate(x, y)
+	txtOp.ColorScale.ScaleWithColor(textColor)
+	text.Draw(screen, line, face, txtOp)
+}
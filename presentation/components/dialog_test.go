@@ -0,0 +1,87 @@
+package components
+
+import (
+	"github.com/kmdkuk/clicker/presentation/input"
+	"github.com/kmdkuk/clicker/presentation/theme"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dialog", func() {
+	var dialog *Dialog
+	style, err := NewStyle(theme.Dark())
+	Expect(err).NotTo(HaveOccurred())
+
+	optionY := func(option int) int {
+		_, y := dialog.GetOptionPosition(option)
+		return y
+	}
+
+	BeforeEach(func() {
+		dialog = NewDialog(style)
+		dialog.Show("Your save could not be loaded.", []string{"Keep this game", "Restore backup 2", "Restore backup 3"})
+		dialog.Draw(ebiten.NewImage(640, 480))
+	})
+
+	It("should start on the last choice, which is the safe one", func() {
+		Expect(dialog.IsActive()).To(BeTrue())
+		Expect(dialog.Cursor).To(Equal(2))
+	})
+
+	It("should move the cursor and wrap around", func() {
+		dialog.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+		Expect(dialog.Cursor).To(Equal(0))
+		dialog.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+		Expect(dialog.Cursor).To(Equal(2))
+		dialog.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+		Expect(dialog.Cursor).To(Equal(1))
+	})
+
+	It("should stay open until a choice is made", func() {
+		_, chosen := dialog.HandleInput(input.KeyTypeLeft, false, false, 0, 0)
+		Expect(chosen).To(BeFalse())
+		Expect(dialog.IsActive()).To(BeTrue())
+	})
+
+	It("should return the choice and close on decision", func() {
+		dialog.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+		choice, chosen := dialog.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+		Expect(chosen).To(BeTrue())
+		Expect(choice).To(Equal(1))
+		Expect(dialog.IsActive()).To(BeFalse())
+	})
+
+	It("should select the option under the mouse", func() {
+		_, chosen := dialog.HandleInput(input.KeyTypeNone, false, true, 100, optionY(1))
+		Expect(chosen).To(BeFalse())
+		Expect(dialog.Cursor).To(Equal(1))
+	})
+
+	It("should return the option that was clicked", func() {
+		choice, chosen := dialog.HandleInput(input.KeyTypeNone, true, false, 100, optionY(2))
+		Expect(chosen).To(BeTrue())
+		Expect(choice).To(Equal(2))
+		Expect(dialog.IsActive()).To(BeFalse())
+	})
+
+	It("should ignore clicks outside the options", func() {
+		for _, y := range []int{optionY(0) - 40, optionY(3)} {
+			_, chosen := dialog.HandleInput(input.KeyTypeNone, true, false, 100, y)
+			Expect(chosen).To(BeFalse())
+		}
+		_, chosen := dialog.HandleInput(input.KeyTypeNone, true, false, 5, optionY(1))
+		Expect(chosen).To(BeFalse())
+		Expect(dialog.IsActive()).To(BeTrue())
+		Expect(dialog.Cursor).To(Equal(2))
+	})
+
+	It("should choose the last option on cancel", func() {
+		dialog.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+		choice, chosen := dialog.HandleInput(input.KeyTypeCancel, false, false, 0, 0)
+		Expect(chosen).To(BeTrue())
+		Expect(choice).To(Equal(2))
+		Expect(dialog.IsActive()).To(BeFalse())
+	})
+})
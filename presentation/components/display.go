@@ -1,65 +1,86 @@
 package components
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/kmdkuk/clicker/application/dto"
-	"github.com/kmdkuk/clicker/assets/fonts"
 	"github.com/kmdkuk/clicker/presentation/formatter"
 )
 
 // DisplayComponent shows basic game information
 type Display struct {
-	x int
-	y int
+	style *Style
+	x     int
+	y     int
+	width int // 0 extends the display to the right edge of the screen
 }
 
-func NewDisplay(x, y int) *Display {
+func NewDisplay(style *Style, x, y int) *Display {
 	return &Display{
-		x: x,
-		y: y,
+		style: style,
+		x:     x,
+		y:     y,
 	}
 }
 
+// SetBounds moves the display, e.g. when the window is resized
+func (d *Display) SetBounds(x, y, width int) {
+	d.x = x
+	d.y = y
+	d.width = width
+}
+
 func (d *Display) calcItemWidthHeight(screenWidth int) (float32, float32) {
-	itemWidth := screenWidth - d.x - ScrollbarWidth - ScrollbarMargin*2
-	itemHeight := ItemHeight - ItemVerticalShift
+	areaWidth := screenWidth - d.x
+	if d.width > 0 {
+		areaWidth = d.width
+	}
+	itemWidth := areaWidth - d.style.Px(ScrollbarWidth) - d.style.Px(ScrollbarMargin)*2
+	itemHeight := d.style.Px(ItemHeight) - d.style.Px(ItemVerticalShift)
 	return float32(itemWidth), float32(itemHeight)
 }
 
-func (d *Display) DrawMoney(screen *ebiten.Image, playerDTO *dto.Player) {
-	moneyText := fmt.Sprintf("Money: %s (Total Generate Rate: %s/s)",
+// moneyText splits the net rate into gross income and upkeep and reports throttled buildings
+// and saves edited outside the game
+func moneyText(playerDTO *dto.Player) string {
+	moneyText := fmt.Sprintf("Money: %s (Income: %s/s - Upkeep: %s/s = Net: %s/s)",
 		formatter.FormatCurrency(playerDTO.GetMoney(), "$"),
 		formatter.FormatCurrency(playerDTO.GetTotalGenerateRate(), "$"),
+		formatter.FormatCurrency(playerDTO.GetTotalUpkeep(), "$"),
+		formatter.FormatCurrency(playerDTO.GetNetRate(), "$"),
 	)
+	switch throttle := playerDTO.GetThrottle(); {
+	case throttle <= 0:
+		moneyText += " Shut down!"
+	case throttle < 1:
+		moneyText += fmt.Sprintf(" Throttled: %.0f%%", throttle*100)
+	}
+	if playerDTO.GetIsTampered() {
+		moneyText += " [Modified save]"
+	}
+	return moneyText
+}
+
+func (d *Display) DrawMoney(screen *ebiten.Image, playerDTO *dto.Player) {
+	moneyText := moneyText(playerDTO)
 
-	bgColor := NormalBgColor
+	bgColor := d.style.Colors.NormalBg
 
 	// 背景矩形を描画
 	rectWidth, rectHeight := d.calcItemWidthHeight(screen.Bounds().Dx())
 	vector.FillRect(screen, float32(d.x), float32(d.y), rectWidth, rectHeight, bgColor, false)
 
 	// テキストの色を設定（選択中かどうかで分ける）
-	textColor := NormalTextColor
+	textColor := d.style.Colors.NormalText
 
 	// フォントフェイスを作成
-	s, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.BebasNeueRegular_ttf))
-	if err != nil {
-		fmt.Printf("Error loading font: %v", err)
-		return
-	}
-
-	face := &text.GoTextFace{
-		Source: s,
-		Size:   float64(TextSize),
-	}
+	face := d.style.Face(d.style.TextSizes.Text)
 
 	rectCenterY := float32(d.y) + rectHeight/2
-	textX := float64(d.x) + ItemTextPadding
+	textX := float64(d.x + d.style.Px(d.style.Padding.ItemText))
 	textY := float64(rectCenterY)
 
 	// テキスト描画
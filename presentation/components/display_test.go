@@ -3,6 +3,7 @@ package components
 import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/presentation/theme"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -18,8 +19,12 @@ var _ = Describe("Display", func() {
 		playerDTO = &dto.Player{
 			Money:             123.45,
 			TotalGenerateRate: 6.78,
+			TotalUpkeep:       1.23,
+			Throttle:          1,
 		}
-		display = NewDisplay(10, 10)
+		style, err := NewStyle(theme.Dark())
+		Expect(err).NotTo(HaveOccurred())
+		display = NewDisplay(style, 10, 10)
 		mockScreen = ebiten.NewImage(640, 480)
 	})
 
@@ -36,4 +41,25 @@ var _ = Describe("Display", func() {
 			}).NotTo(Panic())
 		})
 	})
+
+	Describe("moneyText", func() {
+		It("should split the net rate into income and upkeep", func() {
+			Expect(moneyText(playerDTO)).To(Equal("Money: $ 123 (Income: $ 6.78/s - Upkeep: $ 1.23/s = Net: $ 5.55/s)"))
+		})
+
+		It("should report throttled buildings", func() {
+			playerDTO.Throttle = 0.5
+			Expect(moneyText(playerDTO)).To(HaveSuffix(" Throttled: 50%"))
+		})
+
+		It("should report shut down buildings", func() {
+			playerDTO.Throttle = 0
+			Expect(moneyText(playerDTO)).To(HaveSuffix(" Shut down!"))
+		})
+
+		It("should mark an edited save", func() {
+			playerDTO.IsTampered = true
+			Expect(moneyText(playerDTO)).To(HaveSuffix(" [Modified save]"))
+		})
+	})
 })
@@ -0,0 +1,225 @@
+package components
+
+import (
+	"image/color"
+
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// FormArrowWidth is the width of the "<" in front of a value, which decreases the value when clicked
+const FormArrowWidth = 30
+
+// FormHint is the default hint of a form
+const FormHint = "[Up/Down] Choose  [Left/Right] Change  [Esc] Close"
+
+// FormField is a row of a form with the values it can take.
+// A button row shows its selected option as is and is reported as changed when it is pressed.
+type FormField struct {
+	Label    string
+	Options  []string
+	Selected int
+	Button   bool
+}
+
+// Value returns the selected option
+func (f *FormField) Value() string {
+	if f.Selected < 0 || f.Selected >= len(f.Options) {
+		return ""
+	}
+	return f.Options[f.Selected]
+}
+
+// step moves the selection by delta, wrapping around at the ends if wrap is set
+func (f *FormField) step(delta int, wrap bool) bool {
+	if len(f.Options) == 0 {
+		return false
+	}
+	next := f.Selected + delta
+	switch {
+	case wrap:
+		next = (next%len(f.Options) + len(f.Options)) % len(f.Options)
+	case next < 0 || next >= len(f.Options):
+		return false
+	}
+	changed := next != f.Selected
+	f.Selected = next
+	return changed
+}
+
+// Form shows fields whose values the player changes with the keyboard or the mouse.
+// Like Dialog it covers the screen and takes the input while it is active.
+// Unlike List, the selected row has a value that can be changed instead of only being chosen.
+type Form struct {
+	style  *Style
+	Title  string
+	Hint   string // Keys shown at the bottom
+	Fields []FormField
+	Cursor int  // 選択中のフィールド
+	Active bool // アクティブ状態
+	// Screen size of the last Draw, to find the row under the mouse
+	screenWidth  int
+	screenHeight int
+}
+
+func NewForm(style *Style, title string) *Form {
+	return &Form{
+		style: style,
+		Title: title,
+		Hint:  FormHint,
+	}
+}
+
+// Show opens the form with the fields, keeping the cursor if it still points at a field
+func (f *Form) Show(fields []FormField) {
+	f.Fields = fields
+	if f.Cursor >= len(fields) {
+		f.Cursor = 0
+	}
+	f.Active = true
+}
+
+func (f *Form) Close() {
+	f.Active = false
+}
+
+func (f *Form) IsActive() bool {
+	return f.Active
+}
+
+// HandleInput moves the cursor over the fields and changes the value of the selected one.
+// Left and Right step through the values, Enter and clicks cycle through them and a click on
+// the "<" steps back. It returns the index of the changed field and true if a value changed.
+func (f *Form) HandleInput(keyType input.KeyType, isClicked, isMouseMoved bool, mouseX, mouseY int) (int, bool) {
+	if !f.IsActive() || len(f.Fields) == 0 {
+		return 0, false
+	}
+
+	if isMouseMoved || isClicked {
+		if row, onArrow := f.hoverRow(mouseX, mouseY); row != -1 {
+			f.Cursor = row
+			if isClicked {
+				if f.Fields[row].Button {
+					return row, true
+				}
+				if onArrow {
+					return f.change(-1, false)
+				}
+				return f.change(1, true)
+			}
+		}
+	}
+
+	switch keyType {
+	case input.KeyTypeUp:
+		f.Cursor = (f.Cursor - 1 + len(f.Fields)) % len(f.Fields)
+	case input.KeyTypeDown:
+		f.Cursor = (f.Cursor + 1) % len(f.Fields)
+	case input.KeyTypeLeft:
+		return f.change(-1, false)
+	case input.KeyTypeRight:
+		return f.change(1, false)
+	case input.KeyTypeDecision:
+		if f.Fields[f.Cursor].Button {
+			return f.Cursor, true
+		}
+		return f.change(1, true)
+	case input.KeyTypeCancel:
+		f.Close()
+	}
+	return 0, false
+}
+
+func (f *Form) change(delta int, wrap bool) (int, bool) {
+	if !f.Fields[f.Cursor].step(delta, wrap) {
+		return 0, false
+	}
+	return f.Cursor, true
+}
+
+// geometry returns the panel and the position of the first row for the screen size
+func (f *Form) geometry(screenWidth, screenHeight int) (x, y, width, height, rowsY, rowHeight, valueX int) {
+	padding := f.style.Px(f.style.Padding.Dialog)
+	x, y = padding, padding
+	width = screenWidth - padding*2
+	height = screenHeight - padding*2
+	rowHeight = f.style.RowHeight()
+	rowsY = y + padding + rowHeight // タイトルの下
+	valueX = x + width/2
+	return
+}
+
+// hoverRow returns the row under the mouse and whether the mouse is on its "<", or -1 if none
+func (f *Form) hoverRow(mouseX, mouseY int) (int, bool) {
+	if f.screenWidth == 0 {
+		return -1, false
+	}
+	x, _, width, _, rowsY, rowHeight, valueX := f.geometry(f.screenWidth, f.screenHeight)
+	if mouseX < x || mouseX >= x+width || mouseY < rowsY {
+		return -1, false
+	}
+	row := (mouseY - rowsY) / rowHeight
+	if row >= len(f.Fields) {
+		return -1, false
+	}
+	onArrow := mouseX >= valueX && mouseX < valueX+f.style.Px(FormArrowWidth)
+	return row, onArrow
+}
+
+func (f *Form) Draw(screen *ebiten.Image) {
+	if !f.IsActive() {
+		return
+	}
+	f.screenWidth = screen.Bounds().Dx()
+	f.screenHeight = screen.Bounds().Dy()
+	x, y, width, height, rowsY, rowHeight, valueX := f.geometry(f.screenWidth, f.screenHeight)
+	colors := f.style.Colors
+	padding := f.style.Px(f.style.Padding.Dialog)
+	border := float32(f.style.Px(1))
+
+	// 画面の大部分を覆う背景
+	vector.FillRect(screen, float32(x), float32(y), float32(width), float32(height), colors.PopupBorder, false)
+	vector.FillRect(screen, float32(x)+border, float32(y)+border, float32(width)-border*2, float32(height)-border*2, colors.PopupBg, false)
+
+	face := f.style.Face(f.style.TextSizes.Text)
+	f.drawText(screen, face, f.Title, x+padding, y+padding+rowHeight/2, colors.PopupText)
+
+	shift := f.style.Px(ItemVerticalShift)
+	for i := range f.Fields {
+		field := &f.Fields[i]
+		rowY := rowsY + i*rowHeight
+		textColor := colors.PopupText
+		if i == f.Cursor {
+			vector.FillRect(screen, float32(x+padding), float32(rowY+shift), float32(width-padding*2), float32(rowHeight-shift*2), colors.SelectedBg, false)
+			textColor = colors.SelectedText
+		}
+		centerY := rowY + rowHeight/2
+		f.drawText(screen, face, field.Label, x+padding*2, centerY, textColor)
+		if field.Button {
+			f.drawText(screen, face, field.Value(), valueX, centerY, textColor)
+		} else {
+			f.drawText(screen, face, "<  "+field.Value()+"  >", valueX, centerY, textColor)
+		}
+	}
+
+	hintText := f.Hint
+	hintFace := f.style.Face(f.style.TextSizes.Dialog)
+	txtOp := &text.DrawOptions{}
+	txtOp.PrimaryAlign = text.AlignEnd
+	txtOp.SecondaryAlign = text.AlignEnd
+	txtOp.GeoM.Translate(float64(x+width-padding), float64(y+height-padding))
+	txtOp.ColorScale.ScaleWithColor(colors.Hint)
+	text.Draw(screen, hintText, hintFace, txtOp)
+}
+
+func (f *Form) drawText(screen *ebiten.Image, face *text.GoTextFace, s string, x, centerY int, textColor color.Color) {
+	txtOp := &text.DrawOptions{}
+	txtOp.PrimaryAlign = text.AlignStart
+	txtOp.SecondaryAlign = text.AlignCenter
+	txtOp.GeoM.Translate(float64(x), float64(centerY))
+	txtOp.ColorScale.ScaleWithColor(textColor)
+	text.Draw(screen, s, face, txtOp)
+}
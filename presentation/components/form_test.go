@@ -0,0 +1,102 @@
+package components
+
+import (
+	"github.com/kmdkuk/clicker/presentation/input"
+	"github.com/kmdkuk/clicker/presentation/theme"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Form", func() {
+	var form *Form
+	style, err := NewStyle(theme.Dark())
+	Expect(err).NotTo(HaveOccurred())
+
+	// Rows start below the title: padding 20 + padding 20 + one row of 40
+	const firstRowY = 80 + ItemHeight/2
+
+	BeforeEach(func() {
+		form = NewForm(style, "Settings")
+		form.Show([]FormField{
+			{Label: "Volume", Options: []string{"0%", "50%", "100%"}, Selected: 2},
+			{Label: "Confirm purchases", Options: []string{"Off", "On"}},
+		})
+		form.Draw(ebiten.NewImage(640, 480))
+	})
+
+	It("should move the cursor with Up and Down", func() {
+		form.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+		Expect(form.Cursor).To(Equal(1))
+		form.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+		Expect(form.Cursor).To(Equal(0))
+		form.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+		Expect(form.Cursor).To(Equal(1))
+	})
+
+	It("should step through the values with Left and Right without wrapping", func() {
+		field, changed := form.HandleInput(input.KeyTypeRight, false, false, 0, 0)
+		Expect(changed).To(BeFalse())
+
+		field, changed = form.HandleInput(input.KeyTypeLeft, false, false, 0, 0)
+		Expect(changed).To(BeTrue())
+		Expect(field).To(Equal(0))
+		Expect(form.Fields[0].Value()).To(Equal("50%"))
+	})
+
+	It("should cycle through the values with Enter", func() {
+		form.Cursor = 1
+		field, changed := form.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+		Expect(changed).To(BeTrue())
+		Expect(field).To(Equal(1))
+		Expect(form.Fields[1].Value()).To(Equal("On"))
+		form.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+		Expect(form.Fields[1].Value()).To(Equal("Off"))
+	})
+
+	It("should select the row under the mouse and change it on a click", func() {
+		form.HandleInput(input.KeyTypeNone, false, true, 100, firstRowY+ItemHeight)
+		Expect(form.Cursor).To(Equal(1))
+
+		field, changed := form.HandleInput(input.KeyTypeNone, true, false, 500, firstRowY+ItemHeight)
+		Expect(changed).To(BeTrue())
+		Expect(field).To(Equal(1))
+		Expect(form.Fields[1].Value()).To(Equal("On"))
+	})
+
+	It("should step back on a click on the arrow", func() {
+		field, changed := form.HandleInput(input.KeyTypeNone, true, false, 325, firstRowY)
+		Expect(changed).To(BeTrue())
+		Expect(field).To(Equal(0))
+		Expect(form.Fields[0].Value()).To(Equal("50%"))
+	})
+
+	It("should ignore clicks outside the rows", func() {
+		_, changed := form.HandleInput(input.KeyTypeNone, true, false, 100, 400)
+		Expect(changed).To(BeFalse())
+		Expect(form.Cursor).To(Equal(0))
+	})
+
+	It("should report a press of a button without changing it", func() {
+		form.Show(append(form.Fields, FormField{Label: "Key bindings", Options: []string{"Edit"}, Button: true}))
+		form.Cursor = 2
+		_, changed := form.HandleInput(input.KeyTypeRight, false, false, 0, 0)
+		Expect(changed).To(BeFalse())
+
+		field, changed := form.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+		Expect(changed).To(BeTrue())
+		Expect(field).To(Equal(2))
+		field, changed = form.HandleInput(input.KeyTypeNone, true, false, 325, firstRowY+2*ItemHeight)
+		Expect(changed).To(BeTrue())
+		Expect(field).To(Equal(2))
+		Expect(form.Fields[2].Value()).To(Equal("Edit"))
+	})
+
+	It("should close with Escape", func() {
+		form.HandleInput(input.KeyTypeCancel, false, false, 0, 0)
+		Expect(form.IsActive()).To(BeFalse())
+		_, changed := form.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+		Expect(changed).To(BeFalse())
+	})
+})
@@ -1,7 +1,7 @@
 package components
 
 import (
-	"image/color"
+	"math"
 
 	"github.com/kmdkuk/clicker/application/dto"
 
@@ -26,47 +26,82 @@ func ConvertUpgradeToListItems(upgrades []dto.Upgrade) []ListItem {
 	return items
 }
 
+func ConvertMarketOrderToListItems(orders []dto.MarketOrder) []ListItem {
+	items := make([]ListItem, len(orders))
+	for i := range orders {
+		items[i] = &orders[i]
+	}
+	return items
+}
+
+func ConvertSlotToListItems(slots []dto.Slot) []ListItem {
+	items := make([]ListItem, len(slots))
+	for i := range slots {
+		items[i] = &slots[i]
+	}
+	return items
+}
+
 type ListItem interface {
 	String() string
 }
 
 type List struct {
-	source       *text.GoTextFaceSource
+	style        *Style
 	Items        []ListItem
 	Visible      bool
 	x            int
 	y            int
+	width        int // Width including the scrollbar, 0 extends the list to the right edge of the screen
 	scrollPos    int // Current scroll position
 	viewportSize int // Number of items visible at once
+	lastCursor   int // Cursor of the last Draw, the list only scrolls to the cursor when it moves
+	// Dragging with a finger
+	dragOffset float64 // Pixels dragged that do not add up to a whole row yet
+	velocity   float64 // Pixels per frame the list keeps scrolling after the finger is lifted
 }
 
-func NewList(source *text.GoTextFaceSource, defaultVisible bool, x, y int) *List {
-	return NewListWithViewport(source, defaultVisible, x, y, ViewportSize)
+func NewList(style *Style, defaultVisible bool, x, y int) *List {
+	return NewListWithViewport(style, defaultVisible, x, y, ViewportSize)
 }
 
 // NewListWithViewport creates a new list with a specified viewport size and font
-func NewListWithViewport(source *text.GoTextFaceSource, defaultVisible bool, x, y, viewportSize int) *List {
+func NewListWithViewport(style *Style, defaultVisible bool, x, y, viewportSize int) *List {
 	return &List{
-		source:       source,
+		style:        style,
 		Items:        []ListItem{},
 		Visible:      defaultVisible,
 		x:            x,
 		y:            y,
 		scrollPos:    0,
 		viewportSize: viewportSize,
+		lastCursor:   -1,
 	}
 }
 
+// SetBounds moves the list and changes how many items it shows at once, e.g. when the window is resized
+func (l *List) SetBounds(x, y, width, viewportSize int) {
+	l.x = x
+	l.y = y
+	l.width = width
+	l.viewportSize = max(viewportSize, 1)
+	l.lastCursor = -1 // Keep the cursor in view after the resize
+}
+
 func (l *List) Draw(screen *ebiten.Image, cursor int) {
 	if (!l.Visible) || (len(l.Items) == 0) {
 		return
 	}
 
-	// Adjust scroll position if cursor moves outside the viewport
-	if cursor < l.scrollPos {
-		l.scrollPos = cursor
-	} else if cursor >= l.scrollPos+l.viewportSize {
-		l.scrollPos = cursor - l.viewportSize + 1
+	// Adjust scroll position if cursor moves outside the viewport.
+	// A list dragged away from the cursor stays where it is until the cursor moves.
+	if cursor != l.lastCursor {
+		l.lastCursor = cursor
+		if cursor < l.scrollPos {
+			l.scrollPos = cursor
+		} else if cursor >= l.scrollPos+l.viewportSize {
+			l.scrollPos = cursor - l.viewportSize + 1
+		}
 	}
 
 	// Ensure scroll position stays within valid range
@@ -90,7 +125,7 @@ func (l *List) Draw(screen *ebiten.Image, cursor int) {
 	// Draw only items within the current viewport
 	for i := l.scrollPos; i < endIdx; i++ {
 		item := l.Items[i]
-		displayY := l.y + (i-l.scrollPos)*ItemHeight + ItemVerticalShift
+		displayY := l.y + (i-l.scrollPos)*l.style.RowHeight() + l.style.Px(ItemVerticalShift)
 		l.drawItem(screen, item, l.x, displayY, i == cursor)
 	}
 
@@ -98,20 +133,27 @@ func (l *List) Draw(screen *ebiten.Image, cursor int) {
 	l.drawScrollBar(screen, endIdx)
 }
 
+// areaWidth returns the width of the list including the scrollbar
+func (l *List) areaWidth(screenWidth int) int {
+	if l.width > 0 {
+		return l.width
+	}
+	return screenWidth - l.x
+}
+
 func (l *List) calcItemWidthHeight(screenWidth int, x, y int) (float32, float32) {
-	itemWidth := screenWidth - x - ScrollbarWidth - ScrollbarMargin*2
-	itemHeight := ItemHeight - ItemVerticalShift*2
+	itemWidth := l.areaWidth(screenWidth) - l.style.Px(ScrollbarWidth) - l.style.Px(ScrollbarMargin)*2
+	itemHeight := l.style.RowHeight() - l.style.Px(ItemVerticalShift)*2
 
 	return float32(itemWidth), float32(itemHeight)
 
 }
 
 func (l *List) drawItem(screen *ebiten.Image, item ListItem, x, y int, isSelected bool) {
-	var bgColor color.RGBA
+	colors := l.style.Colors
+	bgColor := colors.NormalBg
 	if isSelected {
-		bgColor = SelectedBgColor
-	} else {
-		bgColor = NormalBgColor
+		bgColor = colors.SelectedBg
 	}
 
 	// 背景矩形を描画
@@ -119,11 +161,9 @@ func (l *List) drawItem(screen *ebiten.Image, item ListItem, x, y int, isSelecte
 	vector.FillRect(screen, float32(x), float32(y), rectWidth, rectHeight, bgColor, false)
 
 	// テキストの色を設定（選択中かどうかで分ける）
-	var textColor color.RGBA
+	textColor := colors.NormalText
 	if isSelected {
-		textColor = SelectedTextColor
-	} else {
-		textColor = NormalTextColor
+		textColor = colors.SelectedText
 	}
 
 	// テキストを描画
@@ -133,13 +173,10 @@ func (l *List) drawItem(screen *ebiten.Image, item ListItem, x, y int, isSelecte
 	}
 
 	// フォントフェイスを作成
-	face := &text.GoTextFace{
-		Source: l.source,
-		Size:   float64(TextSize),
-	}
+	face := l.style.Face(l.style.TextSizes.Text)
 
 	rectCenterY := float32(y) + rectHeight/2
-	textX := float64(x + ItemTextPadding)
+	textX := float64(x + l.style.Px(l.style.Padding.ItemText))
 	textY := float64(rectCenterY)
 
 	// テキスト描画
@@ -152,10 +189,9 @@ func (l *List) drawItem(screen *ebiten.Image, item ListItem, x, y int, isSelecte
 	text.Draw(screen, textStr, face, txtOp)
 }
 
-// drawScrollBar draws a scrollbar on the right edge of the screen
+// drawScrollBar draws a scrollbar on the right edge of the list
 func (l *List) drawScrollBar(screen *ebiten.Image, endIdx int) {
-	// Get screen dimensions
-	screenWidth := screen.Bounds().Dx()
+	scrollbarWidth := l.style.Px(ScrollbarWidth)
 
 	// Skip drawing scrollbar if all items fit in viewport
 	if len(l.Items) <= l.viewportSize {
@@ -163,23 +199,23 @@ func (l *List) drawScrollBar(screen *ebiten.Image, endIdx int) {
 	}
 
 	// Calculate scrollbar position
-	scrollbarX := float64(screenWidth - ScrollbarWidth - ScrollbarMargin)
+	scrollbarX := float64(l.x + l.areaWidth(screen.Bounds().Dx()) - scrollbarWidth - l.style.Px(ScrollbarMargin))
 	scrollbarY := float64(l.y)
 
 	// Calculate scrollbar height based on visible range
 	visibleCount := endIdx - l.scrollPos
-	listHeight := float64(visibleCount * ItemHeight)
+	listHeight := float64(visibleCount * l.style.RowHeight())
 
 	// Draw scrollbar background (track)
 	vector.FillRect(screen, float32(scrollbarX), float32(scrollbarY),
-		float32(ScrollbarWidth), float32(listHeight), ScrollbarTrackColor, false)
+		float32(scrollbarWidth), float32(listHeight), l.style.Colors.ScrollbarTrack, false)
 
 	// Calculate handle size and position
 	totalItems := len(l.Items)
 	handleRatio := float64(visibleCount) / float64(totalItems)
 	handleHeight := listHeight * handleRatio
-	if handleHeight < MinimumHandleHeight {
-		handleHeight = MinimumHandleHeight // 最小ハンドル高さを確保
+	if minimum := float64(l.style.Px(MinimumHandleHeight)); handleHeight < minimum {
+		handleHeight = minimum // 最小ハンドル高さを確保
 	}
 
 	// Position the handle based on scroll position
@@ -191,7 +227,7 @@ func (l *List) drawScrollBar(screen *ebiten.Image, endIdx int) {
 
 	// Draw scrollbar handle
 	vector.FillRect(screen, float32(scrollbarX), float32(handleY),
-		float32(ScrollbarWidth), float32(handleHeight), ScrollbarHandleColor, false)
+		float32(scrollbarWidth), float32(handleHeight), l.style.Colors.ScrollbarHandle, false)
 }
 
 // Scroll manually adjusts the scroll position by the specified amount
@@ -212,6 +248,43 @@ func (l *List) Scroll(amount int) {
 	}
 }
 
+// Drag scrolls the list with a finger that moved dy device pixels down, so that the items follow the finger.
+// The list scrolls by whole rows once the finger moved far enough.
+func (l *List) Drag(dy float64) {
+	// 直近のフレームの速さを慣性スクロールに使う
+	l.velocity = (l.velocity - dy) / 2
+	l.scrollBy(-dy)
+}
+
+// Coast keeps scrolling the list after the finger is lifted, slowing down until it stops
+func (l *List) Coast() {
+	if l.velocity == 0 {
+		return
+	}
+	l.scrollBy(l.velocity)
+	l.velocity *= ScrollFriction
+	if math.Abs(l.velocity) < MinimumScrollSpeed {
+		l.velocity = 0
+	}
+}
+
+// scrollBy scrolls by the rows that the pixels add up to, and stops the coasting at the ends of the list
+func (l *List) scrollBy(pixels float64) {
+	rowHeight := float64(l.style.RowHeight())
+	l.dragOffset += pixels
+	rows := int(l.dragOffset / rowHeight)
+	if rows == 0 {
+		return
+	}
+	l.dragOffset -= float64(rows) * rowHeight
+	before := l.scrollPos
+	l.Scroll(rows)
+	if l.scrollPos-before != rows {
+		l.dragOffset = 0
+		l.velocity = 0
+	}
+}
+
 // GetVisibleRange returns the start and end indices of currently visible items (for testing)
 func (l *List) GetVisibleRange() (start, end int) {
 	end = l.scrollPos + l.viewportSize
@@ -221,6 +294,13 @@ func (l *List) GetVisibleRange() (start, end int) {
 	return l.scrollPos, end
 }
 
+// GetItemPosition returns the middle of the visible item at index (for testing)
+func (l *List) GetItemPosition(screenWidth, index int) (int, int) {
+	itemWidth, _ := l.calcItemWidthHeight(screenWidth, l.x, l.y)
+	itemHeight := l.style.RowHeight()
+	return l.x + int(itemWidth)/2, l.y + (index-l.scrollPos)*itemHeight + itemHeight/2
+}
+
 func (l *List) GetHoverCursor(screenWidth, mouseX, mouseY int) int {
 	if !l.Visible {
 		return -1
@@ -229,10 +309,11 @@ func (l *List) GetHoverCursor(screenWidth, mouseX, mouseY int) int {
 	itemWidth, _ := l.calcItemWidthHeight(screenWidth, l.x, l.y)
 	startX := l.x
 	endX := l.x + int(itemWidth)
+	itemHeight := l.style.RowHeight()
 	for i := l.scrollPos; i < l.scrollPos+l.viewportSize && i < len(l.Items); i++ {
-		offsetY := l.y + (i-l.scrollPos)*(ItemHeight)
+		offsetY := l.y + (i-l.scrollPos)*itemHeight
 		if mouseX >= startX && mouseX < endX {
-			if mouseY >= offsetY && mouseY <= offsetY+int(ItemHeight) {
+			if mouseY >= offsetY && mouseY <= offsetY+itemHeight {
 				return i
 			}
 		}
@@ -1,13 +1,10 @@
 package components
 
 import (
-	"bytes"
-
 	"github.com/kmdkuk/clicker/application/dto"
-	"github.com/kmdkuk/clicker/assets/fonts"
+	"github.com/kmdkuk/clicker/presentation/theme"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -27,7 +24,7 @@ var _ = Describe("List", func() {
 		mockScreen *ebiten.Image
 		items      []ListItem
 	)
-	source, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.BebasNeueRegular_ttf))
+	style, err := NewStyle(theme.Dark())
 	Expect(err).NotTo(HaveOccurred())
 
 	BeforeEach(func() {
@@ -39,7 +36,7 @@ var _ = Describe("List", func() {
 			&MockListItem{StringValue: "Item 3"},
 		}
 
-		list = NewList(source, true, 10, 20)
+		list = NewList(style, true, 10, 20)
 		list.Items = items
 	})
 
@@ -102,7 +99,7 @@ var _ = Describe("List", func() {
 
 		BeforeEach(func() {
 			// Create a test list with viewport size 3
-			list = NewListWithViewport(source, true, 10, 20, 3)
+			list = NewListWithViewport(style, true, 10, 20, 3)
 
 			// Add test items
 			list.Items = []ListItem{
@@ -119,7 +116,7 @@ var _ = Describe("List", func() {
 
 		Describe("Initialization", func() {
 			It("should initialize with correct default values", func() {
-				list := NewList(source, true, 10, 20)
+				list := NewList(style, true, 10, 20)
 				Expect(list.Visible).To(BeTrue())
 				Expect(list.x).To(Equal(10))
 				Expect(list.y).To(Equal(20))
@@ -127,7 +124,7 @@ var _ = Describe("List", func() {
 			})
 
 			It("should initialize with custom viewport size", func() {
-				list := NewListWithViewport(source, false, 5, 15, 5)
+				list := NewListWithViewport(style, false, 5, 15, 5)
 				Expect(list.Visible).To(BeFalse())
 				Expect(list.x).To(Equal(5))
 				Expect(list.y).To(Equal(15))
@@ -226,6 +223,56 @@ var _ = Describe("List", func() {
 			})
 		})
 
+		Describe("Dragging", func() {
+			It("should scroll by whole rows with the finger", func() {
+				list.Drag(-ItemHeight / 2)
+				Expect(list.scrollPos).To(Equal(0))
+				list.Drag(-ItemHeight / 2)
+				Expect(list.scrollPos).To(Equal(1))
+				list.Drag(ItemHeight)
+				Expect(list.scrollPos).To(Equal(0))
+			})
+
+			It("should keep the dragged position until the cursor moves", func() {
+				list.Draw(mockScreen, 0)
+				list.Drag(-ItemHeight * 2)
+				list.Draw(mockScreen, 0)
+				Expect(list.scrollPos).To(Equal(2))
+
+				list.Draw(mockScreen, 1)
+				Expect(list.scrollPos).To(Equal(1))
+			})
+
+			It("should coast after the finger is lifted until it slows down", func() {
+				for range 4 {
+					list.Drag(-ItemHeight / 4)
+				}
+				start := list.scrollPos
+				list.Coast()
+				Expect(list.velocity).To(BeNumerically(">", 0))
+				for range 1000 {
+					list.Coast()
+				}
+				Expect(list.velocity).To(BeZero())
+				Expect(list.scrollPos).To(BeNumerically(">", start))
+			})
+
+			It("should stop coasting at the end of the list", func() {
+				list.Drag(-ItemHeight * 10)
+				Expect(list.scrollPos).To(Equal(3))
+				Expect(list.velocity).To(BeZero())
+			})
+
+			It("should scroll by the taller rows in touch mode", func() {
+				style.Touch = true
+				defer func() { style.Touch = false }()
+				list.Drag(-ItemHeight)
+				Expect(list.scrollPos).To(Equal(0))
+				list.Drag(-(TouchItemHeight - ItemHeight))
+				Expect(list.scrollPos).To(Equal(1))
+			})
+		})
+
 		Describe("GetVisibleRange", func() {
 			It("should return correct visible range", func() {
 				// Default position
@@ -254,6 +301,28 @@ var _ = Describe("List", func() {
 			})
 		})
 
+		Describe("SetBounds", func() {
+			It("should show more items when the viewport grows", func() {
+				list.SetBounds(10, 20, 300, 5)
+				start, end := list.GetVisibleRange()
+				Expect(start).To(Equal(0))
+				Expect(end).To(Equal(5))
+			})
+
+			It("should only take hovers inside its width", func() {
+				list.SetBounds(10, 20, 300, 3)
+				y := 20 + ItemHeight/2
+				Expect(list.GetHoverCursor(640, 100, y)).To(Equal(0))
+				Expect(list.GetHoverCursor(640, 400, y)).To(Equal(-1))
+			})
+
+			It("should show at least one item", func() {
+				list.SetBounds(10, 20, 300, 0)
+				_, end := list.GetVisibleRange()
+				Expect(end).To(Equal(1))
+			})
+		})
+
 		Describe("Draw method behavior", func() {
 			It("should not draw when list is not visible", func() {
 				list.Visible = false
@@ -325,7 +394,7 @@ var _ = Describe("List", func() {
 
 		BeforeEach(func() {
 			// Create a test list with viewport size 3
-			list = NewListWithViewport(source, true, x, y, 3)
+			list = NewListWithViewport(style, true, x, y, 3)
 
 			// Add test items
 			list.Items = []ListItem{
@@ -1,8 +1,6 @@
 package components
 
 import (
-	"image/color"
-
 	"github.com/kmdkuk/clicker/presentation/input"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -13,30 +11,21 @@ import (
 // 吹き出し型ポップアップの設定定数
 const (
 	PopupMargin       = 20  // ポップアップの余白
-	PopupPadding      = 15  // 内部パディング
 	PopupCornerRadius = 10  // 角の丸み
 	PopupMaxWidth     = 400 // 最大幅
-	PopupTextSize     = 18  // テキストサイズ
 	PopupLineHeight   = 28  // 行の高さ
 	PopupTailSize     = 15  // 吹き出しの尻尾のサイズ
 )
 
-// ポップアップの色設定
-var (
-	PopupBgColor     = color.RGBA{R: 50, G: 50, B: 70, A: 240}    // 背景色
-	PopupBorderColor = color.RGBA{R: 80, G: 80, B: 120, A: 255}   // 枠線色
-	PopupTextColor   = color.RGBA{R: 240, G: 240, B: 240, A: 255} // テキスト色
-)
-
 type Popup struct {
-	source  *text.GoTextFaceSource
+	style   *Style
 	Message string // 表示メッセージ
 	Active  bool   // アクティブ状態
 }
 
-func NewPopup(source *text.GoTextFaceSource) *Popup {
+func NewPopup(style *Style) *Popup {
 	return &Popup{
-		source:  source,
+		style:   style,
 		Message: "",
 		Active:  false,
 	}
@@ -50,14 +39,12 @@ func (p *Popup) Draw(screen *ebiten.Image) {
 	screenWidth := screen.Bounds().Dx()
 	screenHeight := screen.Bounds().Dy()
 
-	face := &text.GoTextFace{
-		Source: p.source,
-		Size:   float64(PopupTextSize),
-	}
+	face := p.style.Face(p.style.TextSizes.Popup)
+	padding := float32(p.style.Px(p.style.Padding.Popup))
 
 	// ポップアップのサイズ設定
-	popupWidth := float32(screenWidth)/2 + float32(PopupPadding*2)
-	popupHeight := float32(screenHeight/2) + float32(PopupPadding*2)
+	popupWidth := float32(screenWidth)/2 + padding*2
+	popupHeight := float32(screenHeight/2) + padding*2
 
 	// 画面中央の座標を計算
 	centerX := float32(screenWidth / 2)
@@ -73,39 +60,40 @@ func (p *Popup) Draw(screen *ebiten.Image) {
 	txtOp.PrimaryAlign = text.AlignCenter
 	txtOp.SecondaryAlign = text.AlignCenter
 	txtOp.GeoM.Translate(float64(centerX), float64(centerY))
-	txtOp.ColorScale.ScaleWithColor(PopupTextColor)
+	txtOp.ColorScale.ScaleWithColor(p.style.Colors.PopupText)
 
 	text.Draw(screen, p.Message, face, txtOp)
 
 	// クローズヒントの表示
 	hintText := "Press Enter or Space to close"
-	hintX := popupX + popupWidth - PopupPadding
-	hintY := popupY + popupHeight - PopupPadding
+	hintX := popupX + popupWidth - padding
+	hintY := popupY + popupHeight - padding
 
 	txtOp = &text.DrawOptions{}
 	txtOp.PrimaryAlign = text.AlignEnd
 	txtOp.SecondaryAlign = text.AlignEnd
 	txtOp.GeoM.Translate(float64(hintX), float64(hintY))
-	txtOp.ColorScale.ScaleWithColor(color.RGBA{R: 180, G: 180, B: 180, A: 180})
+	txtOp.ColorScale.ScaleWithColor(p.style.Colors.Hint)
 	text.Draw(screen, hintText, face, txtOp)
 }
 
 // 吹き出し背景の描画
 func (p *Popup) drawBackground(screen *ebiten.Image, x, y, width, height float32) {
+	border := float32(p.style.Px(1))
 	// メイン背景の描画
 	vector.FillRect(screen,
 		float32(x), float32(y),
 		float32(width), float32(height),
-		PopupBorderColor, false)
+		p.style.Colors.PopupBorder, false)
 	vector.FillRect(screen,
-		float32(x+1), float32(y+1),
-		float32(width-2), float32(height-2),
-		PopupBgColor, false)
+		float32(x+border), float32(y+border),
+		float32(width-border*2), float32(height-border*2),
+		p.style.Colors.PopupBg, false)
 
 }
 
 func (p *Popup) HandleInput(keyType input.KeyType, isClicked bool) {
-	if p.IsActive() && (keyType == input.KeyTypeDecision || isClicked) {
+	if p.IsActive() && (keyType == input.KeyTypeDecision || keyType == input.KeyTypeCancel || isClicked) {
 		p.Close()
 	}
 }
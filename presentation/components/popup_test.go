@@ -46,6 +46,11 @@ var _ = Describe("Popup", func() {
 			Expect(popup.Active).To(BeFalse())
 		})
 
+		It("should close popup when the cancel key or the B button is pressed", func() {
+			popup.HandleInput(input.KeyTypeCancel, false)
+			Expect(popup.Active).To(BeFalse())
+		})
+
 		It("should not close popup when non-decision keys are pressed", func() {
 			popup.HandleInput(input.KeyTypeUp, false)
 			Expect(popup.Active).To(BeTrue())
@@ -0,0 +1,69 @@
+package components
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/kmdkuk/clicker/presentation/theme"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Style is the theme the components are drawn with together with its loaded font.
+// The renderer shares one Style with all its components, so switching the theme restyles all of them.
+type Style struct {
+	theme.Theme
+	Scale  float64 // Device pixels per layout pixel, e.g. 2 on high DPI displays
+	Touch  bool    // The player uses a touch screen, so rows and tabs are taller to be easier to tap
+	source *text.GoTextFaceSource
+}
+
+func NewStyle(t theme.Theme) (*Style, error) {
+	s := &Style{Scale: 1}
+	if err := s.SetTheme(t); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetTheme switches to the theme. The style is left as it is if the font of the theme cannot be loaded.
+func (s *Style) SetTheme(t theme.Theme) error {
+	data, err := t.FontData()
+	if err != nil {
+		return err
+	}
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to load font of theme %q: %w", t.Name, err)
+	}
+	s.Theme = t
+	s.source = source
+	return nil
+}
+
+// Face returns the font of the theme in the given size, scaled to the device pixels
+func (s *Style) Face(size float64) *text.GoTextFace {
+	return &text.GoTextFace{
+		Source: s.source,
+		Size:   size * s.Scale,
+	}
+}
+
+// Px converts a length in layout pixels, like the sizes in const.go and the padding of the theme, to device pixels
+func (s *Style) Px(length int) int {
+	return int(math.Round(float64(length) * s.Scale))
+}
+
+// ItemHeight returns the height of list rows, tabs and form rows in layout pixels
+func (s *Style) ItemHeight() int {
+	if s.Touch {
+		return TouchItemHeight
+	}
+	return ItemHeight
+}
+
+// RowHeight returns ItemHeight in device pixels
+func (s *Style) RowHeight() int {
+	return s.Px(s.ItemHeight())
+}
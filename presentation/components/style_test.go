@@ -0,0 +1,33 @@
+package components
+
+import (
+	"path/filepath"
+
+	"github.com/kmdkuk/clicker/presentation/theme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Style", func() {
+	It("should load the font of every built-in theme", func() {
+		for _, t := range theme.Builtin() {
+			style, err := NewStyle(t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(style.Face(style.TextSizes.Text).Size).To(Equal(t.TextSizes.Text))
+		}
+	})
+
+	It("should keep the current theme if the font of the new one cannot be loaded", func() {
+		style, err := NewStyle(theme.Dark())
+		Expect(err).NotTo(HaveOccurred())
+
+		broken := theme.Light()
+		broken.Font = filepath.Join(GinkgoT().TempDir(), "missing.ttf")
+		Expect(style.SetTheme(broken)).NotTo(Succeed())
+		Expect(style.Name).To(Equal("Dark"))
+
+		Expect(style.SetTheme(theme.Light())).To(Succeed())
+		Expect(style.Colors).To(Equal(theme.Light().Colors))
+	})
+})
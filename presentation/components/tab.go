@@ -1,7 +1,7 @@
 package components
 
 import (
-	"image/color"
+	"github.com/kmdkuk/clicker/presentation/theme"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -9,16 +9,17 @@ import (
 )
 
 type Tab struct {
-	source     *text.GoTextFaceSource
+	style      *Style
 	titles     []string
 	x          int
 	y          int
+	width      int // 0 extends the tabs to the right edge of the screen
 	activePage int
 }
 
-func NewTab(source *text.GoTextFaceSource, items []string, defaultPage, x, y int) *Tab {
+func NewTab(style *Style, items []string, defaultPage, x, y int) *Tab {
 	return &Tab{
-		source:     source,
+		style:      style,
 		titles:     items,
 		x:          x,
 		y:          y,
@@ -26,6 +27,13 @@ func NewTab(source *text.GoTextFaceSource, items []string, defaultPage, x, y int
 	}
 }
 
+// SetBounds moves the tabs, e.g. when the window is resized
+func (t *Tab) SetBounds(x, y, width int) {
+	t.x = x
+	t.y = y
+	t.width = width
+}
+
 func (t *Tab) SetActivePage(index int) {
 	if index >= 0 && index < len(t.titles) {
 		t.activePage = index
@@ -42,46 +50,41 @@ func (t *Tab) Draw(screen *ebiten.Image) {
 		return
 	}
 
-	face := &text.GoTextFace{
-		Source: t.source,
-		Size:   float64(TextSize),
-	}
+	face := t.style.Face(t.style.TextSizes.Text)
+	colors := t.style.Colors
 
 	// 現在のX位置（水平方向に並べるため）
 	currentX := t.x
+	shift := t.style.Px(ItemVerticalShift)
 
 	// 各タブを描画
 	for i, item := range t.titles {
 		if i != 0 {
 			// 最初のタブ以外は、前のタブの右側に配置
-			currentX += ItemVerticalShift
+			currentX += shift
 		}
 		label := item
 		isSelected := i == t.activePage
 		tabWidth, tabHeight := t.getTabSize(screen.Bounds().Dx())
 
 		// 背景色を設定（選択中かホバー中かで分ける）
-		var bgColor color.RGBA
+		bgColor := colors.NormalBg
 		if isSelected {
-			bgColor = SelectedBgColor
-		} else {
-			bgColor = NormalBgColor
+			bgColor = colors.SelectedBg
 		}
 
 		// タブの背景を描画（上部に丸みをつける）
-		t.drawTabBackground(screen, currentX, t.y+ItemVerticalShift, tabWidth, tabHeight, bgColor)
+		t.drawTabBackground(screen, currentX, t.y+shift, tabWidth, tabHeight, bgColor)
 
 		// テキストの色を設定
-		var textColor color.RGBA
+		textColor := colors.NormalText
 		if isSelected {
-			textColor = SelectedTextColor
-		} else {
-			textColor = NormalTextColor
+			textColor = colors.SelectedText
 		}
 
 		// 背景矩形の中央を計算
 		rectCenterX := float64(currentX + tabWidth/2)
-		rectCenterY := float64(t.y + ItemHeight/2)
+		rectCenterY := float64(t.y + t.style.RowHeight()/2)
 
 		// テキスト描画
 		txtOp := &text.DrawOptions{}
@@ -93,12 +96,12 @@ func (t *Tab) Draw(screen *ebiten.Image) {
 		text.Draw(screen, label, face, txtOp)
 
 		// 次のタブの開始位置
-		currentX += tabWidth + ItemVerticalShift
+		currentX += tabWidth + shift
 	}
 }
 
 // drawTabBackground はタブの背景を描画します（上側の角のみ丸くする）
-func (t *Tab) drawTabBackground(screen *ebiten.Image, x, y, width, height int, bgColor color.RGBA) {
+func (t *Tab) drawTabBackground(screen *ebiten.Image, x, y, width, height int, bgColor theme.Color) {
 	// 標準の矩形描画
 	vector.FillRect(
 		screen,
@@ -112,8 +115,13 @@ func (t *Tab) drawTabBackground(screen *ebiten.Image, x, y, width, height int, b
 }
 
 func (t *Tab) getTabSize(screenWidth int) (int, int) {
-	width := ((screenWidth - (10 + ScrollbarWidth + ScrollbarMargin*2)) / len(t.titles)) - ItemVerticalShift*(len(t.titles)-1)
-	height := ItemHeight - (ItemVerticalShift * 2)
+	areaWidth := screenWidth - t.x
+	if t.width > 0 {
+		areaWidth = t.width
+	}
+	shift := t.style.Px(ItemVerticalShift)
+	width := ((areaWidth - (t.style.Px(ScrollbarWidth) + t.style.Px(ScrollbarMargin)*2)) / len(t.titles)) - shift*(len(t.titles)-1)
+	height := t.style.RowHeight() - (shift * 2)
 	return width, height
 }
 
@@ -122,15 +130,16 @@ func (t *Tab) GetHoverPage(screenWidth, mouseX, mouseY int) int {
 	tabWidth, tabHeight := t.getTabSize(screenWidth)
 
 	// 各タブをチェック
-	xOffset := 10
+	shift := t.style.Px(ItemVerticalShift)
+	xOffset := t.x
 	for i := 0; i < len(t.titles); i++ {
 		if mouseX >= xOffset && // left side
 			mouseX < xOffset+tabWidth && // right side
-			mouseY >= t.y+ItemVerticalShift && // top side
-			mouseY < t.y+tabHeight-ItemVerticalShift { // bottom side
+			mouseY >= t.y+shift && // top side
+			mouseY < t.y+tabHeight-shift { // bottom side
 			return i
 		}
-		xOffset += (tabWidth + 2*ItemVerticalShift)
+		xOffset += (tabWidth + 2*shift)
 	}
 	return -1
 }
@@ -1,19 +1,16 @@
 package components
 
 import (
-	"bytes"
-
-	"github.com/kmdkuk/clicker/assets/fonts"
+	"github.com/kmdkuk/clicker/presentation/theme"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Tab Component", func() {
 	var tab *Tab
-	source, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.BebasNeueRegular_ttf))
+	style, err := NewStyle(theme.Dark())
 	Expect(err).NotTo(HaveOccurred())
 	var (
 		x = 10
@@ -22,7 +19,7 @@ var _ = Describe("Tab Component", func() {
 
 	BeforeEach(func() {
 		// Initialize a tab with test data before each test
-		tab = NewTab(source, []string{"Buildings", "Upgrades"}, 0, x, y)
+		tab = NewTab(style, []string{"Buildings", "Upgrades"}, 0, x, y)
 	})
 
 	Context("initialization", func() {
@@ -34,7 +31,7 @@ var _ = Describe("Tab Component", func() {
 		})
 
 		It("should support initialization with non-zero default page", func() {
-			customTab := NewTab(source, []string{"Tab1", "Tab2", "Tab3"}, 1, 5, 15)
+			customTab := NewTab(style, []string{"Tab1", "Tab2", "Tab3"}, 1, 5, 15)
 			Expect(customTab.activePage).To(Equal(1))
 		})
 	})
@@ -80,7 +77,7 @@ var _ = Describe("Tab Component", func() {
 
 	Context("with multiple tabs", func() {
 		It("should handle multiple tabs correctly", func() {
-			multiTab := NewTab(source, []string{"Tab1", "Tab2", "Tab3", "Tab4"}, 0, 10, 20)
+			multiTab := NewTab(style, []string{"Tab1", "Tab2", "Tab3", "Tab4"}, 0, 10, 20)
 
 			Expect(multiTab.titles).To(HaveLen(4))
 
@@ -102,7 +99,7 @@ var _ = Describe("Tab Component", func() {
 
 		BeforeEach(func() {
 			// Initialize a Tab instance for testing
-			tab = NewTab(nil, []string{"Page 1", "Page 2", "Page 3"}, 0, x, y)
+			tab = NewTab(style, []string{"Page 1", "Page 2", "Page 3"}, 0, x, y)
 		})
 
 		It("should return the correct page index when hovering over a tab", func() {
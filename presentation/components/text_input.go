@@ -0,0 +1,88 @@
+package components
+
+import (
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const TextInputMaxLength = 32
+
+// TextInput is a single line text field for keyboard input
+type TextInput struct {
+	style  *Style
+	x      int
+	y      int
+	Prompt string
+	Value  string
+	Active bool
+}
+
+func NewTextInput(style *Style, x, y int) *TextInput {
+	return &TextInput{
+		style: style,
+		x:     x,
+		y:     y,
+	}
+}
+
+// SetPosition moves the field, e.g. when the window is resized
+func (t *TextInput) SetPosition(x, y int) {
+	t.x = x
+	t.y = y
+}
+
+// Start activates the field with the given prompt and initial value
+func (t *TextInput) Start(prompt, value string) {
+	t.Prompt = prompt
+	t.Value = value
+	t.Active = true
+}
+
+// HandleInput applies the typed characters and returns true when the input was submitted.
+// Cancelling deactivates the field without submitting.
+func (t *TextInput) HandleInput(keyType input.KeyType, chars []rune) bool {
+	if !t.Active {
+		return false
+	}
+	switch keyType {
+	case input.KeyTypeDecision:
+		t.Active = false
+		return true
+	case input.KeyTypeCancel:
+		t.Active = false
+		return false
+	case input.KeyTypeBackspace:
+		if runes := []rune(t.Value); len(runes) > 0 {
+			t.Value = string(runes[:len(runes)-1])
+		}
+		return false
+	}
+	for _, c := range chars {
+		// Enter and space also decide, so they are not part of the value
+		if c <= ' ' || c == 0x7f || len([]rune(t.Value)) >= TextInputMaxLength {
+			continue
+		}
+		t.Value += string(c)
+	}
+	return false
+}
+
+func (t *TextInput) Draw(screen *ebiten.Image) {
+	if !t.Active {
+		return
+	}
+	width := float32(screen.Bounds().Dx() - t.x*2)
+	height := float32(t.style.Px(ItemHeight) - t.style.Px(ItemVerticalShift)*2)
+	vector.FillRect(screen, float32(t.x), float32(t.y), width, height, t.style.Colors.SelectedBg, false)
+
+	face := t.style.Face(t.style.TextSizes.Text)
+	txtOp := &text.DrawOptions{}
+	txtOp.PrimaryAlign = text.AlignStart
+	txtOp.SecondaryAlign = text.AlignCenter
+	txtOp.GeoM.Translate(float64(t.x+t.style.Px(t.style.Padding.ItemText)), float64(t.y)+float64(height)/2)
+	txtOp.ColorScale.ScaleWithColor(t.style.Colors.SelectedText)
+	text.Draw(screen, t.Prompt+" "+t.Value+"_", face, txtOp)
+}
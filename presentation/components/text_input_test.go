@@ -0,0 +1,55 @@
+package components
+
+import (
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TextInput", func() {
+	var textInput *TextInput
+
+	BeforeEach(func() {
+		textInput = NewTextInput(nil, 10, 10)
+		textInput.Start("Name:", "ab")
+	})
+
+	It("should append typed characters", func() {
+		Expect(textInput.HandleInput(input.KeyTypeNone, []rune("cd"))).To(BeFalse())
+		Expect(textInput.Value).To(Equal("abcd"))
+	})
+
+	It("should ignore spaces and control characters", func() {
+		textInput.HandleInput(input.KeyTypeNone, []rune(" \nc"))
+		Expect(textInput.Value).To(Equal("abc"))
+	})
+
+	It("should delete the last character on backspace", func() {
+		textInput.HandleInput(input.KeyTypeBackspace, nil)
+		Expect(textInput.Value).To(Equal("a"))
+	})
+
+	It("should submit on decision", func() {
+		Expect(textInput.HandleInput(input.KeyTypeDecision, nil)).To(BeTrue())
+		Expect(textInput.Active).To(BeFalse())
+	})
+
+	It("should deactivate without submitting on cancel", func() {
+		Expect(textInput.HandleInput(input.KeyTypeCancel, nil)).To(BeFalse())
+		Expect(textInput.Active).To(BeFalse())
+	})
+
+	It("should limit the length", func() {
+		for i := 0; i < TextInputMaxLength*2; i++ {
+			textInput.HandleInput(input.KeyTypeNone, []rune("x"))
+		}
+		Expect(textInput.Value).To(HaveLen(TextInputMaxLength))
+	})
+
+	It("should ignore input while inactive", func() {
+		textInput.Active = false
+		Expect(textInput.HandleInput(input.KeyTypeDecision, []rune("x"))).To(BeFalse())
+		Expect(textInput.Value).To(Equal("ab"))
+	})
+})
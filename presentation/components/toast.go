@@ -0,0 +1,78 @@
+package components
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// トースト通知の設定定数
+const (
+	ToastDuration = 120 // 表示フレーム数 (60TPSで2秒)
+	ToastFadeOut  = 30  // フェードアウトにかけるフレーム数
+	ToastMargin   = 20  // 画面下端からの余白
+)
+
+// Toast shows a short message at the bottom of the screen that disappears by itself.
+// Unlike Popup it does not take the input.
+type Toast struct {
+	style     *Style
+	Message   string // 表示メッセージ
+	Remaining int    // 残り表示フレーム数
+}
+
+func NewToast(style *Style) *Toast {
+	return &Toast{
+		style: style,
+	}
+}
+
+func (t *Toast) Show(message string) {
+	t.Message = message
+	t.Remaining = ToastDuration
+}
+
+// Update counts down the frames the toast is shown for
+func (t *Toast) Update() {
+	if t.Remaining > 0 {
+		t.Remaining--
+	}
+}
+
+func (t *Toast) IsActive() bool {
+	return t.Remaining > 0
+}
+
+// alpha returns the opacity of the toast, fading out in the last frames
+func (t *Toast) alpha() float32 {
+	if t.Remaining >= ToastFadeOut {
+		return 1
+	}
+	return float32(t.Remaining) / ToastFadeOut
+}
+
+func (t *Toast) Draw(screen *ebiten.Image) {
+	if !t.IsActive() {
+		return
+	}
+
+	face := t.style.Face(t.style.TextSizes.Toast)
+	padding := float32(t.style.Px(t.style.Padding.Toast))
+	textWidth, textHeight := text.Measure(t.Message, face, 0)
+	width := float32(textWidth) + padding*2
+	height := float32(textHeight) + padding*2
+	x := float32(screen.Bounds().Dx())/2 - width/2
+	y := float32(screen.Bounds().Dy()-t.style.Px(ToastMargin)) - height
+
+	bg := t.style.Colors.ToastBg
+	bg.A = uint8(float32(bg.A) * t.alpha())
+	vector.FillRect(screen, x, y, width, height, bg, false)
+
+	txtOp := &text.DrawOptions{}
+	txtOp.PrimaryAlign = text.AlignCenter
+	txtOp.SecondaryAlign = text.AlignCenter
+	txtOp.GeoM.Translate(float64(x+width/2), float64(y+height/2))
+	txtOp.ColorScale.ScaleWithColor(t.style.Colors.ToastText)
+	txtOp.ColorScale.ScaleAlpha(t.alpha())
+	text.Draw(screen, t.Message, face, txtOp)
+}
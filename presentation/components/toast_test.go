@@ -0,0 +1,47 @@
+package components
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Toast", func() {
+	var toast *Toast
+
+	BeforeEach(func() {
+		toast = NewToast(nil)
+	})
+
+	It("should be inactive until shown", func() {
+		Expect(toast.IsActive()).To(BeFalse())
+	})
+
+	It("should hide itself after the duration", func() {
+		toast.Show("Game saved!")
+		Expect(toast.IsActive()).To(BeTrue())
+		Expect(toast.Message).To(Equal("Game saved!"))
+
+		for i := 0; i < ToastDuration-1; i++ {
+			toast.Update()
+		}
+		Expect(toast.IsActive()).To(BeTrue())
+		toast.Update()
+		Expect(toast.IsActive()).To(BeFalse())
+	})
+
+	It("should fade out at the end", func() {
+		toast.Show("Game saved!")
+		Expect(toast.alpha()).To(BeNumerically("==", 1))
+
+		toast.Remaining = ToastFadeOut / 2
+		Expect(toast.alpha()).To(BeNumerically("~", 0.5, 0.01))
+	})
+
+	It("should restart the duration when shown again", func() {
+		toast.Show("first")
+		toast.Update()
+		toast.Show("second")
+		Expect(toast.Remaining).To(Equal(ToastDuration))
+		Expect(toast.Message).To(Equal("second"))
+	})
+})
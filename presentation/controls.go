@@ -0,0 +1,134 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kmdkuk/clicker/presentation/components"
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	controlsTitle = "Key bindings"
+	controlsHint  = "[Enter] Add a key  [Delete] Remove the keys  [Esc] Back"
+)
+
+// actionLabels are the names of the actions on the key bindings screen
+var actionLabels = map[input.KeyType]string{
+	input.KeyTypeUp:       "Up",
+	input.KeyTypeDown:     "Down",
+	input.KeyTypeLeft:     "Left",
+	input.KeyTypeRight:    "Right",
+	input.KeyTypeNextTab:  "Next page",
+	input.KeyTypePrevTab:  "Previous page",
+	input.KeyTypeDecision: "Select",
+	input.KeyTypeLevelUp:  "Level up",
+	input.KeyTypeBuyMax:   "Buy max",
+	input.KeyTypeSell:     "Sell all coins",
+	input.KeyTypeSave:     "Save",
+	input.KeyTypeExport:   "Export save",
+	input.KeyTypeImport:   "Import save",
+	input.KeyTypeTheme:    "Next theme",
+	input.KeyTypeSettings: "Settings",
+	input.KeyTypeCreate:   "New slot",
+	input.KeyTypeCopy:     "Copy slot",
+	input.KeyTypeRename:   "Rename slot",
+	input.KeyTypeDelete:   "Delete slot",
+}
+
+// controlFields returns a row for each action with its keys, and the row that resets them
+func (r *DefaultRenderer) controlFields() []components.FormField {
+	fields := make([]components.FormField, 0, len(input.BindableKeyTypes)+1)
+	for _, keyType := range input.BindableKeyTypes {
+		var names []string
+		for _, key := range r.bindings.Keys(keyType) {
+			names = append(names, key.String())
+		}
+		if keyType == input.KeyTypeSave {
+			names = append(names, "Ctrl+S")
+		}
+		keys := strings.Join(names, ", ")
+		if keys == "" {
+			keys = "(none)"
+		}
+		fields = append(fields, components.FormField{Label: actionLabels[keyType], Options: []string{keys}, Button: true})
+	}
+	return append(fields, components.FormField{Label: "Reset to defaults", Button: true})
+}
+
+// openControls shows the key bindings over the settings
+func (r *DefaultRenderer) openControls() {
+	r.controlsForm.Cursor = 0
+	r.controlsForm.Show(r.controlFields())
+}
+
+// handleControlsInput handles the input on the key bindings screen
+func (r *DefaultRenderer) handleControlsInput(keyType input.KeyType, isClicked, isMouseMoved bool, mouseX, mouseY int) {
+	cursor := r.controlsForm.Cursor
+	if keyType == input.KeyTypeDelete && cursor < len(input.BindableKeyTypes) {
+		r.bindings.Clear(input.BindableKeyTypes[cursor])
+		r.saveBindings()
+		return
+	}
+	field, ok := r.controlsForm.HandleInput(keyType, isClicked, isMouseMoved, mouseX, mouseY)
+	if !ok {
+		return
+	}
+	if field == len(input.BindableKeyTypes) {
+		r.bindings.Reset()
+		r.saveBindings()
+		return
+	}
+	// 次に押されたキーを割り当てる
+	r.rebinding = input.BindableKeyTypes[field]
+	r.bindings.StartCapture()
+	r.controlsForm.Title = "Press a key for " + actionLabels[r.rebinding] + " (Esc to cancel)"
+}
+
+// handleRebinding binds the key captured for the action being rebound, once it is pressed.
+// A key bound to another action is only moved once the player agrees.
+func (r *DefaultRenderer) handleRebinding() {
+	key, ok := r.bindings.Captured()
+	if !ok {
+		return
+	}
+	keyType := r.rebinding
+	r.rebinding = input.KeyTypeNone
+	r.controlsForm.Title = controlsTitle
+
+	if key == ebiten.KeyEscape {
+		return
+	}
+	if input.IsReservedKey(key) {
+		r.ShowPopup(key.String() + " cannot be bound!")
+		return
+	}
+	if other := r.bindings.Action(key); other != input.KeyTypeNone && other != keyType {
+		question := fmt.Sprintf("%s is bound to %s. Bind it to %s instead?", key, actionLabels[other], actionLabels[keyType])
+		r.ShowDialog(question, []string{"Yes", "No"}, func(choice int) {
+			if choice == 0 {
+				r.bindKey(keyType, key)
+			}
+		})
+		return
+	}
+	r.bindKey(keyType, key)
+}
+
+func (r *DefaultRenderer) bindKey(keyType input.KeyType, key ebiten.Key) {
+	if err := r.bindings.Bind(keyType, key); err != nil {
+		r.ShowPopup("Failed to bind the key: " + err.Error())
+		return
+	}
+	r.saveBindings()
+}
+
+// saveBindings stores the key bindings in the preferences and shows them
+func (r *DefaultRenderer) saveBindings() {
+	p := *r.preferencesUseCase.GetPreferences()
+	p.KeyBindings = r.bindings.Names()
+	r.updatePreferences(p)
+	r.controlsForm.Show(r.controlFields())
+}
@@ -2,19 +2,24 @@ package presentation
 
 type Decider interface {
 	Decide(page, cursor int) (bool, string)
+	LevelUp(page, cursor int) (bool, string)
+	BuyMax(page, cursor int) (bool, string)
+	Sell() (bool, string)
 }
 
 type DefaultDecider struct {
 	ManualWorkUseCase ManualWorkUseCase
 	BuildingUseCase   BuildingUseCase
 	UpgradeUseCase    UpgradeUseCase
+	MarketUseCase     MarketUseCase
 }
 
-func NewDecider(manualWorkUseCase ManualWorkUseCase, buildingUseCase BuildingUseCase, upgradeUseCase UpgradeUseCase) Decider {
+func NewDecider(manualWorkUseCase ManualWorkUseCase, buildingUseCase BuildingUseCase, upgradeUseCase UpgradeUseCase, marketUseCase MarketUseCase) Decider {
 	return &DefaultDecider{
 		ManualWorkUseCase: manualWorkUseCase,
 		BuildingUseCase:   buildingUseCase,
 		UpgradeUseCase:    upgradeUseCase,
+		MarketUseCase:     marketUseCase,
 	}
 }
 
@@ -35,7 +40,31 @@ func (d *DefaultDecider) Decide(page, cursor int) (bool, string) {
 	case 1: // アップグレードページ
 		return d.UpgradeUseCase.PurchaseUpgradeAction(adjustedCursor)
 
+	case 2: // マーケットページ
+		return d.MarketUseCase.SellAction(adjustedCursor)
+
 	default:
 		return false, "Invalid page selection"
 	}
 }
+
+// LevelUp levels up the building under the cursor. Other rows have no level.
+func (d *DefaultDecider) LevelUp(page, cursor int) (bool, string) {
+	if page != 0 || cursor == 0 {
+		return false, ""
+	}
+	return d.BuildingUseCase.LevelUpBuildingAction(cursor - 1)
+}
+
+// BuyMax buys as many of the building under the cursor as the money allows. Other rows are not bought in bulk.
+func (d *DefaultDecider) BuyMax(page, cursor int) (bool, string) {
+	if page != 0 || cursor == 0 {
+		return false, ""
+	}
+	return d.BuildingUseCase.PurchaseMaxBuildingAction(cursor - 1)
+}
+
+// Sell sells all coins with the last market order, from any page
+func (d *DefaultDecider) Sell() (bool, string) {
+	return d.MarketUseCase.SellAction(len(d.MarketUseCase.GetMarketOrders()) - 1)
+}
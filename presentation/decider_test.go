@@ -72,6 +72,7 @@ var _ = Describe("Decider", func() {
 		manualWorkUseCase *MockManualWorkUseCase
 		buildingUseCase   *MockBuildingUseCase
 		upgradeUseCase    *MockUpgradeUseCase
+		marketUseCase     *MockMarketUseCase
 	)
 
 	BeforeEach(func() {
@@ -92,10 +93,14 @@ var _ = Describe("Decider", func() {
 			successPurchaseUpgradeAction: true,
 			messagePurchaseUpgradeAction: "",
 		}
+		marketUseCase = &MockMarketUseCase{
+			successSellAction: true,
+		}
 		decider = NewDecider(
 			manualWorkUseCase,
 			buildingUseCase,
 			upgradeUseCase,
+			marketUseCase,
 		)
 	})
 
@@ -127,8 +132,50 @@ var _ = Describe("Decider", func() {
 			Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeTrue())
 		})
 
-		It("should return false for invalid page selection", func() {
+		It("should call SellAction when page is 2 and cursor is not 0", func() {
 			success, message := decider.Decide(2, 1)
+			Expect(success).To(BeTrue())
+			Expect(message).To(Equal(""))
+			Expect(manualWorkUseCase.ManualWorkActionCalled).To(BeFalse())
+			Expect(marketUseCase.SellActionCalled).To(BeTrue())
+		})
+
+		It("should call LevelUpBuildingAction for a building", func() {
+			success, _ := decider.LevelUp(0, 2)
+			Expect(success).To(BeTrue())
+			Expect(buildingUseCase.LevelUpBuildingActionCalled).To(BeTrue())
+			Expect(buildingUseCase.levelUpIndex).To(Equal(1))
+		})
+
+		It("should not level up outside the buildings page", func() {
+			success, message := decider.LevelUp(1, 1)
+			Expect(success).To(BeFalse())
+			Expect(message).To(Equal(""))
+			success, _ = decider.LevelUp(0, 0)
+			Expect(success).To(BeFalse())
+			Expect(buildingUseCase.LevelUpBuildingActionCalled).To(BeFalse())
+		})
+
+		It("should buy the most of a building", func() {
+			success, _ := decider.BuyMax(0, 1)
+			Expect(success).To(BeTrue())
+			Expect(buildingUseCase.PurchaseMaxBuildingActionCalled).To(BeTrue())
+
+			buildingUseCase.PurchaseMaxBuildingActionCalled = false
+			success, _ = decider.BuyMax(1, 1)
+			Expect(success).To(BeFalse())
+			Expect(buildingUseCase.PurchaseMaxBuildingActionCalled).To(BeFalse())
+		})
+
+		It("should sell all coins with the last order", func() {
+			marketUseCase.orders = []dto.MarketOrder{{Name: "Sell Half"}, {Name: "Sell All"}}
+			success, _ := decider.Sell()
+			Expect(success).To(BeTrue())
+			Expect(marketUseCase.sellIndex).To(Equal(1))
+		})
+
+		It("should return false for invalid page selection", func() {
+			success, message := decider.Decide(3, 1)
 			Expect(success).To(BeFalse())
 			Expect(message).To(Equal("Invalid page selection"))
 			Expect(manualWorkUseCase.ManualWorkActionCalled).To(BeFalse())
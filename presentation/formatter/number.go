@@ -3,11 +3,22 @@ package formatter
 import (
 	"fmt"
 	"math"
+	"strings"
+
+	"github.com/kmdkuk/clicker/domain/model"
 )
 
 // 3桁ごとの単位定義
 var units = []string{"", "K", "M", "B", "T"}
 
+// numberFormat is the format of numbers from 1000 up, chosen in the settings
+var numberFormat = model.NumberFormatShort
+
+// SetNumberFormat changes how FormatLargeNumber shows numbers from 1000 up
+func SetNumberFormat(format model.NumberFormat) {
+	numberFormat = format
+}
+
 // FormatLargeNumber は大きな数値を3桁ごとの指数表記に変換します
 // 例: 1000 -> 1.00K, 1500 -> 1.50K, 1000000 -> 1.00M
 func FormatLargeNumber(value float64) string {
@@ -33,6 +44,13 @@ func FormatLargeNumber(value float64) string {
 		return fmt.Sprintf("%.0f", floor(value, 0))
 	}
 
+	switch numberFormat {
+	case model.NumberFormatScientific:
+		return formatScientific(value)
+	case model.NumberFormatFull:
+		return formatFull(value)
+	}
+
 	// 3桁ごとの指数を計算
 	exp := int(math.Floor(math.Log10(value) / 3))
 	if exp >= len(units) {
@@ -54,6 +72,32 @@ func FormatLargeNumber(value float64) string {
 	return formattedValue + units[exp]
 }
 
+// formatScientific は 1000 以上の値を仮数と指数で表します
+// 例: 1500 -> 1.50e3, 1234567 -> 1.23e6
+func formatScientific(value float64) string {
+	exp := int(math.Floor(math.Log10(value)))
+	mantissa := floor(value/math.Pow(10, float64(exp)), 2)
+	if mantissa >= 10 { // 浮動小数点の誤差で繰り上がった場合
+		mantissa /= 10
+		exp++
+	}
+	return fmt.Sprintf("%.2fe%d", mantissa, exp)
+}
+
+// formatFull は 1000 以上の値の整数部を3桁ごとにカンマで区切ります
+// 例: 1500 -> 1,500, 1234567.8 -> 1,234,567
+func formatFull(value float64) string {
+	digits := fmt.Sprintf("%.0f", math.Floor(value))
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
 // FormatCurrency は通貨値を整形します（通貨記号付き）
 func FormatCurrency(value float64, symbol string) string {
 	return symbol + " " + FormatLargeNumber(value)
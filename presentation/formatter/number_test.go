@@ -1,6 +1,8 @@
 package formatter
 
 import (
+	"github.com/kmdkuk/clicker/domain/model"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -58,6 +60,30 @@ var _ = Describe("Number Formatter", func() {
 		})
 	})
 
+	Context("Number formats", func() {
+		AfterEach(func() {
+			SetNumberFormat(model.NumberFormatShort)
+		})
+
+		It("should show numbers from 1000 up with a mantissa and an exponent in the scientific format", func() {
+			SetNumberFormat(model.NumberFormatScientific)
+			Expect(FormatLargeNumber(999)).To(Equal("999"))
+			Expect(FormatLargeNumber(1000)).To(Equal("1.00e3"))
+			Expect(FormatLargeNumber(1234567)).To(Equal("1.23e6"))
+			Expect(FormatLargeNumber(-1500)).To(Equal("-1.50e3"))
+			Expect(FormatLargeNumber(1e30)).To(Equal("1.00e30"))
+		})
+
+		It("should show all digits grouped by thousands in the full format", func() {
+			SetNumberFormat(model.NumberFormatFull)
+			Expect(FormatLargeNumber(42)).To(Equal("42.0"))
+			Expect(FormatLargeNumber(1500)).To(Equal("1,500"))
+			Expect(FormatLargeNumber(1234567.8)).To(Equal("1,234,567"))
+			Expect(FormatLargeNumber(100000)).To(Equal("100,000"))
+			Expect(FormatCurrency(-2500000, "$")).To(Equal("$ -2,500,000"))
+		})
+	})
+
 	Context("FormatCurrency", func() {
 		It("should add currency symbol to formatted numbers", func() {
 			Expect(FormatCurrency(0, "$")).To(Equal("$ 0.00"))
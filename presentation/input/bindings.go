@@ -0,0 +1,226 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BindableKeyTypes are the actions the player can bind keys to, in the order they are listed.
+// Cancel and Backspace are left out: Escape and Backspace always trigger them,
+// so that a menu can always be closed and a text can always be edited.
+var BindableKeyTypes = []KeyType{
+	KeyTypeUp,
+	KeyTypeDown,
+	KeyTypeLeft,
+	KeyTypeRight,
+	KeyTypeNextTab,
+	KeyTypePrevTab,
+	KeyTypeDecision,
+	KeyTypeLevelUp,
+	KeyTypeBuyMax,
+	KeyTypeSell,
+	KeyTypeSave,
+	KeyTypeExport,
+	KeyTypeImport,
+	KeyTypeTheme,
+	KeyTypeSettings,
+	KeyTypeCreate,
+	KeyTypeCopy,
+	KeyTypeRename,
+	KeyTypeDelete,
+}
+
+// defaultBindings are the keys of each action until the player changes them.
+// Save has no key of its own, Ctrl+S (Cmd+S on macOS) always saves.
+// The previous page has no key either: Left switches pages as well, and LB on a gamepad.
+var defaultBindings = map[KeyType][]ebiten.Key{
+	KeyTypeUp:       {ebiten.KeyArrowUp, ebiten.KeyW, ebiten.KeyK},
+	KeyTypeDown:     {ebiten.KeyArrowDown, ebiten.KeyS, ebiten.KeyJ},
+	KeyTypeLeft:     {ebiten.KeyArrowLeft, ebiten.KeyA, ebiten.KeyH},
+	KeyTypeRight:    {ebiten.KeyArrowRight, ebiten.KeyD, ebiten.KeyL},
+	KeyTypeNextTab:  {ebiten.KeyTab},
+	KeyTypeDecision: {ebiten.KeyEnter, ebiten.KeySpace},
+	KeyTypeLevelUp:  {ebiten.KeyU},
+	KeyTypeBuyMax:   {ebiten.KeyM},
+	KeyTypeSell:     {ebiten.KeyP},
+	KeyTypeExport:   {ebiten.KeyE},
+	KeyTypeImport:   {ebiten.KeyI},
+	KeyTypeTheme:    {ebiten.KeyT},
+	KeyTypeSettings: {ebiten.KeyO},
+	KeyTypeCreate:   {ebiten.KeyN},
+	KeyTypeCopy:     {ebiten.KeyC},
+	KeyTypeRename:   {ebiten.KeyR},
+	KeyTypeDelete:   {ebiten.KeyX, ebiten.KeyDelete},
+}
+
+// IsReservedKey reports whether the key cannot be bound: Escape and Backspace, which are reserved
+// for Cancel and Backspace, and the modifier keys.
+func IsReservedKey(key ebiten.Key) bool {
+	switch key {
+	case ebiten.KeyEscape, ebiten.KeyBackspace,
+		ebiten.KeyControlLeft, ebiten.KeyControlRight, ebiten.KeyShiftLeft, ebiten.KeyShiftRight,
+		ebiten.KeyAltLeft, ebiten.KeyAltRight, ebiten.KeyMetaLeft, ebiten.KeyMetaRight:
+		return true
+	}
+	return false
+}
+
+// Bindings maps the keys to the actions they trigger. Each key triggers at most one action,
+// an action can have several keys.
+// It also captures the next key pressed while the player rebinds an action.
+type Bindings struct {
+	keys map[KeyType][]ebiten.Key
+	// Key capture for rebinding
+	capturing   bool
+	captured    ebiten.Key
+	hasCaptured bool
+}
+
+func DefaultBindings() *Bindings {
+	b := &Bindings{}
+	b.Reset()
+	return b
+}
+
+// NewBindings returns the bindings stored as action names to key names, as written by Names.
+// Actions left out keep their default keys unless a stored action uses them.
+// Unknown names and keys bound twice are skipped and reported in the error, the rest is still bound.
+func NewBindings(names map[string][]string) (*Bindings, error) {
+	b := &Bindings{keys: map[KeyType][]ebiten.Key{}}
+	var errs []error
+	stored := map[KeyType][]string{}
+	for name, keyNames := range names {
+		keyType, ok := ParseKeyType(name)
+		if !ok || !slices.Contains(BindableKeyTypes, keyType) {
+			errs = append(errs, fmt.Errorf("unknown action %q", name))
+			continue
+		}
+		stored[keyType] = keyNames
+	}
+
+	// 保存された割り当てを先に、残りの操作をデフォルトで埋める
+	for _, keyType := range BindableKeyTypes {
+		keyNames, ok := stored[keyType]
+		if !ok {
+			continue
+		}
+		b.keys[keyType] = []ebiten.Key{}
+		for _, keyName := range keyNames {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(keyName)); err != nil {
+				errs = append(errs, fmt.Errorf("unknown key %q for %s", keyName, keyType))
+				continue
+			}
+			if other := b.Action(key); other != KeyTypeNone && other != keyType {
+				errs = append(errs, fmt.Errorf("%s is bound to both %s and %s", key, other, keyType))
+				continue
+			}
+			if err := b.Bind(keyType, key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for _, keyType := range BindableKeyTypes {
+		if _, ok := stored[keyType]; ok {
+			continue
+		}
+		for _, key := range defaultBindings[keyType] {
+			if b.Action(key) == KeyTypeNone {
+				b.keys[keyType] = append(b.keys[keyType], key)
+			}
+		}
+	}
+	return b, errors.Join(errs...)
+}
+
+// Action returns the action the key triggers, or KeyTypeNone if the key is not bound
+func (b *Bindings) Action(key ebiten.Key) KeyType {
+	for keyType, keys := range b.keys {
+		if slices.Contains(keys, key) {
+			return keyType
+		}
+	}
+	return KeyTypeNone
+}
+
+// Keys returns the keys bound to the action
+func (b *Bindings) Keys(keyType KeyType) []ebiten.Key {
+	return slices.Clone(b.keys[keyType])
+}
+
+// Bind adds the key to the action. A key bound to another action is moved, so callers that want
+// to ask first check Action before.
+func (b *Bindings) Bind(keyType KeyType, key ebiten.Key) error {
+	if !slices.Contains(BindableKeyTypes, keyType) {
+		return fmt.Errorf("%s cannot be bound", keyType)
+	}
+	if IsReservedKey(key) {
+		return fmt.Errorf("%s is reserved", key)
+	}
+	if current := b.Action(key); current != KeyTypeNone {
+		if current == keyType {
+			return nil
+		}
+		b.keys[current] = slices.DeleteFunc(b.keys[current], func(k ebiten.Key) bool { return k == key })
+	}
+	b.keys[keyType] = append(b.keys[keyType], key)
+	return nil
+}
+
+// Clear removes all keys of the action
+func (b *Bindings) Clear(keyType KeyType) {
+	b.keys[keyType] = []ebiten.Key{}
+}
+
+// Reset goes back to the default keys
+func (b *Bindings) Reset() {
+	b.keys = map[KeyType][]ebiten.Key{}
+	for keyType, keys := range defaultBindings {
+		b.keys[keyType] = slices.Clone(keys)
+	}
+}
+
+// Names returns the bindings as action names to key names, to be stored in the preferences
+func (b *Bindings) Names() map[string][]string {
+	names := map[string][]string{}
+	for _, keyType := range BindableKeyTypes {
+		keyNames := []string{}
+		for _, key := range b.keys[keyType] {
+			keyNames = append(keyNames, key.String())
+		}
+		names[keyType.String()] = keyNames
+	}
+	return names
+}
+
+// StartCapture makes the handler record the next key pressed instead of triggering its action
+func (b *Bindings) StartCapture() {
+	b.capturing = true
+	b.hasCaptured = false
+}
+
+func (b *Bindings) IsCapturing() bool {
+	return b.capturing
+}
+
+// Capture records the key pressed while capturing and ends the capture
+func (b *Bindings) Capture(key ebiten.Key) {
+	if !b.capturing {
+		return
+	}
+	b.capturing = false
+	b.captured = key
+	b.hasCaptured = true
+}
+
+// Captured returns the key recorded by the last capture once, and false until a key was pressed
+func (b *Bindings) Captured() (ebiten.Key, bool) {
+	if !b.hasCaptured {
+		return 0, false
+	}
+	b.hasCaptured = false
+	return b.captured, true
+}
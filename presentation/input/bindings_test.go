@@ -0,0 +1,92 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bindings", func() {
+	var bindings *Bindings
+
+	BeforeEach(func() {
+		bindings = DefaultBindings()
+	})
+
+	It("should bind the default keys", func() {
+		Expect(bindings.Action(ebiten.KeyW)).To(Equal(KeyTypeUp))
+		Expect(bindings.Action(ebiten.KeyEnter)).To(Equal(KeyTypeDecision))
+		Expect(bindings.Action(ebiten.KeyF1)).To(Equal(KeyTypeNone))
+		Expect(bindings.Keys(KeyTypeSave)).To(BeEmpty())
+	})
+
+	It("should move a key bound to another action", func() {
+		Expect(bindings.Bind(KeyTypeSave, ebiten.KeyS)).To(Succeed())
+		Expect(bindings.Action(ebiten.KeyS)).To(Equal(KeyTypeSave))
+		Expect(bindings.Keys(KeyTypeDown)).To(Equal([]ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyJ}))
+	})
+
+	It("should refuse the reserved keys", func() {
+		Expect(bindings.Bind(KeyTypeUp, ebiten.KeyEscape)).NotTo(Succeed())
+		Expect(bindings.Bind(KeyTypeUp, ebiten.KeyShiftLeft)).NotTo(Succeed())
+		Expect(bindings.Bind(KeyTypeCancel, ebiten.KeyF1)).NotTo(Succeed())
+	})
+
+	It("should clear and reset the keys", func() {
+		bindings.Clear(KeyTypeDecision)
+		Expect(bindings.Action(ebiten.KeyEnter)).To(Equal(KeyTypeNone))
+		bindings.Reset()
+		Expect(bindings.Action(ebiten.KeyEnter)).To(Equal(KeyTypeDecision))
+	})
+
+	Describe("NewBindings", func() {
+		It("should restore the names written by Names", func() {
+			Expect(bindings.Bind(KeyTypeSell, ebiten.KeyF2)).To(Succeed())
+			bindings.Clear(KeyTypeTheme)
+
+			restored, err := NewBindings(bindings.Names())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Keys(KeyTypeSell)).To(Equal([]ebiten.Key{ebiten.KeyP, ebiten.KeyF2}))
+			Expect(restored.Keys(KeyTypeTheme)).To(BeEmpty())
+			Expect(restored.Names()).To(Equal(bindings.Names()))
+		})
+
+		It("should keep the defaults of actions left out unless their keys are taken", func() {
+			restored, err := NewBindings(map[string][]string{"save": {"S"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Action(ebiten.KeyS)).To(Equal(KeyTypeSave))
+			Expect(restored.Keys(KeyTypeDown)).To(Equal([]ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyJ}))
+			Expect(restored.Keys(KeyTypeUp)).To(Equal([]ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyW, ebiten.KeyK}))
+		})
+
+		It("should skip unknown names and keys bound twice", func() {
+			restored, err := NewBindings(map[string][]string{
+				"jump": {"J"},
+				"up":   {"F1", "NoSuchKey"},
+				"down": {"F1", "F2"},
+			})
+			Expect(err).To(MatchError(ContainSubstring(`unknown action "jump"`)))
+			Expect(err).To(MatchError(ContainSubstring(`unknown key "NoSuchKey"`)))
+			Expect(err).To(MatchError(ContainSubstring("F1 is bound to both up and down")))
+			Expect(restored.Keys(KeyTypeUp)).To(Equal([]ebiten.Key{ebiten.KeyF1}))
+			Expect(restored.Keys(KeyTypeDown)).To(Equal([]ebiten.Key{ebiten.KeyF2}))
+		})
+	})
+
+	Describe("Capture", func() {
+		It("should report the captured key once", func() {
+			bindings.Capture(ebiten.KeyF1)
+			_, ok := bindings.Captured()
+			Expect(ok).To(BeFalse())
+
+			bindings.StartCapture()
+			bindings.Capture(ebiten.KeyF1)
+			Expect(bindings.IsCapturing()).To(BeFalse())
+			key, ok := bindings.Captured()
+			Expect(ok).To(BeTrue())
+			Expect(key).To(Equal(ebiten.KeyF1))
+			_, ok = bindings.Captured()
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package input
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	// RepeatDelay is how many frames Up or Down is held before it repeats (60TPSで0.4秒)
+	RepeatDelay = 24
+	// RepeatInterval is how many frames pass between the repeats while Up or Down is held
+	RepeatInterval = 6
+	// StickDeadZone is how far the left stick is tilted before it counts as a direction
+	StickDeadZone = 0.5
+)
+
+// gamepadButtons are the actions of the buttons of the standard gamepad layout
+var gamepadButtons = []struct {
+	button  ebiten.StandardGamepadButton
+	keyType KeyType
+}{
+	{ebiten.StandardGamepadButtonLeftTop, KeyTypeUp},            // D-pad
+	{ebiten.StandardGamepadButtonLeftBottom, KeyTypeDown},       // D-pad
+	{ebiten.StandardGamepadButtonLeftLeft, KeyTypeLeft},         // D-pad
+	{ebiten.StandardGamepadButtonLeftRight, KeyTypeRight},       // D-pad
+	{ebiten.StandardGamepadButtonRightBottom, KeyTypeDecision},  // A
+	{ebiten.StandardGamepadButtonRightRight, KeyTypeCancel},     // B
+	{ebiten.StandardGamepadButtonFrontTopLeft, KeyTypePrevTab},  // LB: previous page
+	{ebiten.StandardGamepadButtonFrontTopRight, KeyTypeNextTab}, // RB: next page
+	{ebiten.StandardGamepadButtonCenterRight, KeyTypeSettings},  // Start
+}
+
+// isRepeat reports whether an action held for the frames repeats in this frame
+func isRepeat(frames int) bool {
+	return frames >= RepeatDelay && (frames-RepeatDelay)%RepeatInterval == 0
+}
+
+// isRepeatable reports whether the action repeats while held, to scroll through long lists
+func isRepeatable(keyType KeyType) bool {
+	return keyType == KeyTypeUp || keyType == KeyTypeDown
+}
+
+// stickState is the direction the left stick of a gamepad is held in and for how many frames
+type stickState struct {
+	keyType KeyType
+	frames  int
+}
+
+// gamepads reads the gamepads with the standard layout. Gamepads can be connected and
+// disconnected while the game runs.
+type gamepads struct {
+	ids    []ebiten.GamepadID
+	sticks map[ebiten.GamepadID]*stickState
+}
+
+// update follows the connected gamepads and returns the actions triggered in this frame
+func (g *gamepads) update(textMode bool) []KeyType {
+	if g.sticks == nil {
+		g.sticks = map[ebiten.GamepadID]*stickState{}
+	}
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			slog.Info("Gamepad connected", "id", id, "name", ebiten.GamepadName(id))
+		} else {
+			slog.Warn("Gamepad connected without the standard layout, it is ignored", "id", id, "name", ebiten.GamepadName(id))
+		}
+	}
+	for _, id := range g.ids {
+		if inpututil.IsGamepadJustDisconnected(id) {
+			slog.Info("Gamepad disconnected", "id", id)
+			delete(g.sticks, id)
+		}
+	}
+	g.ids = ebiten.AppendGamepadIDs(g.ids[:0])
+
+	var keyTypes []KeyType
+	for _, id := range g.ids {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for _, b := range gamepadButtons {
+			// Only A and B are used while text is typed
+			if textMode && b.keyType != KeyTypeDecision && b.keyType != KeyTypeCancel {
+				continue
+			}
+			frames := inpututil.StandardGamepadButtonPressDuration(id, b.button)
+			if frames == 1 || (isRepeatable(b.keyType) && isRepeat(frames)) {
+				keyTypes = append(keyTypes, b.keyType)
+			}
+		}
+		if !textMode {
+			if keyType, ok := g.updateStick(id); ok {
+				keyTypes = append(keyTypes, keyType)
+			}
+		}
+	}
+	return keyTypes
+}
+
+// updateStick returns the direction of the left stick once when it is tilted,
+// and again while Up or Down is held
+func (g *gamepads) updateStick(id ebiten.GamepadID) (KeyType, bool) {
+	state, ok := g.sticks[id]
+	if !ok {
+		state = &stickState{keyType: KeyTypeNone}
+		g.sticks[id] = state
+	}
+	return state.update(stickDirection(
+		ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal),
+		ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical),
+	))
+}
+
+// update records the direction of this frame and returns it if it triggers the action
+func (s *stickState) update(keyType KeyType) (KeyType, bool) {
+	if keyType != s.keyType {
+		s.keyType = keyType
+		s.frames = 1
+		return keyType, keyType != KeyTypeNone
+	}
+	if keyType == KeyTypeNone {
+		return KeyTypeNone, false
+	}
+	s.frames++
+	return keyType, isRepeatable(keyType) && isRepeat(s.frames)
+}
+
+// stickDirection returns the direction the stick is tilted in the most, or KeyTypeNone within the dead zone
+func stickDirection(x, y float64) KeyType {
+	if math.Abs(x) < StickDeadZone && math.Abs(y) < StickDeadZone {
+		return KeyTypeNone
+	}
+	if math.Abs(x) > math.Abs(y) {
+		if x < 0 {
+			return KeyTypeLeft
+		}
+		return KeyTypeRight
+	}
+	// 下に倒すと正の値
+	if y < 0 {
+		return KeyTypeUp
+	}
+	return KeyTypeDown
+}
@@ -0,0 +1,75 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Gamepad", func() {
+	Describe("stickDirection", func() {
+		It("should ignore the dead zone", func() {
+			Expect(stickDirection(0.3, -0.4)).To(Equal(KeyTypeNone))
+		})
+
+		It("should return the axis tilted the most", func() {
+			Expect(stickDirection(0.2, -0.9)).To(Equal(KeyTypeUp))
+			Expect(stickDirection(0.2, 0.9)).To(Equal(KeyTypeDown))
+			Expect(stickDirection(-0.8, 0.6)).To(Equal(KeyTypeLeft))
+			Expect(stickDirection(0.8, -0.6)).To(Equal(KeyTypeRight))
+		})
+	})
+
+	Describe("stick repeat", func() {
+		It("should repeat Up and Down while the stick is held", func() {
+			state := &stickState{keyType: KeyTypeNone}
+			triggered := 0
+			for range RepeatDelay + RepeatInterval {
+				if _, ok := state.update(KeyTypeDown); ok {
+					triggered++
+				}
+			}
+			// Once when tilted, then after the delay and after one interval
+			Expect(triggered).To(Equal(3))
+
+			_, ok := state.update(KeyTypeNone)
+			Expect(ok).To(BeFalse())
+			keyType, ok := state.update(KeyTypeDown)
+			Expect(ok).To(BeTrue())
+			Expect(keyType).To(Equal(KeyTypeDown))
+		})
+
+		It("should not repeat the page switch", func() {
+			state := &stickState{keyType: KeyTypeNone}
+			triggered := 0
+			for range RepeatDelay * 2 {
+				if _, ok := state.update(KeyTypeRight); ok {
+					triggered++
+				}
+			}
+			Expect(triggered).To(Equal(1))
+		})
+	})
+
+	Describe("gamepadButtons", func() {
+		It("should switch pages with the shoulder buttons", func() {
+			actions := map[ebiten.StandardGamepadButton]KeyType{}
+			for _, b := range gamepadButtons {
+				actions[b.button] = b.keyType
+			}
+			Expect(actions[ebiten.StandardGamepadButtonFrontTopLeft]).To(Equal(KeyTypePrevTab))
+			Expect(actions[ebiten.StandardGamepadButtonFrontTopRight]).To(Equal(KeyTypeNextTab))
+		})
+	})
+
+	Describe("isRepeat", func() {
+		It("should repeat after the delay at the interval", func() {
+			Expect(isRepeat(1)).To(BeFalse())
+			Expect(isRepeat(RepeatDelay - 1)).To(BeFalse())
+			Expect(isRepeat(RepeatDelay)).To(BeTrue())
+			Expect(isRepeat(RepeatDelay + 1)).To(BeFalse())
+			Expect(isRepeat(RepeatDelay + RepeatInterval)).To(BeTrue())
+		})
+	})
+})
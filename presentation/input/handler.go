@@ -2,11 +2,15 @@ package input
 
 import (
 	"math"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
+// maxPendingKeys is how many keys or buttons pressed in the same frame are kept for the next frames
+const maxPendingKeys = 4
+
 // Handler is an interface for handling input
 type Handler interface {
 	Update()
@@ -14,52 +18,143 @@ type Handler interface {
 	IsClicked() bool
 	IsMouseMoved() bool
 	GetMouseCursor() (int, int)
+	GetInputChars() []rune
+	IsCloseRequested() bool
 	ResetClickState()
+	SetTextMode(enabled bool)
+	GetDrag() (float64, bool)
+	IsTouchUsed() bool
 }
 
 func NewHandler() Handler {
+	return NewHandlerWithBindings(DefaultBindings())
+}
+
+// NewHandlerWithBindings returns a handler that triggers the actions of the bindings.
+// The bindings are shared with the screen that rebinds them.
+func NewHandlerWithBindings(bindings *Bindings) Handler {
 	return &DefaultHandler{
-		pressedKey: ebiten.KeyMeta, // Initialize with a default key
+		pressedKey:    ebiten.KeyMeta, // Initialize with a default key
+		bindings:      bindings,
+		pressedButton: KeyTypeNone,
+		touchFrames:   EmulatedMouseFrames, // No touch yet, so the mouse is not ignored
 	}
 }
 
 // DefaultHandler is the default implementation of InputHandler
 type DefaultHandler struct {
-	pressedKey   ebiten.Key // Stores the pressed key
-	wheeldx      float64
-	wheeldy      float64
-	mouseX       int
-	mouseY       int
-	keepClicking bool
-	isClicked    bool
-	isMouseMoved bool
+	pressedKey     ebiten.Key   // Stores the pressed key
+	pendingKeys    []ebiten.Key // Bound keys pressed in the same frame as pressedKey, reported in the next frames
+	bindings       *Bindings    // Actions of the keys
+	gamepads       gamepads
+	pressedButton  KeyType   // Action of the gamepad button pressed in this frame, if no key is pressed
+	pendingButtons []KeyType // Actions of the gamepad buttons waiting for a frame without keys
+	wheeldx        float64
+	wheeldy        float64
+	mouseX         int // Position of the last mouse move or tap
+	mouseY         int
+	cursorX        int // Position of the mouse cursor when it was last seen moving
+	cursorY        int
+	keepClicking   bool
+	isClicked      bool
+	isMouseMoved   bool
+	touch          touchState
+	dragDY         float64 // Distance the finger was dragged down in this frame
+	touchFrames    int     // Frames since the last touch, the mouse is ignored shortly after one
+	isTouchUsed    bool    // The last pointer input was a touch instead of the mouse
+	inputChars     []rune  // Characters typed in this frame
+	isCtrlHeld     bool    // Ctrl (Cmd on macOS) is held, for shortcuts like Ctrl+S
+	isClosing      bool    // The window close button was pressed
+	isTextMode     bool    // Text is being typed, so only the keys that edit it trigger actions
 }
 
 // Update method to record the pressed key
 func (ih *DefaultHandler) Update() {
+	ih.isCtrlHeld = ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
 	ih.pressedKey = ebiten.KeyMeta // Initialize ebiten.Key(0) => 'A'
-	// Record the pressed key
-	for _, key := range inpututil.AppendJustPressedKeys(nil) {
-		ih.pressedKey = key
-		break // Record only the first pressed key
+	ih.pressedButton = KeyTypeNone
+	justPressed := inpututil.AppendJustPressedKeys(nil)
+	buttons := ih.gamepads.update(ih.isTextMode)
+	if ih.bindings.IsCapturing() {
+		// The key is being rebound, so it does not trigger its current action
+		ih.pendingKeys = ih.pendingKeys[:0]
+		ih.pendingButtons = ih.pendingButtons[:0]
+		if len(justPressed) > 0 {
+			ih.bindings.Capture(justPressed[0])
+		} else if slices.Contains(buttons, KeyTypeCancel) {
+			ih.bindings.Capture(ebiten.KeyEscape) // B cancels the capture like Escape
+		}
+	} else {
+		// Keys without an action do not hide the bound keys pressed with them
+		for _, key := range append(justPressed, ih.repeatedKeys()...) {
+			if ih.keyType(key) != KeyTypeNone && len(ih.pendingKeys) < maxPendingKeys {
+				ih.pendingKeys = append(ih.pendingKeys, key)
+			}
+		}
+		for _, button := range buttons {
+			if len(ih.pendingButtons) < maxPendingKeys {
+				ih.pendingButtons = append(ih.pendingButtons, button)
+			}
+		}
+		ih.nextPressed()
 	}
 	ih.wheeldx, ih.wheeldy = ebiten.Wheel()
+	ih.inputChars = ebiten.AppendInputChars(ih.inputChars[:0])
+	ih.isClosing = ebiten.IsWindowBeingClosed()
+
+	ih.updateTouch()
+	ih.updateMouse()
+}
+
+// updateTouch reports a tap as a click where the finger was lifted, and a moving finger as a drag
+func (ih *DefaultHandler) updateTouch() {
+	scale := 1.0
+	if m := ebiten.Monitor(); m != nil {
+		scale = m.DeviceScaleFactor()
+	}
+	dy, released := ih.touch.update(scale)
+	ih.dragDY = dy
+	if ih.touch.active || released {
+		ih.touchFrames = 0
+	} else if ih.touchFrames < EmulatedMouseFrames {
+		ih.touchFrames++
+	}
+	if !released {
+		return
+	}
+	if !ih.touch.dragging {
+		ih.isClicked = true
+		ih.mouseX, ih.mouseY = ih.touch.x, ih.touch.y
+	}
+	// 指を離してから切り替えて、タップした位置の項目がずれないようにする
+	ih.isTouchUsed = true
+}
 
-	mouseX, mouseY := ebiten.CursorPosition()
+// updateMouse reports a click and a move of the mouse.
+// The mouse counts as moved once the cursor is farther than the threshold from where it was last seen
+// moving, and not when a tap moved the position. Mouse events that the browser sends for a touch are ignored.
+func (ih *DefaultHandler) updateMouse() {
+	cursorX, cursorY := ebiten.CursorPosition()
+	pressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
 	ih.isMouseMoved = false
+	if ih.touch.active || ih.touchFrames < EmulatedMouseFrames {
+		ih.cursorX, ih.cursorY = cursorX, cursorY
+		ih.keepClicking = pressed
+		return
+	}
 	const mouseMoveThreshold = 10
-	if math.Abs(float64(mouseX-ih.mouseX)) > mouseMoveThreshold || math.Abs(float64(mouseY-ih.mouseY)) > mouseMoveThreshold {
+	if math.Abs(float64(cursorX-ih.cursorX)) > mouseMoveThreshold || math.Abs(float64(cursorY-ih.cursorY)) > mouseMoveThreshold {
 		ih.isMouseMoved = true
-		ih.mouseX = mouseX
-		ih.mouseY = mouseY
+		ih.isTouchUsed = false
+		ih.cursorX, ih.cursorY = cursorX, cursorY
+		ih.mouseX, ih.mouseY = cursorX, cursorY
 	}
-	if !ih.keepClicking && ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		ih.keepClicking = true
+	if !ih.keepClicking && pressed {
 		ih.isClicked = true
+		ih.isTouchUsed = false
+		ih.mouseX, ih.mouseY = cursorX, cursorY
 	}
-	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		ih.keepClicking = false
-	}
+	ih.keepClicking = pressed
 }
 
 func (ih *DefaultHandler) ResetClickState() {
@@ -76,6 +171,28 @@ func (ih *DefaultHandler) GetMouseCursor() (int, int) {
 	return ih.mouseX, ih.mouseY
 }
 
+// GetDrag returns how far a finger was dragged down in this frame in device pixels,
+// and whether a finger is on the screen
+func (ih *DefaultHandler) GetDrag() (float64, bool) {
+	return ih.dragDY, ih.touch.active
+}
+
+// IsTouchUsed reports whether the player last tapped or dragged instead of using the mouse
+func (ih *DefaultHandler) IsTouchUsed() bool {
+	return ih.isTouchUsed
+}
+
+// GetInputChars returns the characters typed in this frame for text input
+func (ih *DefaultHandler) GetInputChars() []rune {
+	return ih.inputChars
+}
+
+// IsCloseRequested reports whether the window is being closed.
+// The close is only reported when window closing is handled with ebiten.SetWindowClosingHandled.
+func (ih *DefaultHandler) IsCloseRequested() bool {
+	return ih.isClosing
+}
+
 // GetPressedKey method to classify and retrieve the pressed key
 func (ih *DefaultHandler) GetPressedKey() KeyType {
 	if ih.wheeldx > 0 {
@@ -90,18 +207,60 @@ func (ih *DefaultHandler) GetPressedKey() KeyType {
 	if ih.wheeldy < 0 {
 		return KeyTypeDown
 	}
-	switch ih.pressedKey {
-	case ebiten.KeyArrowUp, ebiten.KeyW, ebiten.KeyK:
-		return KeyTypeUp // Direction key: Up
-	case ebiten.KeyArrowDown, ebiten.KeyS, ebiten.KeyJ:
-		return KeyTypeDown // Direction key: Down
-	case ebiten.KeyArrowLeft, ebiten.KeyA, ebiten.KeyH:
-		return KeyTypeLeft // Direction key: Left
-	case ebiten.KeyArrowRight, ebiten.KeyD, ebiten.KeyL:
-		return KeyTypeRight // Direction key: Right
-	case ebiten.KeyEnter, ebiten.KeySpace:
-		return KeyTypeDecision // Decision key
-	default:
-		return KeyTypeNone // No input or other keys
+	if keyType := ih.keyType(ih.pressedKey); keyType != KeyTypeNone {
+		return keyType
+	}
+	return ih.pressedButton
+}
+
+// nextPressed takes the key, or the gamepad button if no key is waiting, to report in this frame
+func (ih *DefaultHandler) nextPressed() {
+	if len(ih.pendingKeys) > 0 {
+		ih.pressedKey = ih.pendingKeys[0]
+		ih.pendingKeys = slices.Delete(ih.pendingKeys, 0, 1)
+		return
+	}
+	if len(ih.pendingButtons) > 0 {
+		ih.pressedButton = ih.pendingButtons[0]
+		ih.pendingButtons = slices.Delete(ih.pendingButtons, 0, 1)
+	}
+}
+
+// repeatedKeys returns the keys of Up and Down that repeat in this frame because they are held
+func (ih *DefaultHandler) repeatedKeys() []ebiten.Key {
+	var keys []ebiten.Key
+	for _, keyType := range []KeyType{KeyTypeUp, KeyTypeDown} {
+		for _, key := range ih.bindings.Keys(keyType) {
+			if isRepeat(inpututil.KeyPressDuration(key)) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// SetTextMode turns the text mode on while text is typed. In text mode only Enter, Escape and
+// Backspace trigger actions, so that typing letters bound to actions does not trigger them.
+func (ih *DefaultHandler) SetTextMode(enabled bool) {
+	ih.isTextMode = enabled
+}
+
+// keyType returns the action of the key
+func (ih *DefaultHandler) keyType(key ebiten.Key) KeyType {
+	switch key {
+	case ebiten.KeyEscape:
+		return KeyTypeCancel // Cancel key, reserved
+	case ebiten.KeyBackspace:
+		return KeyTypeBackspace // Backspace key, reserved
+	}
+	if ih.isTextMode {
+		if key == ebiten.KeyEnter || key == ebiten.KeyNumpadEnter {
+			return KeyTypeDecision
+		}
+		return KeyTypeNone
+	}
+	if ih.isCtrlHeld && key == ebiten.KeyS {
+		return KeyTypeSave // Save shortcut: Ctrl+S / Cmd+S
 	}
+	return ih.bindings.Action(key)
 }
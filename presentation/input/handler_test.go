@@ -12,9 +12,7 @@ var _ = Describe("InputHandler", func() {
 	)
 
 	BeforeEach(func() {
-		handler = &DefaultHandler{
-			pressedKey: ebiten.KeyMeta, // Initialize with a default key
-		}
+		handler = NewHandler().(*DefaultHandler)
 	})
 
 	Describe("NewDefaultKeyHandler", func() {
@@ -96,10 +94,155 @@ var _ = Describe("InputHandler", func() {
 			}
 		})
 
+		It("should return the correct key type for LevelUp", func() {
+			handler.pressedKey = ebiten.KeyU
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeLevelUp))
+		})
+
+		It("should return the correct key type for Export and Import", func() {
+			handler.pressedKey = ebiten.KeyE
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeExport))
+			handler.pressedKey = ebiten.KeyI
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeImport))
+		})
+
+		It("should return the correct key type for Theme", func() {
+			handler.pressedKey = ebiten.KeyT
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeTheme))
+		})
+
+		It("should return the correct key type for Settings", func() {
+			handler.pressedKey = ebiten.KeyO
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeSettings))
+		})
+
+		It("should return the correct key types for slot management", func() {
+			handler.pressedKey = ebiten.KeyN
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeCreate))
+			handler.pressedKey = ebiten.KeyC
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeCopy))
+			handler.pressedKey = ebiten.KeyR
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeRename))
+			handler.pressedKey = ebiten.KeyDelete
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeDelete))
+			handler.pressedKey = ebiten.KeyBackspace
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeBackspace))
+			handler.pressedKey = ebiten.KeyEscape
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeCancel))
+		})
+
+		It("should return the correct key type for Save only with Ctrl held", func() {
+			handler.pressedKey = ebiten.KeyS
+			handler.isCtrlHeld = true
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeSave))
+			handler.isCtrlHeld = false
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeDown))
+		})
+
+		It("should return the correct key types for the page and market actions", func() {
+			handler.pressedKey = ebiten.KeyTab
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeNextTab))
+			handler.pressedKey = ebiten.KeyM
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeBuyMax))
+			handler.pressedKey = ebiten.KeyP
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeSell))
+		})
+
 		It("should return NONE for other keys", func() {
 			handler.pressedKey = ebiten.KeyMeta
 			keyType := handler.GetPressedKey()
 			Expect(keyType).To(Equal(KeyTypeNone))
 		})
 	})
+
+	Describe("Bindings", func() {
+		It("should follow the keys bound by the player", func() {
+			Expect(handler.bindings.Bind(KeyTypeUp, ebiten.KeyS)).To(Succeed())
+			handler.pressedKey = ebiten.KeyS
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeUp))
+		})
+
+		It("should report the keys pressed in the same frame in the next frames", func() {
+			handler.pendingKeys = []ebiten.Key{ebiten.KeyW, ebiten.KeyEnter}
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeUp))
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeDecision))
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeNone))
+		})
+
+		It("should report the gamepad buttons in frames without keys", func() {
+			handler.pendingKeys = []ebiten.Key{ebiten.KeyW}
+			handler.pendingButtons = []KeyType{KeyTypeNextTab}
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeUp))
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeNextTab))
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeNone))
+		})
+
+		It("should not trigger actions while a key is captured", func() {
+			handler.pendingKeys = []ebiten.Key{ebiten.KeyW}
+			handler.bindings.StartCapture()
+			handler.Update()
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeNone))
+			Expect(handler.bindings.IsCapturing()).To(BeTrue())
+		})
+	})
+
+	Describe("Text mode", func() {
+		BeforeEach(func() {
+			handler.SetTextMode(true)
+		})
+
+		It("should not trigger the actions of letters and space", func() {
+			for _, key := range []ebiten.Key{ebiten.KeyW, ebiten.KeyX, ebiten.KeySpace} {
+				handler.pressedKey = key
+				Expect(handler.GetPressedKey()).To(Equal(KeyTypeNone))
+			}
+		})
+
+		It("should keep the keys that edit the text", func() {
+			handler.pressedKey = ebiten.KeyEnter
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeDecision))
+			handler.pressedKey = ebiten.KeyBackspace
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeBackspace))
+			handler.pressedKey = ebiten.KeyEscape
+			Expect(handler.GetPressedKey()).To(Equal(KeyTypeCancel))
+		})
+	})
+
+	Describe("Mouse", func() {
+		// The cursor of the test is at 0, 0 and no button is pressed
+
+		It("should report a move farther than the threshold from the cursor", func() {
+			handler.cursorX = 50
+			handler.isTouchUsed = true
+			handler.updateMouse()
+			Expect(handler.IsMouseMoved()).To(BeTrue())
+			x, _ := handler.GetMouseCursor()
+			Expect(x).To(Equal(0))
+			Expect(handler.IsTouchUsed()).To(BeFalse())
+		})
+
+		It("should not take a tap for a move of the mouse", func() {
+			handler.mouseX, handler.mouseY = 300, 200 // Where the finger was lifted
+			handler.updateMouse()
+			Expect(handler.IsMouseMoved()).To(BeFalse())
+			x, y := handler.GetMouseCursor()
+			Expect([]int{x, y}).To(Equal([]int{300, 200}))
+		})
+
+		It("should ignore the mouse shortly after a touch", func() {
+			handler.touchFrames = 0
+			handler.cursorX = 50
+			handler.isTouchUsed = true
+			handler.updateMouse()
+			Expect(handler.IsMouseMoved()).To(BeFalse())
+			Expect(handler.IsTouchUsed()).To(BeTrue())
+			Expect(handler.cursorX).To(Equal(0))
+		})
+	})
 })
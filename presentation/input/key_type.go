@@ -4,10 +4,69 @@ package input
 type KeyType int
 
 const (
-	KeyTypeUp       KeyType = iota // Up
-	KeyTypeDown                    // Down
-	KeyTypeLeft                    // Left
-	KeyTypeRight                   // Right
-	KeyTypeDecision                // Decision
-	KeyTypeNone                    // No input or other keys
+	KeyTypeUp        KeyType = iota // Up
+	KeyTypeDown                     // Down
+	KeyTypeLeft                     // Left
+	KeyTypeRight                    // Right
+	KeyTypeDecision                 // Decision
+	KeyTypeLevelUp                  // Level up the selected building
+	KeyTypeExport                   // Export the save as a text string
+	KeyTypeImport                   // Import a save from a text string
+	KeyTypeCreate                   // Create a new item (e.g. a save slot)
+	KeyTypeCopy                     // Copy the selected item
+	KeyTypeRename                   // Rename the selected item
+	KeyTypeDelete                   // Delete the selected item
+	KeyTypeBackspace                // Delete the last typed character
+	KeyTypeCancel                   // Cancel the current input
+	KeyTypeSave                     // Save the game now
+	KeyTypeTheme                    // Switch to the next theme
+	KeyTypeSettings                 // Open or close the settings
+	KeyTypeNextTab                  // Switch to the next page
+	KeyTypeBuyMax                   // Buy as many of the selected building as the money allows
+	KeyTypeSell                     // Sell all coins
+	KeyTypePrevTab                  // Switch to the previous page
+	KeyTypeNone                     // No input or other keys
 )
+
+// keyTypeNames are the names of the actions in the stored key bindings
+var keyTypeNames = map[KeyType]string{
+	KeyTypeUp:        "up",
+	KeyTypeDown:      "down",
+	KeyTypeLeft:      "left",
+	KeyTypeRight:     "right",
+	KeyTypeDecision:  "decision",
+	KeyTypeLevelUp:   "level_up",
+	KeyTypeExport:    "export",
+	KeyTypeImport:    "import",
+	KeyTypeCreate:    "create",
+	KeyTypeCopy:      "copy",
+	KeyTypeRename:    "rename",
+	KeyTypeDelete:    "delete",
+	KeyTypeBackspace: "backspace",
+	KeyTypeCancel:    "cancel",
+	KeyTypeSave:      "save",
+	KeyTypeTheme:     "theme",
+	KeyTypeSettings:  "settings",
+	KeyTypeNextTab:   "next_tab",
+	KeyTypeBuyMax:    "buy_max",
+	KeyTypeSell:      "sell",
+	KeyTypePrevTab:   "prev_tab",
+	KeyTypeNone:      "none",
+}
+
+func (k KeyType) String() string {
+	if name, ok := keyTypeNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseKeyType returns the action with the name, as written by String
+func ParseKeyType(name string) (KeyType, bool) {
+	for keyType, n := range keyTypeNames {
+		if n == name {
+			return keyType, true
+		}
+	}
+	return KeyTypeNone, false
+}
@@ -0,0 +1,73 @@
+package input
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	// TapSlop is how far a finger moves in layout pixels before the touch is a drag instead of a tap
+	TapSlop = 10
+	// EmulatedMouseFrames is how many frames after a touch the mouse is ignored.
+	// Mobile browsers send mouse events for a tap, which would click and move the cursor again.
+	EmulatedMouseFrames = 30
+)
+
+// touchState follows the first finger on the screen until it is lifted.
+// Other fingers are ignored, so that a second finger does not tap while the first one drags.
+type touchState struct {
+	id       ebiten.TouchID
+	active   bool
+	startX   int
+	startY   int
+	x        int
+	y        int
+	dragging bool // The finger moved farther than TapSlop, so lifting it is not a tap
+}
+
+// update follows the finger in this frame. It returns how far the finger was dragged down
+// in device pixels and whether the finger was lifted, at x, y.
+func (t *touchState) update(scale float64) (float64, bool) {
+	if !t.active {
+		if ids := inpututil.AppendJustPressedTouchIDs(nil); len(ids) > 0 {
+			x, y := ebiten.TouchPosition(ids[0])
+			t.start(ids[0], x, y)
+		}
+		return 0, false
+	}
+	if inpututil.IsTouchJustReleased(t.id) {
+		// The position of a lifted finger is not known, so it stays where it was last seen
+		t.active = false
+		return 0, true
+	}
+	x, y := ebiten.TouchPosition(t.id)
+	return t.move(x, y, int(math.Round(TapSlop*scale))), false
+}
+
+func (t *touchState) start(id ebiten.TouchID, x, y int) {
+	*t = touchState{id: id, active: true, startX: x, startY: y, x: x, y: y}
+}
+
+// move records the position of the finger and returns how far it was dragged down since the last frame.
+// Nothing is dragged until the finger leaves the slop around where it touched the screen.
+func (t *touchState) move(x, y, slop int) float64 {
+	if !t.dragging {
+		if abs(x-t.startX) <= slop && abs(y-t.startY) <= slop {
+			return 0
+		}
+		t.dragging = true
+		t.y = t.startY // 遊びの分も含めて動かす
+	}
+	dy := y - t.y
+	t.x, t.y = x, y
+	return float64(dy)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,37 @@
+package input
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Touch", func() {
+	var touch touchState
+
+	BeforeEach(func() {
+		touch = touchState{}
+		touch.start(1, 100, 200)
+	})
+
+	It("should not drag while the finger stays within the slop", func() {
+		Expect(touch.move(105, 208, TapSlop)).To(BeZero())
+		Expect(touch.dragging).To(BeFalse())
+	})
+
+	It("should drag once the finger leaves the slop, including the slop", func() {
+		Expect(touch.move(100, 215, TapSlop)).To(Equal(15.0))
+		Expect(touch.dragging).To(BeTrue())
+		Expect(touch.move(100, 210, TapSlop)).To(Equal(-5.0))
+	})
+
+	It("should keep dragging when the finger comes back", func() {
+		touch.move(100, 230, TapSlop)
+		Expect(touch.move(100, 200, TapSlop)).To(Equal(-30.0))
+		Expect(touch.dragging).To(BeTrue())
+	})
+
+	It("should count a sideways move as a drag without scrolling", func() {
+		Expect(touch.move(130, 200, TapSlop)).To(BeZero())
+		Expect(touch.dragging).To(BeTrue())
+	})
+})
@@ -0,0 +1,86 @@
+package presentation
+
+import (
+	"math"
+
+	"github.com/kmdkuk/clicker/presentation/components"
+)
+
+// Sizes of the layout in layout pixels, scaled to device pixels with the device scale factor
+const (
+	LayoutMargin       = 10   // Space around the screen
+	SidePanelMinWidth  = 1000 // Screens at least this wide show the chart in a side panel
+	SidePanelMinPanel  = 300  // Minimum width of the side panel
+	NarrowMarketRows   = 3    // Market orders shown above the chart without a side panel
+	MinimumChartHeight = 100
+)
+
+// Rect is a part of the screen in device pixels
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// Layout places the UI on a screen of the given size, so that it reflows when the window is resized
+type Layout struct {
+	Width          int
+	Height         int
+	Scale          float64 // Device pixels per layout pixel
+	SidePanel      bool    // The chart is shown next to the lists on every page
+	Display        Rect
+	ManualWork     Rect
+	Tabs           Rect
+	List           Rect
+	Chart          Rect
+	Viewport       int // Number of list items shown at once
+	MarketViewport int // Number of market orders shown at once, fewer when the chart is below them
+}
+
+// NewLayout lays out a screen of width x height device pixels.
+// The lists grow with the height and wide screens get a side panel for the chart.
+func NewLayout(width, height int, scale float64) Layout {
+	return NewLayoutWithItemHeight(width, height, scale, components.ItemHeight)
+}
+
+// NewLayoutWithItemHeight lays out the screen with rows of itemHeight layout pixels,
+// e.g. the taller rows of the touch mode. The manual work, the tabs and the lists are stacked
+// below the money one row after another.
+func NewLayoutWithItemHeight(width, height int, scale float64, itemHeight int) Layout {
+	if scale <= 0 {
+		scale = 1
+	}
+	px := func(length int) int {
+		return int(math.Round(float64(length) * scale))
+	}
+	margin := px(LayoutMargin)
+	rowHeight := px(itemHeight)
+	displayHeight := px(components.ItemHeight) // The money is not tapped, so it keeps its height
+
+	l := Layout{
+		Width:     width,
+		Height:    height,
+		Scale:     scale,
+		SidePanel: width >= px(SidePanelMinWidth),
+	}
+	mainWidth := width - margin
+	if l.SidePanel {
+		panelWidth := max(px(SidePanelMinPanel), width*2/5)
+		mainWidth -= panelWidth
+		l.Chart = Rect{X: margin + mainWidth, Y: margin + displayHeight, Width: panelWidth}
+		l.Chart.Height = max(height-l.Chart.Y-margin, px(MinimumChartHeight))
+	}
+
+	l.Display = Rect{X: margin, Y: margin, Width: width - margin, Height: displayHeight}
+	l.ManualWork = Rect{X: margin, Y: margin + displayHeight, Width: mainWidth, Height: rowHeight}
+	l.Tabs = Rect{X: margin, Y: l.ManualWork.Y + rowHeight, Width: mainWidth, Height: rowHeight}
+	l.List = Rect{X: margin, Y: l.Tabs.Y + rowHeight, Width: mainWidth}
+	l.List.Height = max(height-l.List.Y-margin, rowHeight)
+	l.Viewport = max(l.List.Height/rowHeight, 1)
+	l.MarketViewport = l.Viewport
+
+	if !l.SidePanel {
+		l.MarketViewport = max(min(NarrowMarketRows, l.Viewport), 1)
+		l.Chart = Rect{X: margin, Y: l.List.Y + NarrowMarketRows*rowHeight + margin, Width: mainWidth}
+		l.Chart.Height = max(height-l.Chart.Y-margin, px(MinimumChartHeight))
+	}
+	return l
+}
@@ -0,0 +1,60 @@
+package presentation
+
+import (
+	"github.com/kmdkuk/clicker/presentation/components"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Layout", func() {
+	It("should keep the original positions on the default window", func() {
+		l := NewLayout(800, 600, 1)
+		Expect(l.SidePanel).To(BeFalse())
+		Expect(l.Display).To(Equal(Rect{X: 10, Y: 10, Width: 790, Height: components.ItemHeight}))
+		Expect(l.ManualWork.Y).To(Equal(50))
+		Expect(l.Tabs.Y).To(Equal(90))
+		Expect(l.List.Y).To(Equal(130))
+		Expect(l.Chart.Y).To(Equal(260))
+		Expect(l.MarketViewport).To(Equal(3))
+	})
+
+	It("should show more list items on taller windows", func() {
+		short := NewLayout(800, 600, 1)
+		tall := NewLayout(800, 1200, 1)
+		Expect(short.Viewport).To(Equal(11))
+		Expect(tall.Viewport).To(Equal(26))
+		Expect(tall.Chart.Height).To(BeNumerically(">", short.Chart.Height))
+	})
+
+	It("should show at least one item on tiny windows", func() {
+		l := NewLayout(200, 100, 1)
+		Expect(l.Viewport).To(Equal(1))
+		Expect(l.MarketViewport).To(Equal(1))
+	})
+
+	It("should move the chart to a side panel on wide windows", func() {
+		l := NewLayout(1500, 800, 1)
+		Expect(l.SidePanel).To(BeTrue())
+		Expect(l.Chart.X).To(Equal(l.List.X + l.List.Width))
+		Expect(l.Chart.X + l.Chart.Width).To(Equal(1500))
+		Expect(l.Chart.Y).To(Equal(l.ManualWork.Y))
+		Expect(l.MarketViewport).To(Equal(l.Viewport))
+	})
+
+	It("should stack taller rows for touch screens", func() {
+		l := NewLayoutWithItemHeight(800, 600, 1, components.TouchItemHeight)
+		Expect(l.Display.Height).To(Equal(components.ItemHeight))
+		Expect(l.Tabs.Y).To(Equal(l.ManualWork.Y + components.TouchItemHeight))
+		Expect(l.List.Y).To(Equal(l.Tabs.Y + components.TouchItemHeight))
+		Expect(l.Viewport).To(Equal((600 - l.List.Y - LayoutMargin) / components.TouchItemHeight))
+		Expect(l.Chart.Y).To(Equal(l.List.Y + NarrowMarketRows*components.TouchItemHeight + LayoutMargin))
+	})
+
+	It("should scale with the device scale factor", func() {
+		l := NewLayout(1600, 1200, 2)
+		Expect(l.SidePanel).To(BeFalse())
+		Expect(l.List.Y).To(Equal(260))
+		Expect(l.Viewport).To(Equal(NewLayout(800, 600, 1).Viewport))
+	})
+})
@@ -16,7 +16,7 @@ func NewNavigation(totalItems []int) *Navigation {
 	return &Navigation{
 		cursor:     0,
 		page:       0,
-		maxPages:   2, // Total number of pages (currently fixed at 2)
+		maxPages:   len(totalItems), // One page per item list
 		totalItems: totalItems,
 	}
 }
@@ -29,9 +29,9 @@ func (n *Navigation) HandleNavigation(keyType input.KeyType) {
 		n.cursor = (n.cursor - 1 + totalItems) % totalItems
 	case input.KeyTypeDown:
 		n.cursor = (n.cursor + 1) % totalItems
-	case input.KeyTypeLeft:
+	case input.KeyTypeLeft, input.KeyTypePrevTab:
 		n.page = (n.page - 1 + n.maxPages) % n.maxPages
-	case input.KeyTypeRight:
+	case input.KeyTypeRight, input.KeyTypeNextTab:
 		n.page = (n.page + 1) % n.maxPages
 	}
 
@@ -1,27 +1,31 @@
 package presentation
 
 import (
-	"bytes"
-	"image/color"
-
 	"github.com/kmdkuk/clicker/application/dto"
-	"github.com/kmdkuk/clicker/assets/fonts"
 	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/presentation/components"
+	"github.com/kmdkuk/clicker/presentation/formatter"
 	"github.com/kmdkuk/clicker/presentation/input"
+	"github.com/kmdkuk/clicker/presentation/theme"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
 type Renderer interface {
 	Update()
 	Draw(screen *ebiten.Image)
+	Resize(width, height int, scale float64)
 	HandleInput(keyType input.KeyType, isClicked, isMouseMoved bool, mouseX, mouseY int)
+	HandleDrag(dy float64, touching bool)
+	SetTouchMode(enabled bool)
 	ShowPopup(message string)
+	ShowToast(message string)
+	ShowDialog(message string, options []string, onChoose func(choice int))
 	IsPopupActive() bool
 	GetPopupMessage() string
+	TakeGameStateChange() bool
 	DebugMessage(message string)
 	GetDebugMessage() string
 }
@@ -37,6 +41,8 @@ type ManualWorkUseCase interface {
 
 type BuildingUseCase interface {
 	PurchaseBuildingAction(cursor int) (bool, string)
+	LevelUpBuildingAction(cursor int) (bool, string)
+	PurchaseMaxBuildingAction(cursor int) (bool, string)
 	GetBuildings() []dto.Building
 	GetBuildingsIsUnlockedWithMaskedNextLock() []dto.Building
 }
@@ -47,47 +53,113 @@ type UpgradeUseCase interface {
 	GetUpgradesIsReleasedCostSorted() []dto.Upgrade
 }
 
+type MarketUseCase interface {
+	SellAction(cursor int) (bool, string)
+	GetMarket() *dto.Market
+	GetMarketOrders() []dto.MarketOrder
+}
+
+type PreferencesUseCase interface {
+	GetPreferences() *dto.Preferences
+	UpdatePreferencesAction(preferences dto.Preferences) (bool, string)
+}
+
 type DefaultRenderer struct {
-	config            *config.Config
-	playerUseCase     PlayerUseCase
-	manualWorkUseCase ManualWorkUseCase
-	buildingUseCase   BuildingUseCase
-	upgradeUseCase    UpgradeUseCase
-	debugMessage      string
-	decider           Decider
-	navigation        *Navigation
+	config             *config.Config
+	playerUseCase      PlayerUseCase
+	manualWorkUseCase  ManualWorkUseCase
+	buildingUseCase    BuildingUseCase
+	upgradeUseCase     UpgradeUseCase
+	marketUseCase      MarketUseCase
+	preferencesUseCase PreferencesUseCase
+	debugMessage       string
+	decider            Decider
+	navigation         *Navigation
+	themes             []theme.Theme
+	themeIndex         int
+	customTheme        string            // Path of the custom theme file, added after the built-in themes
+	style              *components.Style // Shared by all components, so that switching the theme restyles them
+	layout             Layout
+	settings           settings
+	bindings           *input.Bindings // Shared with the input handler, which uses the keys bound here
+	rebinding          input.KeyType   // Action waiting for the key to bind, KeyTypeNone if none
+	gameStateChanged   bool            // An action changed the game state since the last TakeGameStateChange
 	// Components for rendering different parts of the UI
-	display    *components.Display
-	popup      *components.Popup
-	manualWork *components.List
-	buildings  *components.List
-	upgrades   *components.List
-	tabs       *components.Tab
+	settingsForm *components.Form
+	controlsForm *components.Form
+	display      *components.Display
+	popup        *components.Popup
+	dialog       *components.Dialog
+	onChoose     func(choice int) // Called with the choice made in the dialog
+	toast        *components.Toast
+	manualWork   *components.List
+	buildings    *components.List
+	upgrades     *components.List
+	market       *components.List
+	chart        *components.Chart
+	tabs         *components.Tab
 	// Add other components as needed
 }
 
-func NewRenderer(config *config.Config, playerUseCase PlayerUseCase, manualWorkUseCase ManualWorkUseCase, buildingUseCase BuildingUseCase, upgradeUseCase UpgradeUseCase) (Renderer, error) {
-	source, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.BebasNeueRegular_ttf))
+func NewRenderer(config *config.Config, playerUseCase PlayerUseCase, manualWorkUseCase ManualWorkUseCase, buildingUseCase BuildingUseCase, upgradeUseCase UpgradeUseCase, marketUseCase MarketUseCase, preferencesUseCase PreferencesUseCase, bindings *input.Bindings) (Renderer, error) {
+	themes, themeIndex := loadThemes(config)
+	style, themeIndex, err := newStyle(themes, themeIndex)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DefaultRenderer{
-		config:            config,
-		playerUseCase:     playerUseCase,
-		manualWorkUseCase: manualWorkUseCase,
-		buildingUseCase:   buildingUseCase,
-		upgradeUseCase:    upgradeUseCase,
-		debugMessage:      "",
-		decider:           NewDecider(manualWorkUseCase, buildingUseCase, upgradeUseCase),
-		navigation:        NewNavigation([]int{len(buildingUseCase.GetBuildings()), len(upgradeUseCase.GetUpgrades())}),
-		display:           components.NewDisplay(10, 10),
-		popup:             components.NewPopup(source),
-		manualWork:        components.NewList(source, true, 10, 50),
-		tabs:              components.NewTab(source, []string{"Buildings", "Upgrades"}, 0, 10, 90),
-		buildings:         components.NewList(source, true, 10, 130),
-		upgrades:          components.NewList(source, false, 10, 130),
-	}, nil
+	r := &DefaultRenderer{
+		config:             config,
+		playerUseCase:      playerUseCase,
+		manualWorkUseCase:  manualWorkUseCase,
+		buildingUseCase:    buildingUseCase,
+		upgradeUseCase:     upgradeUseCase,
+		marketUseCase:      marketUseCase,
+		preferencesUseCase: preferencesUseCase,
+		debugMessage:       "",
+		decider:            NewDecider(manualWorkUseCase, buildingUseCase, upgradeUseCase, marketUseCase),
+		navigation:         NewNavigation([]int{len(buildingUseCase.GetBuildings()), len(upgradeUseCase.GetUpgrades()), len(marketUseCase.GetMarketOrders())}),
+		themes:             themes,
+		themeIndex:         themeIndex,
+		customTheme:        config.Theme,
+		style:              style,
+		bindings:           bindings,
+		rebinding:          input.KeyTypeNone,
+		settingsForm:       components.NewForm(style, "Settings"),
+		controlsForm:       components.NewForm(style, controlsTitle),
+		display:            components.NewDisplay(style, 0, 0), // Placed by Resize
+		popup:              components.NewPopup(style),
+		dialog:             components.NewDialog(style),
+		toast:              components.NewToast(style),
+		manualWork:         components.NewList(style, true, 0, 0),
+		tabs:               components.NewTab(style, []string{"Buildings", "Upgrades", "Market"}, 0, 0, 0),
+		buildings:          components.NewList(style, true, 0, 0),
+		upgrades:           components.NewList(style, false, 0, 0),
+		market:             components.NewList(style, false, 0, 0),
+		chart:              components.NewChart(style, false, 0, 0, 0),
+	}
+	r.controlsForm.Hint = controlsHint
+	r.Resize(config.ScreenWidth, config.ScreenHeight, 1)
+	formatter.SetNumberFormat(model.NumberFormat(preferencesUseCase.GetPreferences().NumberFormat))
+	return r, nil
+}
+
+// Resize lays the components out for a screen of width x height device pixels
+func (r *DefaultRenderer) Resize(width, height int, scale float64) {
+	layout := NewLayoutWithItemHeight(width, height, scale, r.style.ItemHeight())
+	if layout == r.layout {
+		return
+	}
+	r.layout = layout
+	r.style.Scale = layout.Scale
+
+	r.display.SetBounds(layout.Display.X, layout.Display.Y, layout.Display.Width)
+	r.manualWork.SetBounds(layout.ManualWork.X, layout.ManualWork.Y, layout.ManualWork.Width, 1)
+	r.tabs.SetBounds(layout.Tabs.X, layout.Tabs.Y, layout.Tabs.Width)
+	r.buildings.SetBounds(layout.List.X, layout.List.Y, layout.List.Width, layout.Viewport)
+	r.upgrades.SetBounds(layout.List.X, layout.List.Y, layout.List.Width, layout.Viewport)
+	r.market.SetBounds(layout.List.X, layout.List.Y, layout.List.Width, layout.MarketViewport)
+	r.chart.SetBounds(layout.Chart.X, layout.Chart.Y, layout.Chart.Width, layout.Chart.Height)
 }
 
 func (r *DefaultRenderer) Update() {
@@ -96,15 +168,18 @@ func (r *DefaultRenderer) Update() {
 	}
 	r.buildings.Items = components.ConvertBuildingToListItems(r.buildingUseCase.GetBuildingsIsUnlockedWithMaskedNextLock())
 	r.upgrades.Items = components.ConvertUpgradeToListItems(r.upgradeUseCase.GetUpgradesIsReleasedCostSorted())
+	r.market.Items = components.ConvertMarketOrderToListItems(r.marketUseCase.GetMarketOrders())
 
 	r.navigation.totalItems = []int{
 		len(r.buildings.Items),
 		len(r.upgrades.Items),
+		len(r.market.Items),
 	}
+	r.toast.Update()
 }
 
 func (r *DefaultRenderer) Draw(screen *ebiten.Image) {
-	screen.Fill(color.RGBA{0, 0, 0, 255}) // Fill background with black
+	screen.Fill(r.style.Colors.Background)
 
 	// Draw debug information
 	if r.config.EnableDebug {
@@ -122,8 +197,22 @@ func (r *DefaultRenderer) Draw(screen *ebiten.Image) {
 
 	r.buildings.Visible = r.navigation.GetPage() == 0
 	r.upgrades.Visible = r.navigation.GetPage() == 1
+	r.market.Visible = r.navigation.GetPage() == 2
+	r.chart.Visible = r.market.Visible || r.layout.SidePanel
 	r.buildings.Draw(screen, r.navigation.GetCursor()-1)
 	r.upgrades.Draw(screen, r.navigation.GetCursor()-1)
+	r.market.Draw(screen, r.navigation.GetCursor()-1)
+	if r.chart.Visible {
+		market := r.marketUseCase.GetMarket()
+		r.chart.Draw(screen, market.String(), market.History)
+	}
+
+	r.toast.Draw(screen)
+
+	r.settingsForm.Draw(screen)
+	r.controlsForm.Draw(screen)
+
+	r.dialog.Draw(screen)
 
 	// If popup is active, only draw it and return
 	if r.popup.IsActive() {
@@ -133,11 +222,36 @@ func (r *DefaultRenderer) Draw(screen *ebiten.Image) {
 }
 
 func (r *DefaultRenderer) HandleInput(keyType input.KeyType, isClicked, isMouseMoved bool, mouseX, mouseY int) {
+	// A key is being rebound, so the input waits for the key
+	if r.rebinding != input.KeyTypeNone {
+		r.handleRebinding()
+		return
+	}
 	// Popup handling takes priority
 	if r.popup.IsActive() {
 		r.popup.HandleInput(keyType, isClicked)
 		return
 	}
+	if r.dialog.IsActive() {
+		if choice, ok := r.dialog.HandleInput(keyType, isClicked, isMouseMoved, mouseX, mouseY); ok && r.onChoose != nil {
+			r.onChoose(choice)
+		}
+		return
+	}
+	if r.controlsForm.IsActive() {
+		r.handleControlsInput(keyType, isClicked, isMouseMoved, mouseX, mouseY)
+		return
+	}
+	if r.settingsForm.IsActive() {
+		if keyType == input.KeyTypeSettings {
+			r.settingsForm.Close()
+			return
+		}
+		if field, ok := r.settingsForm.HandleInput(keyType, isClicked, isMouseMoved, mouseX, mouseY); ok {
+			r.changeSetting(field)
+		}
+		return
+	}
 
 	// Normal input handling
 	r.navigation.HandleNavigation(keyType)
@@ -152,6 +266,119 @@ func (r *DefaultRenderer) HandleInput(keyType input.KeyType, isClicked, isMouseM
 	if keyType == input.KeyTypeDecision || isClicked {
 		r.handleDecision(isClicked, mouseX, mouseY)
 	}
+
+	if keyType == input.KeyTypeLevelUp {
+		r.handleLevelUp()
+	}
+
+	if keyType == input.KeyTypeBuyMax {
+		r.handleBuyMax()
+	}
+
+	if keyType == input.KeyTypeSell {
+		r.showResult(r.decider.Sell())
+	}
+
+	if keyType == input.KeyTypeTheme {
+		r.selectTheme((r.themeIndex + 1) % len(r.themes))
+	}
+
+	if keyType == input.KeyTypeSettings {
+		r.openSettings()
+	}
+}
+
+// HandleDrag scrolls the list of the page with a finger dragged dy device pixels down.
+// Once the finger is lifted the list coasts until it stops.
+func (r *DefaultRenderer) HandleDrag(dy float64, touching bool) {
+	if r.IsPopupActive() {
+		return
+	}
+	list := r.pageList()
+	if touching {
+		list.Drag(dy)
+		return
+	}
+	list.Coast()
+}
+
+// SetTouchMode makes the rows and tabs taller while the player uses a touch screen
+func (r *DefaultRenderer) SetTouchMode(enabled bool) {
+	if r.style.Touch == enabled {
+		return
+	}
+	r.style.Touch = enabled
+	r.Resize(r.layout.Width, r.layout.Height, r.layout.Scale)
+}
+
+// pageList returns the list shown on the current page
+func (r *DefaultRenderer) pageList() *components.List {
+	switch r.navigation.GetPage() {
+	case 1:
+		return r.upgrades
+	case 2:
+		return r.market
+	}
+	return r.buildings
+}
+
+// selectTheme switches to the theme and stores it in the preferences
+func (r *DefaultRenderer) selectTheme(index int) {
+	if err := r.style.SetTheme(r.themes[index]); err != nil {
+		r.ShowPopup("Failed to switch the theme: " + err.Error())
+		if r.settingsForm.IsActive() {
+			r.settingsForm.Fields[settingTheme].Selected = r.themeIndex
+		}
+		return
+	}
+	r.themeIndex = index
+	p := *r.preferencesUseCase.GetPreferences()
+	p.Theme = r.themes[index].Name
+	if index >= len(theme.Builtin()) {
+		p.Theme = r.customTheme
+	}
+	r.updatePreferences(p)
+	r.ShowToast("Theme: " + r.themes[index].Name)
+}
+
+func (r *DefaultRenderer) handleLevelUp() {
+	page, cursor := r.navigation.GetPage(), r.navigation.GetCursor()
+	levelUp := func() {
+		r.showResult(r.decider.LevelUp(page, cursor))
+	}
+	if item := r.purchaseItem(page, cursor); page == 0 && item != "" {
+		r.confirmPurchase("Level up "+item+"?", levelUp)
+		return
+	}
+	levelUp()
+}
+
+func (r *DefaultRenderer) handleBuyMax() {
+	page, cursor := r.navigation.GetPage(), r.navigation.GetCursor()
+	buyMax := func() {
+		r.showResult(r.decider.BuyMax(page, cursor))
+	}
+	if item := r.purchaseItem(page, cursor); page == 0 && item != "" {
+		r.confirmPurchase("Buy as many "+item+" as possible?", buyMax)
+		return
+	}
+	buyMax()
+}
+
+// purchaseItem returns the building or upgrade under the cursor, or "" if the cursor is not on one.
+// Manual work and market orders are not purchases.
+func (r *DefaultRenderer) purchaseItem(page, cursor int) string {
+	var items []components.ListItem
+	switch page {
+	case 0:
+		items = r.buildings.Items
+	case 1:
+		items = r.upgrades.Items
+	}
+	if cursor == 0 || cursor > len(items) {
+		return ""
+	}
+	return items[cursor-1].String()
 }
 
 func (r *DefaultRenderer) handleDecision(isClicked bool, mouseX, mouseY int) {
@@ -169,35 +396,43 @@ func (r *DefaultRenderer) handleDecision(isClicked bool, mouseX, mouseY int) {
 		r.navigation.SetCursor(cursor)
 
 	}
-	_, message := r.decider.Decide(
-		r.navigation.GetPage(),
-		r.navigation.GetCursor(),
-	)
+	page, cursor := r.navigation.GetPage(), r.navigation.GetCursor()
+	decide := func() {
+		r.showResult(r.decider.Decide(page, cursor))
+	}
 
-	if message != "" {
-		r.ShowPopup(message)
+	if item := r.purchaseItem(page, cursor); item != "" {
+		r.confirmPurchase("Buy "+item+"?", decide)
+		return
 	}
+	decide()
 }
 
 // return page, cursor
 func (r *DefaultRenderer) detectHoverComponent(mouseX, mouseY int) (int, int) {
 	// if return -1 not hover
-	page := r.tabs.GetHoverPage(r.config.ScreenWidth, mouseX, mouseY)
+	page := r.tabs.GetHoverPage(r.layout.Width, mouseX, mouseY)
 	if page != -1 {
 		return page, -1
 	}
-	cursor := r.manualWork.GetHoverCursor(r.config.ScreenWidth, mouseX, mouseY)
+	cursor := r.manualWork.GetHoverCursor(r.layout.Width, mouseX, mouseY)
 	if cursor != -1 {
 		return -1, cursor
 	}
 	if r.buildings.Visible {
-		cursor = r.buildings.GetHoverCursor(r.config.ScreenWidth, mouseX, mouseY)
+		cursor = r.buildings.GetHoverCursor(r.layout.Width, mouseX, mouseY)
 		if cursor != -1 {
 			return -1, cursor + 1 // +1 for manual work
 		}
 	}
 	if r.upgrades.Visible {
-		cursor = r.upgrades.GetHoverCursor(r.config.ScreenWidth, mouseX, mouseY)
+		cursor = r.upgrades.GetHoverCursor(r.layout.Width, mouseX, mouseY)
+		if cursor != -1 {
+			return -1, cursor + 1 // +1 for manual work
+		}
+	}
+	if r.market.Visible {
+		cursor = r.market.GetHoverCursor(r.layout.Width, mouseX, mouseY)
 		if cursor != -1 {
 			return -1, cursor + 1 // +1 for manual work
 		}
@@ -210,8 +445,40 @@ func (r *DefaultRenderer) ShowPopup(message string) {
 	r.popup.Show(message)
 }
 
+// showResult notes whether an action changed the game state and shows its message
+func (r *DefaultRenderer) showResult(changed bool, message string) {
+	r.gameStateChanged = r.gameStateChanged || changed
+	if message != "" {
+		r.ShowPopup(message)
+	}
+}
+
+// TakeGameStateChange reports whether an action changed the game state since the last call
+func (r *DefaultRenderer) TakeGameStateChange() bool {
+	changed := r.gameStateChanged
+	r.gameStateChanged = false
+	return changed
+}
+
+// ShowToast shows a message that disappears by itself without blocking the input
+func (r *DefaultRenderer) ShowToast(message string) {
+	r.toast.Show(message)
+}
+
+// ShowDialog shows the message with the options and calls onChoose with the index of the chosen one
+func (r *DefaultRenderer) ShowDialog(message string, options []string, onChoose func(choice int)) {
+	r.onChoose = onChoose
+	r.dialog.Show(message, options)
+}
+
+// GetDialogOptionPosition returns the middle of the option of the dialog as last drawn (for testing)
+func (r *DefaultRenderer) GetDialogOptionPosition(option int) (int, int) {
+	return r.dialog.GetOptionPosition(option)
+}
+
+// IsPopupActive reports whether a popup, a dialog or the settings take the input
 func (r *DefaultRenderer) IsPopupActive() bool {
-	return r.popup.IsActive()
+	return r.popup.IsActive() || r.dialog.IsActive() || r.settingsForm.IsActive()
 }
 
 func (r *DefaultRenderer) GetPopupMessage() string {
@@ -1,9 +1,15 @@
 package presentation
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+
 	"github.com/kmdkuk/clicker/application/dto"
 	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/domain/model"
 	"github.com/kmdkuk/clicker/presentation/components"
+	"github.com/kmdkuk/clicker/presentation/formatter"
 	"github.com/kmdkuk/clicker/presentation/input"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -32,10 +38,13 @@ func (m *MockManualWorkUseCase) GetManualWork() *dto.ManualWork {
 }
 
 type MockBuildingUseCase struct {
-	PurchaseBuildingActionCalled  bool
-	buildings                     []dto.Building
-	successPurchaseBuildingAction bool
-	messagePurchaseBuildingAction string
+	PurchaseBuildingActionCalled    bool
+	LevelUpBuildingActionCalled     bool
+	PurchaseMaxBuildingActionCalled bool
+	levelUpIndex                    int
+	buildings                       []dto.Building
+	successPurchaseBuildingAction   bool
+	messagePurchaseBuildingAction   string
 }
 
 func (m *MockBuildingUseCase) GetBuildings() []dto.Building {
@@ -48,6 +57,15 @@ func (m *MockBuildingUseCase) PurchaseBuildingAction(index int) (bool, string) {
 	m.PurchaseBuildingActionCalled = true
 	return m.successPurchaseBuildingAction, m.messagePurchaseBuildingAction
 }
+func (m *MockBuildingUseCase) PurchaseMaxBuildingAction(index int) (bool, string) {
+	m.PurchaseMaxBuildingActionCalled = true
+	return m.successPurchaseBuildingAction, m.messagePurchaseBuildingAction
+}
+func (m *MockBuildingUseCase) LevelUpBuildingAction(index int) (bool, string) {
+	m.LevelUpBuildingActionCalled = true
+	m.levelUpIndex = index
+	return true, "Building leveled up!"
+}
 
 type MockUpgradeUseCase struct {
 	PurchaseUpgradeActionCalled  bool
@@ -67,15 +85,54 @@ func (m *MockUpgradeUseCase) PurchaseUpgradeAction(index int) (bool, string) {
 	return m.successPurchaseUpgradeAction, m.messagePurchaseUpgradeAction
 }
 
+type MockMarketUseCase struct {
+	SellActionCalled  bool
+	sellIndex         int
+	market            *dto.Market
+	orders            []dto.MarketOrder
+	successSellAction bool
+	messageSellAction string
+}
+
+func (m *MockMarketUseCase) GetMarket() *dto.Market {
+	return m.market
+}
+func (m *MockMarketUseCase) GetMarketOrders() []dto.MarketOrder {
+	return m.orders
+}
+func (m *MockMarketUseCase) SellAction(index int) (bool, string) {
+	m.SellActionCalled = true
+	m.sellIndex = index
+	return m.successSellAction, m.messageSellAction
+}
+
+type MockPreferencesUseCase struct {
+	preferences                    dto.Preferences
+	successUpdatePreferencesAction bool
+	messageUpdatePreferencesAction string
+}
+
+func (m *MockPreferencesUseCase) GetPreferences() *dto.Preferences {
+	p := m.preferences
+	return &p
+}
+func (m *MockPreferencesUseCase) UpdatePreferencesAction(preferences dto.Preferences) (bool, string) {
+	m.preferences = preferences
+	return m.successUpdatePreferencesAction, m.messageUpdatePreferencesAction
+}
+
 var _ = Describe("Renderer", func() {
 	var (
-		renderer          *DefaultRenderer
-		testConfig        *config.Config
-		mockScreen        *ebiten.Image
-		playerUseCase     *MockPlayerUseCase
-		manualWorkUseCase *MockManualWorkUseCase
-		buildingUseCase   *MockBuildingUseCase
-		upgradeUseCase    *MockUpgradeUseCase
+		renderer           *DefaultRenderer
+		testConfig         *config.Config
+		mockScreen         *ebiten.Image
+		playerUseCase      *MockPlayerUseCase
+		manualWorkUseCase  *MockManualWorkUseCase
+		buildingUseCase    *MockBuildingUseCase
+		upgradeUseCase     *MockUpgradeUseCase
+		marketUseCase      *MockMarketUseCase
+		preferencesUseCase *MockPreferencesUseCase
+		bindings           *input.Bindings
 	)
 
 	BeforeEach(func() {
@@ -117,12 +174,39 @@ var _ = Describe("Renderer", func() {
 			messagePurchaseUpgradeAction: "",
 		}
 
+		marketUseCase = &MockMarketUseCase{
+			market: &dto.Market{
+				Price:   1.5,
+				Coins:   3,
+				History: []float64{1.0, 1.2, 1.5},
+			},
+			orders: []dto.MarketOrder{
+				{Name: "Sell Half", Amount: 1.5, Value: 2.25},
+				{Name: "Sell All", Amount: 3, Value: 4.5},
+			},
+			successSellAction: true,
+		}
+
+		preferencesUseCase = &MockPreferencesUseCase{
+			preferences: dto.Preferences{
+				AutoSaveInterval: config.DefaultAutoSaveInterval,
+				NumberFormat:     string(model.NumberFormatShort),
+				SoundVolume:      model.MaxSoundVolume,
+			},
+			successUpdatePreferencesAction: true,
+		}
+
+		bindings = input.DefaultBindings()
+
 		// Create Renderer
 		r, err := NewRenderer(testConfig,
 			playerUseCase,
 			manualWorkUseCase,
 			buildingUseCase,
 			upgradeUseCase,
+			marketUseCase,
+			preferencesUseCase,
+			bindings,
 		)
 		Expect(err).NotTo(HaveOccurred())
 		renderer = r.(*DefaultRenderer)
@@ -165,6 +249,389 @@ var _ = Describe("Renderer", func() {
 		})
 	})
 
+	Describe("Toast", func() {
+		It("should not block the input", func() {
+			renderer.ShowToast("Game saved!")
+			Expect(renderer.IsPopupActive()).To(BeFalse())
+			Expect(func() {
+				renderer.Update()
+				renderer.Draw(mockScreen)
+			}).NotTo(Panic())
+		})
+	})
+
+	Describe("Resize", func() {
+		It("should reflow the lists and show the chart on every page of a wide screen", func() {
+			renderer.Resize(1600, 900, 1)
+			Expect(renderer.layout.SidePanel).To(BeTrue())
+
+			renderer.Update()
+			renderer.Draw(ebiten.NewImage(1600, 900))
+			Expect(renderer.navigation.GetPage()).To(Equal(0))
+			Expect(renderer.chart.Visible).To(BeTrue())
+
+			// The click on the second building follows the list to its new width
+			renderer.handleDecision(true, 900, 130+components.ItemHeight+components.ItemHeight/2)
+			Expect(renderer.navigation.GetCursor()).To(Equal(2))
+		})
+
+		It("should scale the text with the device scale factor", func() {
+			renderer.Resize(1280, 960, 2)
+			Expect(renderer.style.Scale).To(Equal(2.0))
+			Expect(renderer.style.Face(renderer.style.TextSizes.Text).Size).To(Equal(renderer.style.TextSizes.Text * 2))
+			Expect(func() {
+				renderer.Draw(ebiten.NewImage(1280, 960))
+			}).NotTo(Panic())
+		})
+	})
+
+	Describe("Touch", func() {
+		BeforeEach(func() {
+			buildingUseCase.buildings = make([]dto.Building, 20)
+			renderer.Resize(640, 300, 1) // 4 buildings at once
+			renderer.Update()
+		})
+
+		It("should scroll the list of the page with a drag and let it coast", func() {
+			renderer.HandleDrag(-components.ItemHeight*2, true)
+			start, _ := renderer.buildings.GetVisibleRange()
+			Expect(start).To(Equal(2))
+
+			for range 60 {
+				renderer.HandleDrag(0, false)
+			}
+			coasted, _ := renderer.buildings.GetVisibleRange()
+			Expect(coasted).To(BeNumerically(">", start))
+		})
+
+		It("should not drag the list while the settings are open", func() {
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			renderer.HandleDrag(-components.ItemHeight*2, true)
+			start, _ := renderer.buildings.GetVisibleRange()
+			Expect(start).To(Equal(0))
+		})
+
+		It("should make the rows taller in touch mode", func() {
+			viewport := renderer.layout.Viewport
+			renderer.SetTouchMode(true)
+			Expect(renderer.layout.Tabs.Height).To(Equal(components.TouchItemHeight))
+			Expect(renderer.layout.Viewport).To(BeNumerically("<", viewport))
+
+			// The tap on the second building hits the taller row
+			renderer.handleDecision(true, 100, renderer.layout.List.Y+components.TouchItemHeight+components.TouchItemHeight/2)
+			Expect(renderer.navigation.GetCursor()).To(Equal(2))
+
+			renderer.SetTouchMode(false)
+			Expect(renderer.layout.Viewport).To(Equal(viewport))
+		})
+	})
+
+	Describe("Theme", func() {
+		It("should start with the default theme", func() {
+			Expect(renderer.style.Name).To(Equal("Dark"))
+		})
+
+		It("should cycle through the built-in themes and remember the choice", func() {
+			renderer.HandleInput(input.KeyTypeTheme, false, false, 0, 0)
+			Expect(renderer.style.Name).To(Equal("Light"))
+			Expect(preferencesUseCase.preferences.Theme).To(Equal("Light"))
+			Expect(renderer.IsPopupActive()).To(BeFalse())
+
+			renderer.HandleInput(input.KeyTypeTheme, false, false, 0, 0)
+			Expect(renderer.style.Name).To(Equal("High Contrast"))
+			renderer.HandleInput(input.KeyTypeTheme, false, false, 0, 0)
+			Expect(renderer.style.Name).To(Equal("Dark"))
+			Expect(func() {
+				renderer.Draw(mockScreen)
+			}).NotTo(Panic())
+		})
+
+		It("should start with the theme of the config", func() {
+			testConfig.Theme = "high-contrast"
+			r, err := NewRenderer(testConfig, playerUseCase, manualWorkUseCase, buildingUseCase, upgradeUseCase, marketUseCase, preferencesUseCase, bindings)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.(*DefaultRenderer).style.Name).To(Equal("High Contrast"))
+		})
+
+		It("should add a custom theme from a file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "solarized.json")
+			Expect(os.WriteFile(path, []byte(`{"name": "Solarized", "base": "light", "colors": {"background": "#fdf6e3"}}`), 0o644)).To(Succeed())
+			testConfig.Theme = path
+			r, err := NewRenderer(testConfig, playerUseCase, manualWorkUseCase, buildingUseCase, upgradeUseCase, marketUseCase, preferencesUseCase, bindings)
+			Expect(err).NotTo(HaveOccurred())
+			custom := r.(*DefaultRenderer)
+			Expect(custom.style.Name).To(Equal("Solarized"))
+			Expect(custom.themes).To(HaveLen(4))
+
+			custom.HandleInput(input.KeyTypeTheme, false, false, 0, 0)
+			Expect(custom.style.Name).To(Equal("Dark"))
+			for range 3 {
+				custom.HandleInput(input.KeyTypeTheme, false, false, 0, 0)
+			}
+			Expect(custom.style.Name).To(Equal("Solarized"))
+			Expect(preferencesUseCase.preferences.Theme).To(Equal(path))
+		})
+
+		It("should fall back to the default theme if the theme file cannot be loaded", func() {
+			testConfig.Theme = filepath.Join(GinkgoT().TempDir(), "missing.json")
+			r, err := NewRenderer(testConfig, playerUseCase, manualWorkUseCase, buildingUseCase, upgradeUseCase, marketUseCase, preferencesUseCase, bindings)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.(*DefaultRenderer).style.Name).To(Equal("Dark"))
+		})
+	})
+
+	Describe("Settings", func() {
+		AfterEach(func() {
+			formatter.SetNumberFormat(model.NumberFormatShort)
+		})
+
+		It("should open and close with the settings key and take the input meanwhile", func() {
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			Expect(renderer.settingsForm.IsActive()).To(BeTrue())
+			Expect(renderer.IsPopupActive()).To(BeTrue())
+			Expect(renderer.settingsForm.Fields[settingAutoSave].Value()).To(Equal("Every 30 sec"))
+
+			renderer.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+			Expect(renderer.navigation.GetCursor()).To(Equal(0))
+			Expect(func() {
+				renderer.Draw(mockScreen)
+			}).NotTo(Panic())
+
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			Expect(renderer.IsPopupActive()).To(BeFalse())
+		})
+
+		It("should store and apply a changed number format", func() {
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeRight, false, false, 0, 0)
+			Expect(preferencesUseCase.preferences.NumberFormat).To(Equal(string(model.NumberFormatScientific)))
+			Expect(formatter.FormatLargeNumber(1234567)).To(Equal("1.23e6"))
+			Expect(renderer.popup.IsActive()).To(BeFalse())
+		})
+
+		It("should switch the theme from the settings", func() {
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			renderer.settingsForm.Cursor = settingTheme
+			renderer.HandleInput(input.KeyTypeRight, false, false, 0, 0)
+			Expect(renderer.style.Name).To(Equal("Light"))
+			Expect(preferencesUseCase.preferences.Theme).To(Equal("Light"))
+		})
+
+		It("should show the message if the settings cannot be saved", func() {
+			preferencesUseCase.successUpdatePreferencesAction = false
+			preferencesUseCase.messageUpdatePreferencesAction = "Failed to save settings! They apply until the game is closed."
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			renderer.settingsForm.Cursor = settingConfirmPurchases
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(preferencesUseCase.preferences.ConfirmPurchases).To(BeTrue())
+			Expect(renderer.GetPopupMessage()).To(Equal("Failed to save settings! They apply until the game is closed."))
+		})
+
+		Context("when purchases are confirmed", func() {
+			BeforeEach(func() {
+				preferencesUseCase.preferences.ConfirmPurchases = true
+				renderer.Update()
+				renderer.navigation.SetPage(1)
+				renderer.navigation.SetCursor(1)
+			})
+
+			It("should buy once the player says yes", func() {
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				Expect(renderer.IsPopupActive()).To(BeTrue())
+				Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeFalse())
+
+				renderer.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeTrue())
+			})
+
+			It("should not buy if the player says no, which is chosen at first", func() {
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeFalse())
+				Expect(renderer.IsPopupActive()).To(BeFalse())
+			})
+
+			It("should not buy if the player cancels", func() {
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				renderer.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+				renderer.HandleInput(input.KeyTypeCancel, false, false, 0, 0)
+				Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeFalse())
+				Expect(renderer.IsPopupActive()).To(BeFalse())
+			})
+
+			It("should not buy if the player clicks No", func() {
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				renderer.Draw(mockScreen)
+				x, y := renderer.GetDialogOptionPosition(1)
+				renderer.HandleInput(input.KeyTypeNone, true, false, x, y)
+				Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeFalse())
+				Expect(renderer.IsPopupActive()).To(BeFalse())
+			})
+
+			It("should buy if the player clicks Yes", func() {
+				renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+				renderer.Draw(mockScreen)
+				x, y := renderer.GetDialogOptionPosition(0)
+				renderer.HandleInput(input.KeyTypeNone, true, false, x, y)
+				Expect(upgradeUseCase.PurchaseUpgradeActionCalled).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Key bindings", func() {
+		saveRow := slices.Index(input.BindableKeyTypes, input.KeyTypeSave)
+
+		BeforeEach(func() {
+			renderer.HandleInput(input.KeyTypeSettings, false, false, 0, 0)
+			renderer.settingsForm.Cursor = settingKeyBindings
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(renderer.controlsForm.IsActive()).To(BeTrue())
+			renderer.controlsForm.Cursor = saveRow
+		})
+
+		It("should bind the next key pressed and store it", func() {
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(bindings.IsCapturing()).To(BeTrue())
+			Expect(func() {
+				renderer.Draw(mockScreen)
+			}).NotTo(Panic())
+
+			bindings.Capture(ebiten.KeyF5)
+			renderer.HandleInput(input.KeyTypeNone, false, false, 0, 0)
+			Expect(bindings.Action(ebiten.KeyF5)).To(Equal(input.KeyTypeSave))
+			Expect(preferencesUseCase.preferences.KeyBindings["save"]).To(Equal([]string{"F5"}))
+			Expect(renderer.controlsForm.Fields[saveRow].Value()).To(Equal("F5, Ctrl+S"))
+		})
+
+		It("should ask before moving a key bound to another action", func() {
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			bindings.Capture(ebiten.KeyW)
+			renderer.HandleInput(input.KeyTypeNone, false, false, 0, 0)
+			Expect(renderer.dialog.IsActive()).To(BeTrue())
+			Expect(bindings.Action(ebiten.KeyW)).To(Equal(input.KeyTypeUp))
+
+			renderer.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(bindings.Action(ebiten.KeyW)).To(Equal(input.KeyTypeSave))
+		})
+
+		It("should cancel with Escape and refuse reserved keys", func() {
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			bindings.Capture(ebiten.KeyEscape)
+			renderer.HandleInput(input.KeyTypeNone, false, false, 0, 0)
+			Expect(renderer.controlsForm.Title).To(Equal(controlsTitle))
+			Expect(renderer.IsPopupActive()).To(BeTrue())
+			Expect(renderer.popup.IsActive()).To(BeFalse())
+
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			bindings.Capture(ebiten.KeyShiftLeft)
+			renderer.HandleInput(input.KeyTypeNone, false, false, 0, 0)
+			Expect(renderer.GetPopupMessage()).To(Equal("ShiftLeft cannot be bound!"))
+			Expect(bindings.Keys(input.KeyTypeSave)).To(BeEmpty())
+		})
+
+		It("should remove the keys of an action and reset them", func() {
+			renderer.controlsForm.Cursor = 0
+			renderer.HandleInput(input.KeyTypeDelete, false, false, 0, 0)
+			Expect(bindings.Keys(input.KeyTypeUp)).To(BeEmpty())
+			Expect(renderer.controlsForm.Fields[0].Value()).To(Equal("(none)"))
+
+			renderer.controlsForm.Cursor = len(input.BindableKeyTypes)
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(bindings.Action(ebiten.KeyW)).To(Equal(input.KeyTypeUp))
+		})
+
+		It("should go back to the settings with Escape", func() {
+			renderer.HandleInput(input.KeyTypeCancel, false, false, 0, 0)
+			Expect(renderer.controlsForm.IsActive()).To(BeFalse())
+			Expect(renderer.settingsForm.IsActive()).To(BeTrue())
+		})
+	})
+
+	Describe("Actions", func() {
+		BeforeEach(func() {
+			renderer.Update()
+		})
+
+		It("should switch to the next page", func() {
+			renderer.HandleInput(input.KeyTypeNextTab, false, false, 0, 0)
+			Expect(renderer.navigation.GetPage()).To(Equal(1))
+		})
+
+		It("should switch to the previous page and wrap around", func() {
+			renderer.HandleInput(input.KeyTypePrevTab, false, false, 0, 0)
+			Expect(renderer.navigation.GetPage()).To(Equal(2))
+			renderer.HandleInput(input.KeyTypePrevTab, false, false, 0, 0)
+			Expect(renderer.navigation.GetPage()).To(Equal(1))
+		})
+
+		It("should buy the most of the selected building", func() {
+			renderer.navigation.SetCursor(1)
+			renderer.HandleInput(input.KeyTypeBuyMax, false, false, 0, 0)
+			Expect(buildingUseCase.PurchaseMaxBuildingActionCalled).To(BeTrue())
+		})
+
+		It("should sell all coins from any page", func() {
+			renderer.HandleInput(input.KeyTypeSell, false, false, 0, 0)
+			Expect(marketUseCase.SellActionCalled).To(BeTrue())
+			Expect(marketUseCase.sellIndex).To(Equal(len(marketUseCase.orders) - 1))
+		})
+
+		It("should report an action that changed the game state once", func() {
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(manualWorkUseCase.ManualWorkActionCalled).To(BeTrue())
+			Expect(renderer.TakeGameStateChange()).To(BeTrue())
+			Expect(renderer.TakeGameStateChange()).To(BeFalse())
+		})
+
+		It("should not report a failed purchase or a click on nothing", func() {
+			renderer.navigation.SetCursor(1)
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(buildingUseCase.PurchaseBuildingActionCalled).To(BeTrue())
+			renderer.HandleInput(input.KeyTypeNone, true, false, -1, -1)
+			Expect(renderer.TakeGameStateChange()).To(BeFalse())
+		})
+	})
+
+	Describe("Dialog", func() {
+		It("should take the input until a choice is made", func() {
+			chosen := -1
+			renderer.ShowDialog("Your save could not be loaded.", []string{"Keep this game", "Restore backup 2"}, func(choice int) {
+				chosen = choice
+			})
+			Expect(renderer.IsPopupActive()).To(BeTrue())
+			initialPage := renderer.navigation.GetPage()
+
+			renderer.HandleInput(input.KeyTypeRight, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeUp, false, false, 0, 0)
+			Expect(renderer.navigation.GetPage()).To(Equal(initialPage))
+			Expect(chosen).To(Equal(-1))
+
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(chosen).To(Equal(0))
+			Expect(renderer.IsPopupActive()).To(BeFalse())
+		})
+
+		It("should choose the option that was clicked and ignore clicks elsewhere", func() {
+			chosen := -1
+			renderer.ShowDialog("Your save could not be loaded.", []string{"Keep this game", "Restore backup 2"}, func(choice int) {
+				chosen = choice
+			})
+			renderer.Draw(ebiten.NewImage(640, 480))
+
+			renderer.HandleInput(input.KeyTypeNone, true, false, 0, 0)
+			Expect(chosen).To(Equal(-1))
+			Expect(renderer.IsPopupActive()).To(BeTrue())
+
+			x, y := renderer.dialog.GetOptionPosition(1)
+			renderer.HandleInput(input.KeyTypeNone, true, false, x, y)
+			Expect(chosen).To(Equal(1))
+			Expect(renderer.IsPopupActive()).To(BeFalse())
+		})
+	})
+
 	Describe("Popup input handling", func() {
 		BeforeEach(func() {
 			// Display popup before each test
@@ -288,7 +755,7 @@ var _ = Describe("Renderer", func() {
 
 				// Navigate left from first page should wrap to last page
 				renderer.HandleInput(input.KeyTypeLeft, false, false, 0, 0)
-				Expect(renderer.navigation.GetPage()).To(Equal(1)) // Assuming 2 pages total
+				Expect(renderer.navigation.GetPage()).To(Equal(2)) // Buildings, Upgrades and Market
 			})
 
 			It("should validate cursor position when switching pages", func() {
@@ -380,7 +847,7 @@ var _ = Describe("Renderer", func() {
 		})
 		Context("when isClicked is true", func() {
 			It("should set the page if a tab is clicked", func() {
-				tab1X := testConfig.ScreenWidth/2 + testConfig.ScreenWidth/4
+				tab1X := testConfig.ScreenWidth / 2
 				tabY := 110
 				renderer.handleDecision(true, tab1X, tabY)
 
@@ -419,5 +886,45 @@ var _ = Describe("Renderer", func() {
 		Expect(renderer.manualWork.Items).To(HaveLen(1))
 		Expect(renderer.buildings.Items).To(HaveLen(len(buildingUseCase.buildings)))
 		Expect(renderer.upgrades.Items).To(HaveLen(len(upgradeUseCase.upgrades)))
+		Expect(renderer.market.Items).To(HaveLen(len(marketUseCase.orders)))
+	})
+
+	Describe("Level up", func() {
+		It("should level up the selected building", func() {
+			renderer.Update()
+			renderer.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeLevelUp, false, false, 0, 0)
+			Expect(buildingUseCase.LevelUpBuildingActionCalled).To(BeTrue())
+			Expect(buildingUseCase.levelUpIndex).To(Equal(1))
+			Expect(renderer.GetPopupMessage()).To(Equal("Building leveled up!"))
+		})
+
+		It("should ignore the level up key on manual work", func() {
+			renderer.HandleInput(input.KeyTypeLevelUp, false, false, 0, 0)
+			Expect(buildingUseCase.LevelUpBuildingActionCalled).To(BeFalse())
+			Expect(renderer.IsPopupActive()).To(BeFalse())
+		})
+	})
+
+	Describe("Market page", func() {
+		BeforeEach(func() {
+			renderer.Update()
+			renderer.navigation.SetPage(2)
+		})
+
+		It("should draw the market list and chart without panicking", func() {
+			Expect(func() {
+				renderer.Draw(mockScreen)
+			}).NotTo(Panic())
+			Expect(renderer.market.Visible).To(BeTrue())
+			Expect(renderer.chart.Visible).To(BeTrue())
+		})
+
+		It("should sell coins on decision", func() {
+			renderer.HandleInput(input.KeyTypeDown, false, false, 0, 0)
+			renderer.HandleInput(input.KeyTypeDecision, false, false, 0, 0)
+			Expect(marketUseCase.SellActionCalled).To(BeTrue())
+		})
 	})
 })
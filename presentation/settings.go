@@ -0,0 +1,147 @@
+package presentation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/domain/model"
+	"github.com/kmdkuk/clicker/presentation/components"
+	"github.com/kmdkuk/clicker/presentation/formatter"
+	"github.com/kmdkuk/clicker/presentation/theme"
+)
+
+// Rows of the settings form
+const (
+	settingAutoSave = iota
+	settingNumberFormat
+	settingTheme
+	settingSoundVolume
+	settingConfirmPurchases
+	settingDebugOverlay
+	settingKeyBindings
+)
+
+var numberFormatLabels = map[model.NumberFormat]string{
+	model.NumberFormatShort:      "Short (1.23M)",
+	model.NumberFormatScientific: "Scientific (1.23e6)",
+	model.NumberFormatFull:       "Full (1,234,567)",
+}
+
+var onOff = []string{"Off", "On"}
+
+// settings holds the values behind the options of the settings form
+type settings struct {
+	autoSaveIntervals []time.Duration
+}
+
+// fields returns the rows of the settings form for the preferences.
+// An auto-save interval set on the command line is added to the choices.
+func (s *settings) fields(p *dto.Preferences, themes []theme.Theme, themeIndex int) []components.FormField {
+	s.autoSaveIntervals = slices.Clone(model.AutoSaveIntervals)
+	if !slices.Contains(s.autoSaveIntervals, p.AutoSaveInterval) {
+		s.autoSaveIntervals = append(s.autoSaveIntervals, p.AutoSaveInterval)
+		slices.Sort(s.autoSaveIntervals)
+	}
+	autoSave := components.FormField{Label: "Auto-save", Selected: slices.Index(s.autoSaveIntervals, p.AutoSaveInterval)}
+	for _, interval := range s.autoSaveIntervals {
+		autoSave.Options = append(autoSave.Options, formatInterval(interval))
+	}
+
+	numberFormat := components.FormField{Label: "Number format", Selected: slices.Index(model.NumberFormats, model.NumberFormat(p.NumberFormat))}
+	for _, format := range model.NumberFormats {
+		numberFormat.Options = append(numberFormat.Options, numberFormatLabels[format])
+	}
+
+	themeField := components.FormField{Label: "Theme", Selected: themeIndex}
+	for _, t := range themes {
+		themeField.Options = append(themeField.Options, t.Name)
+	}
+
+	volume := components.FormField{Label: "Sound volume", Selected: p.SoundVolume / model.SoundVolumeStep}
+	for v := 0; v <= model.MaxSoundVolume; v += model.SoundVolumeStep {
+		volume.Options = append(volume.Options, fmt.Sprintf("%d%%", v))
+	}
+
+	return []components.FormField{
+		autoSave,
+		numberFormat,
+		themeField,
+		volume,
+		{Label: "Confirm purchases", Options: onOff, Selected: boolIndex(p.ConfirmPurchases)},
+		{Label: "Debug overlay", Options: onOff, Selected: boolIndex(p.DebugOverlay)},
+		{Label: "Key bindings", Options: []string{"Change"}, Button: true},
+	}
+}
+
+// preferences returns the preferences chosen in the form. The theme is left to the caller,
+// since switching it can fail.
+func (s *settings) preferences(fields []components.FormField, current dto.Preferences) dto.Preferences {
+	p := current
+	p.AutoSaveInterval = s.autoSaveIntervals[fields[settingAutoSave].Selected]
+	p.NumberFormat = string(model.NumberFormats[fields[settingNumberFormat].Selected])
+	p.SoundVolume = fields[settingSoundVolume].Selected * model.SoundVolumeStep
+	p.ConfirmPurchases = fields[settingConfirmPurchases].Selected == 1
+	p.DebugOverlay = fields[settingDebugOverlay].Selected == 1
+	return p
+}
+
+func formatInterval(interval time.Duration) string {
+	switch {
+	case interval <= 0:
+		return "Only on exit"
+	case interval%time.Minute == 0:
+		return fmt.Sprintf("Every %d min", interval/time.Minute)
+	case interval%time.Second == 0:
+		return fmt.Sprintf("Every %d sec", interval/time.Second)
+	default:
+		return fmt.Sprintf("Every %s", interval)
+	}
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// openSettings shows the settings form with the current preferences
+func (r *DefaultRenderer) openSettings() {
+	r.settingsForm.Show(r.settings.fields(r.preferencesUseCase.GetPreferences(), r.themes, r.themeIndex))
+}
+
+// changeSetting applies the row of the settings form that the player changed
+func (r *DefaultRenderer) changeSetting(field int) {
+	switch field {
+	case settingTheme:
+		r.selectTheme(r.settingsForm.Fields[settingTheme].Selected)
+		return
+	case settingKeyBindings:
+		r.openControls()
+		return
+	}
+	r.updatePreferences(r.settings.preferences(r.settingsForm.Fields, *r.preferencesUseCase.GetPreferences()))
+}
+
+// updatePreferences stores the preferences and applies those the renderer shows
+func (r *DefaultRenderer) updatePreferences(p dto.Preferences) {
+	if _, message := r.preferencesUseCase.UpdatePreferencesAction(p); message != "" {
+		r.ShowPopup(message)
+	}
+	formatter.SetNumberFormat(model.NumberFormat(r.preferencesUseCase.GetPreferences().NumberFormat))
+}
+
+// confirmPurchase asks before buying if the player wants to be asked, and buys once confirmed
+func (r *DefaultRenderer) confirmPurchase(question string, buy func()) {
+	if !r.preferencesUseCase.GetPreferences().ConfirmPurchases {
+		buy()
+		return
+	}
+	r.ShowDialog(question, []string{"Yes", "No"}, func(choice int) {
+		if choice == 0 {
+			buy()
+		}
+	})
+}
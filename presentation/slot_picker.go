@@ -0,0 +1,231 @@
+package presentation
+
+import (
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/presentation/components"
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const slotPickerHelp = "[Enter] Play  [N] New  [C] Copy  [R] Rename  [X] Delete"
+
+type SlotUseCase interface {
+	GetSlots() []dto.Slot
+	CreateSlotAction(name string) (bool, string)
+	CopySlotAction(cursor int) (bool, string)
+	RenameSlotAction(cursor int, name string) (bool, string)
+	DeleteSlotAction(cursor int) (bool, string)
+}
+
+// SlotPicker lets the player choose, create, copy, rename and delete save slots before the game starts
+type SlotPicker interface {
+	Update()
+	Draw(screen *ebiten.Image)
+	Resize(width, height int, scale float64)
+	HandleInput(keyType input.KeyType, chars []rune, isClicked bool, mouseX, mouseY int)
+	Selected() (string, bool)
+	IsTyping() bool
+}
+
+type textInputMode int
+
+const (
+	textInputNone textInputMode = iota
+	textInputCreate
+	textInputRename
+)
+
+type DefaultSlotPicker struct {
+	config        *config.Config
+	slotUseCase   SlotUseCase
+	style         *components.Style
+	layout        Layout
+	slots         []dto.Slot
+	cursor        int
+	pendingDelete int // Index of the slot waiting for the delete confirmation, -1 if none
+	textInputMode textInputMode
+	selected      string
+	isSelected    bool
+	// Components for rendering different parts of the UI
+	list      *components.List
+	textInput *components.TextInput
+	popup     *components.Popup
+}
+
+func NewSlotPicker(config *config.Config, slotUseCase SlotUseCase) (SlotPicker, error) {
+	style, _, err := newStyle(loadThemes(config))
+	if err != nil {
+		return nil, err
+	}
+	picker := &DefaultSlotPicker{
+		config:        config,
+		slotUseCase:   slotUseCase,
+		style:         style,
+		pendingDelete: -1,
+		list:          components.NewList(style, true, 0, 0), // Placed by Resize
+		textInput:     components.NewTextInput(style, 0, 0),
+		popup:         components.NewPopup(style),
+	}
+	picker.Resize(config.ScreenWidth, config.ScreenHeight, 1)
+	picker.Update()
+	return picker, nil
+}
+
+// Resize lays the picker out for a screen of width x height device pixels.
+// The slots take the place of the tabs and lists of the game.
+func (p *DefaultSlotPicker) Resize(width, height int, scale float64) {
+	layout := NewLayout(width, height, scale)
+	if layout == p.layout {
+		return
+	}
+	p.layout = layout
+	p.style.Scale = layout.Scale
+	p.textInput.SetPosition(layout.ManualWork.X, layout.ManualWork.Y)
+	viewport := max((layout.Height-layout.Tabs.Y-layout.Display.Y)/p.style.Px(components.ItemHeight), 1)
+	p.list.SetBounds(layout.Tabs.X, layout.Tabs.Y, layout.Width-layout.Tabs.X, viewport)
+}
+
+func (p *DefaultSlotPicker) Update() {
+	p.slots = p.slotUseCase.GetSlots()
+	p.list.Items = components.ConvertSlotToListItems(p.slots)
+	if p.cursor >= len(p.slots) {
+		p.cursor = len(p.slots) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+func (p *DefaultSlotPicker) Draw(screen *ebiten.Image) {
+	screen.Fill(p.style.Colors.Background)
+
+	title := "Select a save slot    " + slotPickerHelp
+	if len(p.slots) == 0 {
+		title = "No save slots yet. Tap or press Enter to start a new game, or press N to name a slot."
+	}
+	txtOp := &text.DrawOptions{}
+	txtOp.GeoM.Translate(float64(p.layout.Display.X), float64(p.layout.Display.Y))
+	txtOp.ColorScale.ScaleWithColor(p.style.Colors.NormalText)
+	text.Draw(screen, title, p.style.Face(p.style.TextSizes.Text), txtOp)
+
+	p.textInput.Draw(screen)
+	p.list.Draw(screen, p.cursor)
+
+	if p.popup.IsActive() {
+		p.popup.Draw(screen)
+	}
+}
+
+// HandleInput handles a key, or a click or tap at mouseX, mouseY.
+// A tap selects the slot under it and plays it when it was already selected.
+func (p *DefaultSlotPicker) HandleInput(keyType input.KeyType, chars []rune, isClicked bool, mouseX, mouseY int) {
+	// Popup handling takes priority
+	if p.popup.IsActive() {
+		p.popup.HandleInput(keyType, isClicked)
+		return
+	}
+
+	if p.textInput.Active {
+		if p.textInput.HandleInput(keyType, chars) {
+			p.submitTextInput()
+		}
+		return
+	}
+
+	if keyType != input.KeyTypeDelete {
+		p.pendingDelete = -1
+	}
+	if isClicked && keyType == input.KeyTypeNone {
+		keyType = p.handleClick(mouseX, mouseY)
+	}
+
+	switch keyType {
+	case input.KeyTypeUp:
+		if len(p.slots) > 0 {
+			p.cursor = (p.cursor - 1 + len(p.slots)) % len(p.slots)
+		}
+	case input.KeyTypeDown:
+		if len(p.slots) > 0 {
+			p.cursor = (p.cursor + 1) % len(p.slots)
+		}
+	case input.KeyTypeDecision:
+		p.selected = config.DefaultSlot
+		if p.cursor < len(p.slots) {
+			p.selected = p.slots[p.cursor].Name
+		}
+		p.isSelected = true
+	case input.KeyTypeCreate:
+		p.textInputMode = textInputCreate
+		p.textInput.Start("New slot name:", "")
+	case input.KeyTypeCopy:
+		p.showResult(p.slotUseCase.CopySlotAction(p.cursor))
+	case input.KeyTypeRename:
+		if p.cursor < len(p.slots) {
+			p.textInputMode = textInputRename
+			p.textInput.Start("Rename to:", p.slots[p.cursor].Name)
+		}
+	case input.KeyTypeDelete:
+		if p.cursor >= len(p.slots) {
+			return
+		}
+		if p.pendingDelete != p.cursor {
+			p.pendingDelete = p.cursor
+			p.popup.Show("Press X again to delete " + p.slots[p.cursor].Name + "!")
+			return
+		}
+		p.pendingDelete = -1
+		p.showResult(p.slotUseCase.DeleteSlotAction(p.cursor))
+	}
+}
+
+// handleClick moves the cursor to the tapped slot and returns KeyTypeDecision
+// if the slot was already selected, or if there are no slots to tap
+func (p *DefaultSlotPicker) handleClick(mouseX, mouseY int) input.KeyType {
+	if len(p.slots) == 0 {
+		return input.KeyTypeDecision
+	}
+	cursor := p.list.GetHoverCursor(p.layout.Width, mouseX, mouseY)
+	if cursor == -1 {
+		return input.KeyTypeNone
+	}
+	if cursor == p.cursor {
+		return input.KeyTypeDecision
+	}
+	p.cursor = cursor
+	return input.KeyTypeNone
+}
+
+// GetSlotPosition returns the middle of the row of the slot (for testing)
+func (p *DefaultSlotPicker) GetSlotPosition(index int) (int, int) {
+	return p.list.GetItemPosition(p.layout.Width, index)
+}
+
+// Selected returns the slot chosen by the player
+func (p *DefaultSlotPicker) Selected() (string, bool) {
+	return p.selected, p.isSelected
+}
+
+// IsTyping reports whether a slot name is being typed, so that letters do not trigger actions
+func (p *DefaultSlotPicker) IsTyping() bool {
+	return p.textInput.Active
+}
+
+func (p *DefaultSlotPicker) submitTextInput() {
+	switch p.textInputMode {
+	case textInputCreate:
+		p.showResult(p.slotUseCase.CreateSlotAction(p.textInput.Value))
+	case textInputRename:
+		p.showResult(p.slotUseCase.RenameSlotAction(p.cursor, p.textInput.Value))
+	}
+	p.textInputMode = textInputNone
+}
+
+func (p *DefaultSlotPicker) showResult(_ bool, message string) {
+	p.Update()
+	if message != "" {
+		p.popup.Show(message)
+	}
+}
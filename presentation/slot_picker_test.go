@@ -0,0 +1,177 @@
+package presentation
+
+import (
+	"github.com/kmdkuk/clicker/application/dto"
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/presentation/input"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type MockSlotUseCase struct {
+	Slots        []dto.Slot
+	CreatedName  string
+	CopiedIndex  int
+	RenamedIndex int
+	RenamedName  string
+	DeletedIndex int
+}
+
+func (m *MockSlotUseCase) GetSlots() []dto.Slot {
+	return m.Slots
+}
+
+func (m *MockSlotUseCase) CreateSlotAction(name string) (bool, string) {
+	m.CreatedName = name
+	m.Slots = append(m.Slots, dto.Slot{Name: name})
+	return true, "Slot created!"
+}
+
+func (m *MockSlotUseCase) CopySlotAction(cursor int) (bool, string) {
+	m.CopiedIndex = cursor
+	return true, "Slot copied!"
+}
+
+func (m *MockSlotUseCase) RenameSlotAction(cursor int, name string) (bool, string) {
+	m.RenamedIndex = cursor
+	m.RenamedName = name
+	return true, "Slot renamed!"
+}
+
+func (m *MockSlotUseCase) DeleteSlotAction(cursor int) (bool, string) {
+	m.DeletedIndex = cursor
+	m.Slots = append(m.Slots[:cursor], m.Slots[cursor+1:]...)
+	return true, "Slot deleted!"
+}
+
+var _ = Describe("SlotPicker", func() {
+	var (
+		slotUseCase *MockSlotUseCase
+		picker      *DefaultSlotPicker
+	)
+
+	closePopup := func() {
+		Expect(picker.popup.IsActive()).To(BeTrue())
+		picker.HandleInput(input.KeyTypeDecision, nil, false, 0, 0)
+	}
+
+	BeforeEach(func() {
+		slotUseCase = &MockSlotUseCase{
+			Slots:        []dto.Slot{{Name: config.DefaultSlot}, {Name: "balance"}},
+			CopiedIndex:  -1,
+			DeletedIndex: -1,
+		}
+		p, err := NewSlotPicker(config.NewConfig(), slotUseCase)
+		Expect(err).NotTo(HaveOccurred())
+		picker = p.(*DefaultSlotPicker)
+	})
+
+	It("should select the slot under the cursor", func() {
+		picker.HandleInput(input.KeyTypeDown, nil, false, 0, 0)
+		picker.HandleInput(input.KeyTypeDecision, nil, false, 0, 0)
+
+		slot, ok := picker.Selected()
+		Expect(ok).To(BeTrue())
+		Expect(slot).To(Equal("balance"))
+	})
+
+	It("should select the default slot if there are no slots", func() {
+		slotUseCase.Slots = nil
+		picker.Update()
+		picker.HandleInput(input.KeyTypeDecision, nil, false, 0, 0)
+
+		slot, ok := picker.Selected()
+		Expect(ok).To(BeTrue())
+		Expect(slot).To(Equal(config.DefaultSlot))
+	})
+
+	It("should create a slot with the typed name", func() {
+		picker.HandleInput(input.KeyTypeCreate, []rune("n"), false, 0, 0)
+		Expect(picker.IsTyping()).To(BeTrue())
+		picker.HandleInput(input.KeyTypeNone, []rune("test"), false, 0, 0)
+		picker.HandleInput(input.KeyTypeDecision, nil, false, 0, 0)
+		Expect(picker.IsTyping()).To(BeFalse())
+
+		Expect(slotUseCase.CreatedName).To(Equal("test"))
+		closePopup()
+		Expect(picker.slots).To(HaveLen(3))
+		_, ok := picker.Selected()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should rename the selected slot", func() {
+		picker.HandleInput(input.KeyTypeDown, nil, false, 0, 0)
+		picker.HandleInput(input.KeyTypeRename, []rune("r"), false, 0, 0)
+		picker.HandleInput(input.KeyTypeBackspace, nil, false, 0, 0)
+		picker.HandleInput(input.KeyTypeNone, []rune("x"), false, 0, 0)
+		picker.HandleInput(input.KeyTypeDecision, nil, false, 0, 0)
+
+		Expect(slotUseCase.RenamedIndex).To(Equal(1))
+		Expect(slotUseCase.RenamedName).To(Equal("balancx"))
+	})
+
+	It("should copy the selected slot", func() {
+		picker.HandleInput(input.KeyTypeCopy, nil, false, 0, 0)
+		Expect(slotUseCase.CopiedIndex).To(Equal(0))
+		closePopup()
+	})
+
+	It("should ask for a confirmation before deleting", func() {
+		picker.HandleInput(input.KeyTypeDelete, nil, false, 0, 0)
+		Expect(slotUseCase.DeletedIndex).To(Equal(-1))
+		closePopup()
+
+		picker.HandleInput(input.KeyTypeDelete, nil, false, 0, 0)
+		Expect(slotUseCase.DeletedIndex).To(Equal(0))
+		Expect(picker.slots).To(HaveLen(1))
+	})
+
+	It("should cancel the confirmation on another key", func() {
+		picker.HandleInput(input.KeyTypeDelete, nil, false, 0, 0)
+		closePopup()
+		picker.HandleInput(input.KeyTypeDown, nil, false, 0, 0)
+		picker.HandleInput(input.KeyTypeDelete, nil, false, 0, 0)
+
+		Expect(slotUseCase.DeletedIndex).To(Equal(-1))
+	})
+
+	Context("when a slot is tapped", func() {
+		tap := func(index int) {
+			x, y := picker.GetSlotPosition(index)
+			picker.HandleInput(input.KeyTypeNone, nil, true, x, y)
+		}
+
+		It("should select the tapped slot and play it on a second tap", func() {
+			tap(1)
+			_, ok := picker.Selected()
+			Expect(ok).To(BeFalse())
+			Expect(picker.cursor).To(Equal(1))
+
+			tap(1)
+			slot, ok := picker.Selected()
+			Expect(ok).To(BeTrue())
+			Expect(slot).To(Equal("balance"))
+		})
+
+		It("should ignore a tap outside the slots", func() {
+			x, _ := picker.GetSlotPosition(0)
+			_, y := picker.GetSlotPosition(len(slotUseCase.Slots) + 1)
+			picker.HandleInput(input.KeyTypeNone, nil, true, x, y)
+
+			_, ok := picker.Selected()
+			Expect(ok).To(BeFalse())
+			Expect(picker.cursor).To(Equal(0))
+		})
+
+		It("should start the default slot on a tap if there are no slots", func() {
+			slotUseCase.Slots = nil
+			picker.Update()
+			picker.HandleInput(input.KeyTypeNone, nil, true, 0, 0)
+
+			slot, ok := picker.Selected()
+			Expect(ok).To(BeTrue())
+			Expect(slot).To(Equal(config.DefaultSlot))
+		})
+	})
+})
@@ -0,0 +1,278 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/kmdkuk/clicker/assets/fonts"
+)
+
+// Built-in font names a theme can use instead of a font file
+const (
+	FontBebasNeue = "bebas-neue"
+	FontGoRegular = "go-regular"
+	FontGoMono    = "go-mono"
+)
+
+var builtinFonts = map[string][]byte{
+	FontBebasNeue: fonts.BebasNeueRegular_ttf,
+	FontGoRegular: goregular.TTF,
+	FontGoMono:    gomono.TTF,
+}
+
+var ErrInvalidTheme = errors.New("invalid theme")
+
+// Theme describes how the UI looks: its colors, font, text sizes and padding
+type Theme struct {
+	Name      string    `json:"name"`
+	Font      string    `json:"font"` // Built-in font name or path to a TTF/OTF file
+	Colors    Colors    `json:"colors"`
+	TextSizes TextSizes `json:"text_sizes"`
+	Padding   Padding   `json:"padding"`
+}
+
+type Colors struct {
+	Background      Color `json:"background"`
+	NormalBg        Color `json:"normal_bg"`
+	NormalText      Color `json:"normal_text"`
+	SelectedBg      Color `json:"selected_bg"`
+	SelectedText    Color `json:"selected_text"`
+	ScrollbarTrack  Color `json:"scrollbar_track"`
+	ScrollbarHandle Color `json:"scrollbar_handle"`
+	ChartLine       Color `json:"chart_line"`
+	PopupBg         Color `json:"popup_bg"`
+	PopupBorder     Color `json:"popup_border"`
+	PopupText       Color `json:"popup_text"`
+	Hint            Color `json:"hint"`            // Key hints at the bottom of popups and dialogs
+	DialogSelected  Color `json:"dialog_selected"` // Chosen option of a dialog
+	ToastBg         Color `json:"toast_bg"`
+	ToastText       Color `json:"toast_text"`
+}
+
+type TextSizes struct {
+	Text   float64 `json:"text"` // Lists, tabs and the money display
+	Popup  float64 `json:"popup"`
+	Dialog float64 `json:"dialog"`
+	Toast  float64 `json:"toast"`
+}
+
+type Padding struct {
+	ItemText int `json:"item_text"` // Between the text and the background of a list item
+	Popup    int `json:"popup"`
+	Dialog   int `json:"dialog"`
+	Toast    int `json:"toast"`
+}
+
+// Color is a color written as "#rrggbb" or "#rrggbbaa" in theme files
+type Color color.RGBA
+
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.RGBA(c).RGBA()
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A))
+}
+
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	hex, ok := strings.CutPrefix(s, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return fmt.Errorf("invalid color %q: want #rrggbb or #rrggbbaa", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	var rgba [4]uint8
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &rgba[0], &rgba[1], &rgba[2], &rgba[3]); err != nil {
+		return fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	*c = Color{R: rgba[0], G: rgba[1], B: rgba[2], A: rgba[3]}
+	return nil
+}
+
+// Dark is the default theme
+func Dark() Theme {
+	return Theme{
+		Name: "Dark",
+		Font: FontBebasNeue,
+		Colors: Colors{
+			Background:      Color{R: 0, G: 0, B: 0, A: 255},
+			NormalBg:        Color{R: 40, G: 40, B: 40, A: 120},
+			NormalText:      Color{R: 200, G: 200, B: 200, A: 255},
+			SelectedBg:      Color{R: 50, G: 100, B: 200, A: 160},
+			SelectedText:    Color{R: 255, G: 255, B: 255, A: 255},
+			ScrollbarTrack:  Color{R: 80, G: 80, B: 80, A: 180},
+			ScrollbarHandle: Color{R: 180, G: 180, B: 180, A: 255},
+			ChartLine:       Color{R: 240, G: 180, B: 40, A: 255},
+			PopupBg:         Color{R: 50, G: 50, B: 70, A: 240},
+			PopupBorder:     Color{R: 80, G: 80, B: 120, A: 255},
+			PopupText:       Color{R: 240, G: 240, B: 240, A: 255},
+			Hint:            Color{R: 180, G: 180, B: 180, A: 180},
+			DialogSelected:  Color{R: 255, G: 215, B: 0, A: 255},
+			ToastBg:         Color{R: 50, G: 50, B: 70, A: 220},
+			ToastText:       Color{R: 240, G: 240, B: 240, A: 255},
+		},
+		TextSizes: TextSizes{Text: 24, Popup: 18, Dialog: 16, Toast: 18},
+		Padding:   Padding{ItemText: 5, Popup: 15, Dialog: 20, Toast: 10},
+	}
+}
+
+// Light has dark text on a light background
+func Light() Theme {
+	t := Dark()
+	t.Name = "Light"
+	t.Colors = Colors{
+		Background:      Color{R: 245, G: 245, B: 240, A: 255},
+		NormalBg:        Color{R: 215, G: 215, B: 210, A: 200},
+		NormalText:      Color{R: 30, G: 30, B: 30, A: 255},
+		SelectedBg:      Color{R: 50, G: 100, B: 200, A: 200},
+		SelectedText:    Color{R: 255, G: 255, B: 255, A: 255},
+		ScrollbarTrack:  Color{R: 200, G: 200, B: 200, A: 180},
+		ScrollbarHandle: Color{R: 110, G: 110, B: 110, A: 255},
+		ChartLine:       Color{R: 200, G: 110, B: 0, A: 255},
+		PopupBg:         Color{R: 252, G: 252, B: 255, A: 245},
+		PopupBorder:     Color{R: 120, G: 120, B: 160, A: 255},
+		PopupText:       Color{R: 20, G: 20, B: 20, A: 255},
+		Hint:            Color{R: 90, G: 90, B: 90, A: 200},
+		DialogSelected:  Color{R: 180, G: 80, B: 0, A: 255},
+		ToastBg:         Color{R: 60, G: 60, B: 80, A: 220},
+		ToastText:       Color{R: 240, G: 240, B: 240, A: 255},
+	}
+	return t
+}
+
+// HighContrast uses opaque colors with the strongest contrast and a font with lower case letters
+func HighContrast() Theme {
+	t := Dark()
+	t.Name = "High Contrast"
+	t.Font = FontGoRegular
+	t.Colors = Colors{
+		Background:      Color{R: 0, G: 0, B: 0, A: 255},
+		NormalBg:        Color{R: 0, G: 0, B: 0, A: 255},
+		NormalText:      Color{R: 255, G: 255, B: 255, A: 255},
+		SelectedBg:      Color{R: 255, G: 255, B: 0, A: 255},
+		SelectedText:    Color{R: 0, G: 0, B: 0, A: 255},
+		ScrollbarTrack:  Color{R: 255, G: 255, B: 255, A: 255},
+		ScrollbarHandle: Color{R: 255, G: 255, B: 0, A: 255},
+		ChartLine:       Color{R: 0, G: 255, B: 255, A: 255},
+		PopupBg:         Color{R: 0, G: 0, B: 0, A: 255},
+		PopupBorder:     Color{R: 255, G: 255, B: 255, A: 255},
+		PopupText:       Color{R: 255, G: 255, B: 255, A: 255},
+		Hint:            Color{R: 255, G: 255, B: 255, A: 255},
+		DialogSelected:  Color{R: 255, G: 255, B: 0, A: 255},
+		ToastBg:         Color{R: 255, G: 255, B: 255, A: 255},
+		ToastText:       Color{R: 0, G: 0, B: 0, A: 255},
+	}
+	t.TextSizes = TextSizes{Text: 20, Popup: 18, Dialog: 16, Toast: 18}
+	return t
+}
+
+// Builtin returns the themes shipped with the game, the default first
+func Builtin() []Theme {
+	return []Theme{Dark(), Light(), HighContrast()}
+}
+
+// Find returns the built-in theme with the name, ignoring case and "-" for spaces
+func Find(name string) (Theme, bool) {
+	key := normalizeName(name)
+	for _, t := range Builtin() {
+		if normalizeName(t.Name) == key {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "-", " "))
+}
+
+// Load reads a custom theme from a JSON file.
+// A font file in the theme is relative to the theme file.
+func Load(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, builtin := builtinFonts[t.Font]; !builtin && !filepath.IsAbs(t.Font) {
+		t.Font = filepath.Join(filepath.Dir(path), t.Font)
+	}
+	return t, nil
+}
+
+// Parse decodes a custom theme. Fields the theme leaves out are taken from its "base"
+// built-in theme, or from the dark theme if it has none.
+func Parse(data []byte) (Theme, error) {
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Theme{}, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	t := Dark()
+	if header.Base != "" {
+		base, ok := Find(header.Base)
+		if !ok {
+			return Theme{}, fmt.Errorf("%w: unknown base theme %q", ErrInvalidTheme, header.Base)
+		}
+		t = base
+	}
+	name := t.Name
+	t.Name = ""
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	if t.Name == "" {
+		t.Name = "Custom " + name
+	}
+	if err := t.Validate(); err != nil {
+		return Theme{}, err
+	}
+	return t, nil
+}
+
+// Validate checks that the text can be drawn with the theme
+func (t Theme) Validate() error {
+	if t.Font == "" {
+		return fmt.Errorf("%w: no font", ErrInvalidTheme)
+	}
+	for _, size := range []float64{t.TextSizes.Text, t.TextSizes.Popup, t.TextSizes.Dialog, t.TextSizes.Toast} {
+		if size <= 0 {
+			return fmt.Errorf("%w: text size must be positive, got %v", ErrInvalidTheme, size)
+		}
+	}
+	for _, padding := range []int{t.Padding.ItemText, t.Padding.Popup, t.Padding.Dialog, t.Padding.Toast} {
+		if padding < 0 {
+			return fmt.Errorf("%w: padding must not be negative, got %d", ErrInvalidTheme, padding)
+		}
+	}
+	return nil
+}
+
+// FontData returns the font of the theme, reading it from disk if it is not built in
+func (t Theme) FontData() ([]byte, error) {
+	if data, ok := builtinFonts[t.Font]; ok {
+		return data, nil
+	}
+	data, err := os.ReadFile(t.Font)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,127 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Theme", func() {
+	It("should ship valid built-in themes with their fonts", func() {
+		names := map[string]bool{}
+		for _, t := range Builtin() {
+			Expect(t.Validate()).To(Succeed())
+			Expect(names).NotTo(HaveKey(t.Name))
+			names[t.Name] = true
+			data, err := t.FontData()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).NotTo(BeEmpty())
+		}
+		Expect(Builtin()[0].Name).To(Equal("Dark"))
+	})
+
+	DescribeTable("Find",
+		func(name, expected string) {
+			t, ok := Find(name)
+			Expect(ok).To(BeTrue())
+			Expect(t.Name).To(Equal(expected))
+		},
+		Entry("exact name", "Light", "Light"),
+		Entry("lower case", "dark", "Dark"),
+		Entry("dashes for spaces", "high-contrast", "High Contrast"),
+	)
+
+	It("should not find unknown themes", func() {
+		_, ok := Find("neon")
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("Color", func() {
+		It("should round trip through JSON", func() {
+			c := Color{R: 1, G: 0x20, B: 0xff, A: 0x80}
+			data, err := json.Marshal(c)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`"#0120ff80"`))
+
+			var decoded Color
+			Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(c))
+		})
+
+		It("should default to opaque without alpha", func() {
+			var c Color
+			Expect(json.Unmarshal([]byte(`"#102030"`), &c)).To(Succeed())
+			Expect(c).To(Equal(Color{R: 0x10, G: 0x20, B: 0x30, A: 0xff}))
+		})
+
+		It("should reject malformed colors", func() {
+			var c Color
+			Expect(json.Unmarshal([]byte(`"red"`), &c)).NotTo(Succeed())
+			Expect(json.Unmarshal([]byte(`"#12345"`), &c)).NotTo(Succeed())
+			Expect(json.Unmarshal([]byte(`"#gg0000"`), &c)).NotTo(Succeed())
+		})
+	})
+
+	Describe("Parse", func() {
+		It("should take the fields the theme leaves out from its base", func() {
+			t, err := Parse([]byte(`{"name": "Solarized", "base": "light", "colors": {"background": "#fdf6e3"}, "text_sizes": {"text": 20}}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Name).To(Equal("Solarized"))
+			Expect(t.Colors.Background).To(Equal(Color{R: 0xfd, G: 0xf6, B: 0xe3, A: 0xff}))
+			Expect(t.Colors.NormalText).To(Equal(Light().Colors.NormalText))
+			Expect(t.TextSizes.Text).To(Equal(20.0))
+			Expect(t.TextSizes.Popup).To(Equal(Light().TextSizes.Popup))
+			Expect(t.Font).To(Equal(FontBebasNeue))
+		})
+
+		It("should name a theme without a name after its base", func() {
+			t, err := Parse([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Name).To(Equal("Custom Dark"))
+		})
+
+		DescribeTable("invalid themes",
+			func(data string) {
+				_, err := Parse([]byte(data))
+				Expect(errors.Is(err, ErrInvalidTheme)).To(BeTrue())
+			},
+			Entry("not JSON", `{"name":`),
+			Entry("unknown base", `{"base": "neon"}`),
+			Entry("bad color", `{"colors": {"background": "black"}}`),
+			Entry("zero text size", `{"text_sizes": {"toast": 0}}`),
+			Entry("negative padding", `{"padding": {"dialog": -1}}`),
+		)
+	})
+
+	Describe("Load", func() {
+		It("should resolve a font file next to the theme file", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "font.ttf"), []byte("font"), 0644)).To(Succeed())
+			path := filepath.Join(dir, "theme.json")
+			Expect(os.WriteFile(path, []byte(`{"name": "Mine", "font": "font.ttf"}`), 0644)).To(Succeed())
+
+			t, err := Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Font).To(Equal(filepath.Join(dir, "font.ttf")))
+			Expect(t.FontData()).To(Equal([]byte("font")))
+		})
+
+		It("should keep built-in fonts", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "theme.json")
+			Expect(os.WriteFile(path, []byte(`{"font": "go-mono"}`), 0644)).To(Succeed())
+
+			t, err := Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Font).To(Equal(FontGoMono))
+		})
+
+		It("should fail on a missing file", func() {
+			_, err := Load(filepath.Join(GinkgoT().TempDir(), "missing.json"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
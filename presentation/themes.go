@@ -0,0 +1,42 @@
+package presentation
+
+import (
+	"log/slog"
+
+	"github.com/kmdkuk/clicker/config"
+	"github.com/kmdkuk/clicker/presentation/components"
+	"github.com/kmdkuk/clicker/presentation/theme"
+)
+
+// loadThemes returns the themes the player can switch between and the index of the one in config.Theme.
+// A theme that is not built in is loaded from the file and added after the built-in ones.
+func loadThemes(config *config.Config) ([]theme.Theme, int) {
+	themes := theme.Builtin()
+	if config.Theme == "" {
+		return themes, 0
+	}
+	if t, ok := theme.Find(config.Theme); ok {
+		for i := range themes {
+			if themes[i].Name == t.Name {
+				return themes, i
+			}
+		}
+	}
+	custom, err := theme.Load(config.Theme)
+	if err != nil {
+		slog.Warn("Failed to load the theme, using the default one", "theme", config.Theme, "error", err)
+		return themes, 0
+	}
+	return append(themes, custom), len(themes)
+}
+
+// newStyle creates the style for the theme, falling back to the default theme if its font cannot be loaded
+func newStyle(themes []theme.Theme, index int) (*components.Style, int, error) {
+	style, err := components.NewStyle(themes[index])
+	if err == nil || index == 0 {
+		return style, index, err
+	}
+	slog.Warn("Failed to load the font of the theme, using the default one", "theme", themes[index].Name, "error", err)
+	style, err = components.NewStyle(themes[0])
+	return style, 0, err
+}